@@ -0,0 +1,228 @@
+// Command loadbot load-tests the game server: it opens N concurrent
+// WebSocket connections that queue up, play simulated runs with a realistic
+// score cadence, and die after a while, over and over until the run ends.
+// Along the way each bot measures request/reply latency with TIME_SYNC and
+// the tool prints percentiles at the end, so it doubles as a reference
+// client implementation of the protocol.
+//
+// Usage:
+//
+//	go run ./cmd/loadbot -addr ws://localhost:8080/ws -n 100 -duration 60s
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+func main() {
+	addr := flag.String("addr", "ws://localhost:8080/ws", "WebSocket endpoint to hammer")
+	n := flag.Int("n", 10, "number of concurrent bot connections")
+	duration := flag.Duration("duration", 30*time.Second, "how long to keep playing")
+	flag.Parse()
+
+	stats := &stats{}
+	deadline := time.Now().Add(*duration)
+	var wg sync.WaitGroup
+	for i := 0; i < *n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Stagger the dials so the server doesn't see one thundering herd.
+			time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
+			runBot(*addr, fmt.Sprintf("loadbot_%d", i), deadline, stats)
+		}(i)
+	}
+	wg.Wait()
+	stats.report(os.Stdout)
+}
+
+// stats aggregates results across every bot.
+type stats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	games     atomic.Int64
+	errors    atomic.Int64
+}
+
+func (s *stats) addLatency(d time.Duration) {
+	s.mu.Lock()
+	s.latencies = append(s.latencies, d)
+	s.mu.Unlock()
+}
+
+// report prints games played, error count, and latency percentiles.
+func (s *stats) report(w *os.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(w, "games finished: %d\n", s.games.Load())
+	fmt.Fprintf(w, "errors:         %d\n", s.errors.Load())
+	if len(s.latencies) == 0 {
+		fmt.Fprintln(w, "no latency samples collected")
+		return
+	}
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	pct := func(p float64) time.Duration {
+		idx := int(p * float64(len(s.latencies)-1))
+		return s.latencies[idx]
+	}
+	fmt.Fprintf(w, "latency samples: %d\n", len(s.latencies))
+	fmt.Fprintf(w, "  p50: %v\n  p95: %v\n  p99: %v\n  max: %v\n",
+		pct(0.50), pct(0.95), pct(0.99), s.latencies[len(s.latencies)-1])
+}
+
+// runBot is one bot's connection lifetime: queue, play, die, repeat until
+// the deadline passes.
+func runBot(addr, name string, deadline time.Time, st *stats) {
+	conn, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		log.Printf("%s: dial: %v", name, err)
+		st.errors.Add(1)
+		return
+	}
+	defer conn.Close()
+	bot := &bot{name: name, conn: conn, stats: st}
+	for time.Now().Before(deadline) {
+		if err := bot.playOneGame(deadline); err != nil {
+			log.Printf("%s: %v", name, err)
+			st.errors.Add(1)
+			return
+		}
+	}
+}
+
+// bot drives a single connection through the client protocol.
+type bot struct {
+	name  string
+	conn  *websocket.Conn
+	stats *stats
+	// seq numbers score submissions across the connection's lifetime; the
+	// server rejects reuse, so it never resets between games.
+	seq int64
+	// scoreKey is the current room's signing key from GAME_START.
+	scoreKey string
+	roomID   string
+}
+
+func (b *bot) send(msgType string, payload interface{}) error {
+	msg := ws.Message{Type: msgType}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", msgType, err)
+		}
+		msg.Payload = raw
+	}
+	return b.conn.WriteJSON(msg)
+}
+
+// sendScore signs one score submission with the room's key and stamps the
+// next seq on the envelope.
+func (b *bot) sendScore(msgType string, score int) error {
+	b.seq++
+	raw, err := json.Marshal(ws.UpdateScorePayload{
+		Score: score,
+		Sig:   ws.SignScore(b.scoreKey, b.roomID, score, b.seq),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", msgType, err)
+	}
+	return b.conn.WriteJSON(ws.Message{Type: msgType, Seq: b.seq, Payload: raw})
+}
+
+// waitFor reads frames until one of the wanted type arrives, feeding
+// TIME_SYNC replies into the latency samples along the way.
+func (b *bot) waitFor(msgType string, out interface{}, timeout time.Duration) error {
+	b.conn.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		var msg ws.Message
+		if err := b.conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("waiting for %s: %w", msgType, err)
+		}
+		switch msg.Type {
+		case msgType:
+			if out == nil {
+				return nil
+			}
+			return json.Unmarshal(msg.Payload, out)
+		case ws.MsgTimeSync:
+			var p ws.TimeSyncPayload
+			if err := json.Unmarshal(msg.Payload, &p); err == nil {
+				b.stats.addLatency(time.Since(time.UnixMilli(p.ClientTime)))
+			}
+		case ws.MsgError:
+			var p ws.ErrorPayload
+			json.Unmarshal(msg.Payload, &p)
+			return fmt.Errorf("server error %s: %s", p.Code, p.Message)
+		}
+	}
+}
+
+// playOneGame queues, readies up, scores at a realistic cadence, and dies.
+func (b *bot) playOneGame(deadline time.Time) error {
+	if err := b.send(ws.MsgJoinQueue, map[string]string{"name": b.name}); err != nil {
+		return err
+	}
+	var start ws.GameStartPayload
+	// Queueing can take a while; the server falls back to a bot opponent
+	// eventually, so wait generously.
+	if err := b.waitFor(ws.MsgGameStart, &start, time.Until(deadline)+time.Minute); err != nil {
+		return err
+	}
+	b.roomID, b.scoreKey = start.RoomID, start.ScoreKey
+	if err := b.send(ws.MsgReady, nil); err != nil {
+		return err
+	}
+	var cd ws.CountdownPayload
+	if err := b.waitFor(ws.MsgCountdown, &cd, 30*time.Second); err != nil {
+		return err
+	}
+	startAt := time.UnixMilli(cd.StartAt)
+	time.Sleep(time.Until(startAt))
+
+	// Score just under the base points rate, updating twice a second like a
+	// real client, and measure latency with a TIME_SYNC every second.
+	runFor := time.Duration(5+rand.Intn(15)) * time.Second
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	score := 0
+	lastSync := time.Time{}
+	for range ticker.C {
+		elapsed := time.Since(startAt)
+		if elapsed >= runFor {
+			break
+		}
+		score = int(elapsed.Seconds() * 9)
+		if err := b.sendScore(ws.MsgUpdateScore, score); err != nil {
+			return err
+		}
+		if time.Since(lastSync) >= time.Second {
+			lastSync = time.Now()
+			if err := b.send(ws.MsgTimeSync, ws.TimeSyncPayload{ClientTime: lastSync.UnixMilli()}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := b.sendScore(ws.MsgPlayerDied, score); err != nil {
+		return err
+	}
+	// The opponent (often a server bot) may keep running for a while before
+	// the match resolves.
+	if err := b.waitFor(ws.MsgGameOver, nil, 5*time.Minute); err != nil {
+		return err
+	}
+	b.stats.games.Add(1)
+	return nil
+}
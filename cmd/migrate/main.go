@@ -0,0 +1,64 @@
+// Command migrate applies or rolls back database migrations without
+// starting the game server.
+//
+// Usage:
+//
+//	migrate up
+//	migrate down [steps]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/config"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/logging"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx := context.Background()
+	pool, err := db.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	switch os.Args[1] {
+	case "up":
+		err = db.Migrate(ctx, pool)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				usage()
+			}
+		}
+		err = db.MigrateDown(ctx, pool, steps)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | migrate down [steps]")
+	os.Exit(2)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/analytics"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/api"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/config"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/events"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/flags"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/grpcapi"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/logging"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/webhook"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// version identifies the build; stamp it with
+// -ldflags "-X main.version=<tag>".
+var version = "dev"
+
+func main() {
+	devMode := flag.Bool("dev", false, "run entirely in memory, without Postgres or Redis")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
+	ws.Apply(cfg)
+	ctx := context.Background()
+
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		slog.Error("tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(ctx)
+
+	// Dev mode trades durability for a zero-dependency contributor setup:
+	// everything lives in this process, so scores and the matchmaking queue
+	// vanish on restart.
+	var pool *pgxpool.Pool
+	if *devMode {
+		slog.Info("dev mode: in-memory storage, no Postgres or Redis")
+	} else {
+		pool, err = db.Connect(ctx, cfg.DatabaseURL)
+		if err != nil {
+			slog.Error("connect to database failed", "error", err)
+			os.Exit(1)
+		}
+		defer pool.Close()
+		if err := db.Migrate(ctx, pool); err != nil {
+			slog.Error("run migrations failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	var rdb redis.UniversalClient
+	if !*devMode && cfg.RedisURL != "" {
+		rdb, err = db.ConnectRedis(ctx, cfg.RedisURL)
+		if err != nil {
+			slog.Error("connect to redis failed", "error", err)
+			os.Exit(1)
+		}
+		defer rdb.Close()
+	}
+
+	hub := ws.NewHub()
+	validator := game.NewValidator()
+	sessions := ws.NewSessionStore()
+	loops := game.NewLoopManager()
+	matchmaker := ws.NewMatchmaker(hub, validator, sessions, loops)
+	matchmaker.SetRoyaleSize(cfg.RoyaleSize)
+	matchmaker.SetQueueStore(ws.NewQueueStore(rdb))
+	matchmaker.SetOpponentStore(ws.NewOpponentStore(rdb))
+	sessions.SetTokenStore(ws.NewTokenStore(rdb))
+	db.ScoreEventRetention = cfg.ScoreAuditRetention
+	var store db.Store
+	if *devMode {
+		store = db.NewMemStore()
+	} else {
+		pg := db.NewPgStore(pool)
+		pg.SetDailyScoreCap(cfg.MaxDailyScores)
+		defer pg.Close()
+		store = pg
+	}
+	handler := ws.NewHandler(hub, matchmaker, validator, sessions, loops, store)
+	handler.SetRoomBus(ws.NewRoomBus(rdb, hub))
+	handler.RecoverCheckpoints(ctx)
+	bus := events.NewBus()
+	handler.SetEventBus(bus)
+	flagStore := flags.NewStore(rdb)
+	handler.SetFlagStore(flagStore)
+	stream := api.NewLeaderboardStream(store)
+	lbCache := api.NewLeaderboardCache(rdb)
+	bus.Subscribe(events.KindPlayerDied, func(e events.Event) {
+		died := e.(events.PlayerDied)
+		stream.PublishScore(died.PlayerID, died.PlayerName, died.Score)
+		lbCache.Invalidate(ctx)
+	})
+	if rdb != nil && cfg.EventStream != "" {
+		publisher := analytics.NewPublisher(rdb, cfg.EventStream, cfg.EventStreamMaxLen)
+		bus.SubscribeAll(publisher.Publish)
+	}
+	if len(cfg.WebhookURLs) > 0 {
+		notifier := webhook.NewNotifier(cfg.WebhookURLs, cfg.WebhookSecret)
+		bus.Subscribe(events.KindMatchFinished, func(e events.Event) { notifier.Publish(e) })
+	}
+	status := api.NewStatus(hub, matchmaker, version)
+	bus.Subscribe(events.KindMatchFinished, func(events.Event) { status.CountMatch() })
+
+	health := api.NewHealth(pool, rdb)
+	routes := api.Routes{
+		WS:                handler.ServeWs,
+		Leaderboard:       api.HandleLeaderboardStore(store),
+		LeaderboardStream: stream.Handle,
+		Challenge:         api.HandleChallengeToday(store),
+		Rooms:             api.HandleScoreboard(hub),
+		Matches:           api.HandleMatchVerification(matchmaker),
+		Status:            status.Handle,
+		Liveness:          health.Liveness,
+		Readiness:         health.Readiness,
+		Static:            http.FileServer(http.Dir("web")),
+	}
+	// The SQL-backed endpoints — filtered leaderboard, profiles, friends,
+	// admin — need Postgres; dev mode serves the simple store-backed board
+	// and leaves the rest unmounted.
+	if pool != nil {
+		routes.Leaderboard = api.HandleLeaderboard(pool, lbCache)
+		routes.LeaderboardExport = api.HandleLeaderboardExport(pool)
+		routes.Players = api.HandlePlayer(pool, cfg.AdminAPIKey)
+		routes.Friends = api.HandleFriends(pool)
+		routes.Scores = api.HandleScores(pool, cfg.ScoreAPIKeys)
+		routes.Admin = api.NewAdmin(hub, pool, flagStore, cfg.AdminAPIKey)
+	}
+	router := api.NewRouter(routes, api.CORS(cfg.AllowedOrigins))
+
+	if cfg.GRPCPort > 0 && pool != nil {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+		if err != nil {
+			slog.Error("grpc listen failed", "port", cfg.GRPCPort, "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			slog.Info("grpc listening", "port", cfg.GRPCPort)
+			if err := grpcapi.New(pool).Serve(lis); err != nil {
+				slog.Error("grpc server stopped", "error", err)
+			}
+		}()
+	}
+
+	// The diagnostics server stays off the public port and off the public
+	// interface: pprof, expvar, and goroutine dumps bind loopback only and
+	// require the admin key besides.
+	if cfg.DebugPort > 0 {
+		debugAddr := fmt.Sprintf("127.0.0.1:%d", cfg.DebugPort)
+		go func() {
+			slog.Info("debug server listening", "addr", debugAddr)
+			if err := http.ListenAndServe(debugAddr, api.NewDebugHandler(cfg.AdminAPIKey)); err != nil {
+				slog.Error("debug server stopped", "error", err)
+			}
+		}()
+	}
+
+	srv := api.NewServer(fmt.Sprintf(":%d", cfg.Port), router, cfg)
+
+	// On SIGINT/SIGTERM every client gets a 4004 close frame before the
+	// listener stops, so they can tell a restart from a network drop and
+	// reconnect once the server is back; running matches are checkpointed
+	// and recovered by the next process.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		slog.Info("shutting down")
+		hub.CloseAll(ws.CloseServerShutdown, "server shutting down")
+		shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("shutdown failed", "error", err)
+		}
+	}()
+
+	slog.Info("listening", "port", cfg.Port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,93 @@
+// Package analytics publishes game lifecycle events to a Redis Stream so
+// external consumer services — death-timing heatmaps, match-length
+// dashboards — can process them at their own pace, off the game server and
+// off Postgres. Each new consumer is just another XREAD group on the stream;
+// the server never changes for them.
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/events"
+)
+
+// Publisher tuning.
+const (
+	// queueSize bounds how many unappended events may back up before new
+	// ones are dropped; analytics is best-effort and must never block the
+	// event bus, whose handlers run on the game server's goroutines.
+	queueSize = 1024
+	// appendTimeout caps one XADD round trip.
+	appendTimeout = 5 * time.Second
+)
+
+// Publisher appends bus events to one Redis Stream from a background worker.
+type Publisher struct {
+	rdb    redis.UniversalClient
+	stream string
+	maxLen int64
+	queue  chan entry
+}
+
+// entry is one queued stream record.
+type entry struct {
+	kind string
+	body []byte
+}
+
+// NewPublisher starts a publisher appending to the given stream, trimming it
+// to roughly maxLen entries so an idle consumer can't grow it forever.
+func NewPublisher(rdb redis.UniversalClient, stream string, maxLen int) *Publisher {
+	p := &Publisher{
+		rdb:    rdb,
+		stream: stream,
+		maxLen: int64(maxLen),
+		queue:  make(chan entry, queueSize),
+	}
+	go p.run()
+	return p
+}
+
+// Publish queues one event for the stream; subscribe it to the bus with
+// SubscribeAll. It never blocks: when the queue is full the event is dropped
+// with a log line, and the games keep running.
+func (p *Publisher) Publish(e events.Event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("analytics encode failed", "kind", e.Kind(), "error", err)
+		return
+	}
+	select {
+	case p.queue <- entry{kind: e.Kind(), body: body}:
+	default:
+		slog.Warn("analytics queue full, dropping event", "kind", e.Kind())
+	}
+}
+
+// run drains the queue into the stream.
+func (p *Publisher) run() {
+	for en := range p.queue {
+		p.append(en)
+	}
+}
+
+// append XADDs one record. Each stream entry carries the event kind as its
+// own field so consumers can filter without decoding the JSON body.
+func (p *Publisher) append(en entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), appendTimeout)
+	defer cancel()
+	err := p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"kind": en.kind, "event": en.body},
+	}).Err()
+	if err != nil {
+		slog.Warn("analytics stream append failed", "stream", p.stream, "kind", en.kind, "error", err)
+	}
+}
@@ -0,0 +1,391 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/flags"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// Admin exposes moderation endpoints, protected by a static API key. With
+// no key configured the endpoints are disabled entirely.
+type Admin struct {
+	hub   *ws.Hub
+	pool  *pgxpool.Pool
+	flags *flags.Store
+	key   string
+}
+
+// NewAdmin wires up the admin API with the configured key.
+func NewAdmin(hub *ws.Hub, pool *pgxpool.Pool, flagStore *flags.Store, key string) *Admin {
+	return &Admin{hub: hub, pool: pool, flags: flagStore, key: key}
+}
+
+// Register attaches the admin routes to a mux.
+func (a *Admin) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/admin/clients", a.auth(a.handleClients))
+	mux.HandleFunc("/admin/rooms", a.auth(a.handleRooms))
+	mux.HandleFunc("/admin/kick", a.auth(a.handleKick))
+	mux.HandleFunc("/admin/bans", a.auth(a.handleBans))
+	mux.HandleFunc("/admin/scores", a.auth(a.handleScores))
+	mux.HandleFunc("/admin/seasons", a.auth(a.handleSeasons))
+	mux.HandleFunc("/admin/cheat-flags", a.auth(a.handleCheatFlags))
+	mux.HandleFunc("/admin/flags", a.auth(a.handleFlags))
+	mux.HandleFunc("/api/reports", a.auth(a.handleReports))
+}
+
+// auth rejects requests without the admin key.
+func (a *Admin) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.key == "" {
+			http.Error(w, "admin API disabled", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Admin-Key") != a.key {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// clientView is the admin-facing summary of one connection. IP and Country
+// are the connection metadata captured at upgrade time, included here so
+// moderators can trace abuse to a source address; they never reach the
+// public endpoints.
+type clientView struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	RoomID  string `json:"roomId,omitempty"`
+	Score   int    `json:"score"`
+	InQueue bool   `json:"inQueue"`
+	Alive   bool   `json:"alive"`
+	IP      string `json:"ip,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+func (a *Admin) handleClients(w http.ResponseWriter, r *http.Request) {
+	views := []clientView{}
+	for _, c := range a.hub.Clients() {
+		views = append(views, clientView{
+			ID: c.ID, Name: c.Name, RoomID: c.RoomID,
+			Score: c.Score, InQueue: c.InQueue, Alive: c.IsAlive,
+			IP: c.IP, Country: c.Country,
+		})
+	}
+	writeJSON(w, views)
+}
+
+func (a *Admin) handleRooms(w http.ResponseWriter, r *http.Request) {
+	rooms := map[string][]clientView{}
+	for _, c := range a.hub.Clients() {
+		if c.RoomID == "" {
+			continue
+		}
+		rooms[c.RoomID] = append(rooms[c.RoomID], clientView{
+			ID: c.ID, Name: c.Name, Score: c.Score, Alive: c.IsAlive,
+		})
+	}
+	writeJSON(w, rooms)
+}
+
+func (a *Admin) handleKick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		ClientID string `json:"clientId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClientID == "" {
+		http.Error(w, "clientId required", http.StatusBadRequest)
+		return
+	}
+	c, ok := a.hub.GetClient(req.ClientID)
+	if !ok {
+		http.Error(w, "client not connected", http.StatusNotFound)
+		return
+	}
+	c.CloseWith(ws.CloseKicked, "removed by a moderator")
+	slog.Info("admin kicked client", "client_id", req.ClientID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleBans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		bans, err := db.ListBans(r.Context(), a.pool)
+		if err != nil {
+			slog.Error("list bans failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, bans)
+	case http.MethodPost:
+		var req struct {
+			Target string `json:"target"`
+			Kind   string `json:"kind"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Target == "" {
+			http.Error(w, "target required", http.StatusBadRequest)
+			return
+		}
+		if req.Kind != db.BanKindPlayer && req.Kind != db.BanKindIP {
+			http.Error(w, "kind must be player or ip", http.StatusBadRequest)
+			return
+		}
+		if err := db.AddBan(r.Context(), a.pool, req.Target, req.Kind, req.Reason); err != nil {
+			slog.Error("add ban failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		// Banned players who are currently connected get kicked right away.
+		if req.Kind == db.BanKindPlayer {
+			if c, ok := a.hub.GetClient(req.Target); ok {
+				c.CloseWith(ws.CloseBanned, "account banned")
+			}
+		}
+		slog.Info("admin banned target", "target", req.Target, "kind", req.Kind)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "target required", http.StatusBadRequest)
+			return
+		}
+		if err := db.RemoveBan(r.Context(), a.pool, target); err != nil {
+			slog.Error("remove ban failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *Admin) handleScores(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	playerID := r.URL.Query().Get("playerId")
+	if playerID == "" {
+		http.Error(w, "playerId required", http.StatusBadRequest)
+		return
+	}
+	deleted, err := db.DeleteScores(r.Context(), a.pool, playerID)
+	if err != nil {
+		slog.Error("delete scores failed", "player_id", playerID, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("admin purged scores", "player_id", playerID, "deleted", deleted)
+	writeJSON(w, map[string]int64{"deleted": deleted})
+}
+
+// handleSeasons lists seasons (GET) or rolls the current one over (POST):
+// final standings are archived, the season closed, and the next one opened.
+func (a *Admin) handleSeasons(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		seasons, err := db.ListSeasons(r.Context(), a.pool)
+		if err != nil {
+			slog.Error("list seasons failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, seasons)
+	case http.MethodPost:
+		closed, next, err := db.CloseSeason(r.Context(), a.pool)
+		if err != nil {
+			slog.Error("close season failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		slog.Info("admin rolled season over", "closed_id", closed.ID, "next_id", next.ID)
+		writeJSON(w, map[string]db.Season{"closed": closed, "next": next})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCheatFlags lists recent anti-cheat rejections, newest first,
+// optionally filtered with ?playerId=.
+func (a *Admin) handleCheatFlags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := queryInt(r, "limit", 50)
+	if limit < 1 || limit > maxPageSize {
+		limit = 50
+	}
+	flags, err := db.ListCheatFlags(r.Context(), a.pool, r.URL.Query().Get("playerId"), limit)
+	if err != nil {
+		slog.Error("list cheat flags failed", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, flags)
+}
+
+// handleFlags lists runtime feature flags (GET) or sets one (POST). Changes
+// that players can see — a maintenance banner, matchmaking being paused —
+// are announced to everyone online right away.
+func (a *Admin) handleFlags(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		all := a.flags.All(r.Context())
+		if all == nil {
+			all = map[string]string{}
+		}
+		writeJSON(w, all)
+	case http.MethodPost:
+		var req struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		if err := a.flags.Set(r.Context(), req.Name, req.Value); err != nil {
+			slog.Error("set feature flag failed", "flag", req.Name, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		switch req.Name {
+		case flags.MaintenanceBanner:
+			if req.Value != "" {
+				a.hub.Broadcast(ws.MsgNotice, ws.NoticePayload{Message: req.Value})
+			}
+		case flags.Matchmaking:
+			if !a.flags.Enabled(r.Context(), flags.Matchmaking) {
+				a.hub.Broadcast(ws.MsgNotice, ws.NoticePayload{Message: "Matchmaking is paused for maintenance; running games will finish normally."})
+			}
+		}
+		slog.Info("admin set feature flag", "flag", req.Name, "value", req.Value)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// reportEventLimit caps how many audit rows are attached to each report in
+// the review queue.
+const reportEventLimit = 100
+
+// reportView is one report with the reported player's score audit trail from
+// the match attached, so a reviewer sees the run without a second query.
+type reportView struct {
+	db.Report
+	// Events is the reported player's audited score updates from the match,
+	// oldest first. Empty when the trail has already been pruned.
+	Events []db.ScoreEvent `json:"events"`
+}
+
+// handleReports serves the player-report review queue. GET lists reports —
+// open ones by default, ?status=resolved or ?status=all for the rest — each
+// with the reported run's score events attached. POST acts on one report:
+// {"id": N, "action": "resolve"|"flag"|"ban"}. "flag" records a cheat flag
+// for the reported player, "ban" bans and disconnects them; both then mark
+// the report resolved.
+func (a *Admin) handleReports(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "":
+			status = db.ReportStatusOpen
+		case "all":
+			status = ""
+		}
+		limit := queryInt(r, "limit", 50)
+		if limit < 1 || limit > maxPageSize {
+			limit = 50
+		}
+		reports, err := db.ListReports(r.Context(), a.pool, status, limit)
+		if err != nil {
+			slog.Error("list reports failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		views := []reportView{}
+		for _, rep := range reports {
+			events, err := db.ListScoreEvents(r.Context(), a.pool, rep.ReportedID, rep.RoomID, reportEventLimit)
+			if err != nil {
+				slog.Error("list score events failed", "report_id", rep.ID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			views = append(views, reportView{Report: rep, Events: events})
+		}
+		writeJSON(w, views)
+	case http.MethodPost:
+		var req struct {
+			ID     int64  `json:"id"`
+			Action string `json:"action"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+			http.Error(w, "id required", http.StatusBadRequest)
+			return
+		}
+		rep, err := db.GetReport(r.Context(), a.pool, req.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				http.Error(w, "report not found", http.StatusNotFound)
+				return
+			}
+			slog.Error("get report failed", "report_id", req.ID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		switch req.Action {
+		case "resolve":
+		case "flag":
+			detail := fmt.Sprintf("report #%d: %s", rep.ID, rep.Reason)
+			if err := db.RecordCheatFlag(r.Context(), a.pool, rep.ReportedID, rep.ReportedName, rep.RoomID, 0, db.CheatReasonReported, detail); err != nil {
+				slog.Error("record cheat flag failed", "report_id", rep.ID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+		case "ban":
+			if err := db.AddBan(r.Context(), a.pool, rep.ReportedID, db.BanKindPlayer, rep.Reason); err != nil {
+				slog.Error("add ban failed", "report_id", rep.ID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			if c, ok := a.hub.GetClient(rep.ReportedID); ok {
+				c.CloseWith(ws.CloseBanned, "account banned")
+			}
+		default:
+			http.Error(w, "action must be resolve, flag, or ban", http.StatusBadRequest)
+			return
+		}
+		if err := db.ResolveReport(r.Context(), a.pool, rep.ID); err != nil {
+			slog.Error("resolve report failed", "report_id", rep.ID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		slog.Info("admin handled report", "report_id", rep.ID, "action", req.Action, "reported_id", rep.ReportedID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeJSON writes a JSON response body.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
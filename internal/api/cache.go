@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+)
+
+// Leaderboard cache tuning.
+const (
+	// topCacheSize is how many rows of the plain all-time board are cached;
+	// pages that fit inside it are served without touching Postgres.
+	topCacheSize = 100
+	// topCacheKey is the Redis key holding the serialized top rows.
+	topCacheKey = "leaderboard:top"
+	// topCacheTTL bounds staleness when an invalidation races the async
+	// score writer: the cache may be refilled before a just-saved score is
+	// actually in Postgres, so it expires on its own shortly after.
+	topCacheTTL = 30 * time.Second
+)
+
+// cachedBoard is the serialized cache entry: the top rows with ranks already
+// stamped, their row IDs for cursor hand-off, and the total row count.
+type cachedBoard struct {
+	Entries []LeaderboardEntry `json:"entries"`
+	IDs     []int64            `json:"ids"`
+	Total   int                `json:"total"`
+}
+
+// LeaderboardCache keeps the top of the plain all-time leaderboard in Redis
+// so the common first-page request doesn't cost a COUNT(*) plus a sorted
+// query. A nil cache (Redis not configured) is valid and never hits.
+type LeaderboardCache struct {
+	rdb redis.UniversalClient
+}
+
+// NewLeaderboardCache wraps a Redis client; rdb may be nil, which disables
+// caching entirely.
+func NewLeaderboardCache(rdb redis.UniversalClient) *LeaderboardCache {
+	if rdb == nil {
+		return nil
+	}
+	return &LeaderboardCache{rdb: rdb}
+}
+
+// get returns the cached top rows, if present.
+func (c *LeaderboardCache) get(ctx context.Context) (cachedBoard, bool) {
+	if c == nil {
+		return cachedBoard{}, false
+	}
+	raw, err := c.rdb.Get(ctx, topCacheKey).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			slog.Error("leaderboard cache read failed", "error", err)
+		}
+		return cachedBoard{}, false
+	}
+	var board cachedBoard
+	if err := json.Unmarshal(raw, &board); err != nil {
+		slog.Error("leaderboard cache decode failed", "error", err)
+		return cachedBoard{}, false
+	}
+	return board, true
+}
+
+// set stores the top rows. Failures only cost the next request a DB trip, so
+// they are logged and swallowed.
+func (c *LeaderboardCache) set(ctx context.Context, board cachedBoard) {
+	if c == nil {
+		return
+	}
+	raw, err := json.Marshal(board)
+	if err != nil {
+		slog.Error("leaderboard cache encode failed", "error", err)
+		return
+	}
+	if err := c.rdb.Set(ctx, topCacheKey, raw, topCacheTTL).Err(); err != nil {
+		slog.Error("leaderboard cache write failed", "error", err)
+	}
+}
+
+// Invalidate drops the cached top rows; call it whenever a score is saved.
+func (c *LeaderboardCache) Invalidate(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if err := c.rdb.Del(ctx, topCacheKey).Err(); err != nil {
+		slog.Error("leaderboard cache invalidation failed", "error", err)
+	}
+}
+
+// fetchTopBoard reads the cacheable slice of the board from Postgres: the
+// total count plus the top rows in (score DESC, id ASC) order.
+func fetchTopBoard(ctx context.Context, pool *pgxpool.Pool) (cachedBoard, error) {
+	ctx, span := tracing.Start(ctx, "db.leaderboard_query")
+	defer span.End()
+
+	var board cachedBoard
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM leaderboard`).Scan(&board.Total); err != nil {
+		return board, err
+	}
+	rows, err := pool.Query(ctx, `
+		SELECT id, player_id, player_name, score, verified, room_id, created_at
+		FROM leaderboard
+		ORDER BY score DESC, id ASC
+		LIMIT $1`, topCacheSize)
+	if err != nil {
+		return board, err
+	}
+	defer rows.Close()
+	board.Entries = []LeaderboardEntry{}
+	for rows.Next() {
+		var e LeaderboardEntry
+		var id int64
+		if err := rows.Scan(&id, &e.PlayerID, &e.PlayerName, &e.Score, &e.Verified, &e.RoomID, &e.CreatedAt); err != nil {
+			return board, err
+		}
+		e.Rank = len(board.Entries) + 1
+		board.Entries = append(board.Entries, e)
+		board.IDs = append(board.IDs, id)
+	}
+	return board, rows.Err()
+}
+
+// serveCachedPage answers one page of the plain board from the cached top
+// rows, filling the cache from Postgres on a miss.
+func serveCachedPage(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, cache *LeaderboardCache, page, limit, offset int) {
+	board, ok := cache.get(r.Context())
+	if !ok {
+		var err error
+		board, err = fetchTopBoard(r.Context(), pool)
+		if err != nil {
+			slog.Error("leaderboard query failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		cache.set(r.Context(), board)
+	}
+
+	end := offset + limit
+	if end > len(board.Entries) {
+		end = len(board.Entries)
+	}
+	entries := []LeaderboardEntry{}
+	if offset < end {
+		entries = board.Entries[offset:end]
+	}
+	resp := LeaderboardResponse{
+		Entries: entries,
+		Total:   board.Total,
+		Page:    page,
+		Limit:   limit,
+	}
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		resp.NextCursor = encodeCursor(last.Score, board.IDs[end-1], last.Rank)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
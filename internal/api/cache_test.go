@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// The cache stores pre-serialized entries, so any field fetchTopBoard fills
+// must survive the encode/decode round trip or cached pages silently diverge
+// from the uncached paths (as verified and roomId once did).
+func TestCachedBoardRoundTrip(t *testing.T) {
+	board := cachedBoard{
+		Entries: []LeaderboardEntry{
+			{Rank: 1, PlayerID: "p1", PlayerName: "Alice", Score: 42, Verified: true, RoomID: "room_1", CreatedAt: time.Now().UTC().Truncate(time.Second)},
+			{Rank: 2, PlayerID: "p2", PlayerName: "Bert", Score: 7},
+		},
+		IDs:   []int64{11, 12},
+		Total: 2,
+	}
+	raw, err := json.Marshal(board)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got cachedBoard
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !got.Entries[0].Verified || got.Entries[0].RoomID != "room_1" {
+		t.Fatalf("entry 0 = %+v, want verified with room_1", got.Entries[0])
+	}
+	if got.Entries[1].Verified || got.Entries[1].RoomID != "" {
+		t.Fatalf("entry 1 = %+v, want unverified guest row", got.Entries[1])
+	}
+	if got.Total != 2 || len(got.IDs) != 2 {
+		t.Fatalf("total/ids = %d/%d, want 2/2", got.Total, len(got.IDs))
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// ChallengeEntry is one row of the daily-challenge board.
+type ChallengeEntry struct {
+	Rank       int       `json:"rank"`
+	PlayerID   string    `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	Score      int       `json:"score"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ChallengeResponse is the JSON body returned by HandleChallengeToday. Seed
+// is the day's shared obstacle seed, included so clients can render a layout
+// preview without joining.
+type ChallengeResponse struct {
+	Day     string           `json:"day"`
+	Seed    int64            `json:"seed"`
+	Entries []ChallengeEntry `json:"entries"`
+}
+
+// HandleChallengeToday serves GET /api/challenge/today: the current UTC
+// day's seed and board, each player's single best attempt ranked best first.
+func HandleChallengeToday(repo db.ChallengeRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		limit := queryInt(r, "limit", defaultPageSize)
+		if limit < 1 || limit > maxPageSize {
+			limit = defaultPageSize
+		}
+		day := game.ChallengeDay(time.Now())
+		rows, err := repo.TopChallengeScores(r.Context(), day, limit)
+		if err != nil {
+			slog.Error("challenge board query failed", "day", day, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		entries := make([]ChallengeEntry, 0, len(rows))
+		for i, row := range rows {
+			entries = append(entries, ChallengeEntry{
+				Rank:       i + 1,
+				PlayerID:   row.PlayerID,
+				PlayerName: row.PlayerName,
+				Score:      row.Score,
+				UpdatedAt:  row.UpdatedAt,
+			})
+		}
+		writeJSON(w, ChallengeResponse{Day: day, Seed: game.DailySeed(day), Entries: entries})
+	}
+}
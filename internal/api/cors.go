@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORS wraps a handler with cross-origin headers driven by the configured
+// allowlist. A list containing "*" is the dev-mode escape hatch that echoes
+// the wildcard; otherwise only listed origins are reflected back.
+func CORS(allowed []string) func(http.Handler) http.Handler {
+	wildcard := false
+	for _, origin := range allowed {
+		if origin == "*" {
+			wildcard = true
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			switch {
+			case wildcard:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			case origin != "" && originInList(origin, allowed):
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Admin-Key")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func originInList(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(a, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package api
+
+import "testing"
+
+func TestCursorRoundTrip(t *testing.T) {
+	token := encodeCursor(1234, 987654321, 41)
+	score, id, rank, err := decodeCursor(token)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if score != 1234 || id != 987654321 || rank != 41 {
+		t.Fatalf("round trip = (%d, %d, %d), want (1234, 987654321, 41)", score, id, rank)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, bad := range []string{"", "not-base64!", "bm90IGEgY3Vyc29y"} {
+		if _, _, _, err := decodeCursor(bad); err == nil {
+			t.Errorf("decodeCursor(%q) accepted garbage", bad)
+		}
+	}
+}
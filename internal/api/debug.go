@@ -0,0 +1,44 @@
+package api
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+)
+
+// NewDebugHandler builds the diagnostics mux served on the separate debug
+// port: net/http/pprof under /debug/pprof/, expvar under /debug/vars, and a
+// plain-text dump of every goroutine stack under /debug/goroutines — enough
+// to chase a goroutine leak in the hub or the client write pumps on a live
+// server. Every route requires the admin key, on top of the loopback-only
+// listener, because profiles leak internals and CPU captures cost real time.
+func NewDebugHandler(key string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/goroutines", handleGoroutines)
+	return debugAuth(key, mux)
+}
+
+// debugAuth rejects requests without the admin key, mirroring Admin.auth.
+func debugAuth(key string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key == "" || r.Header.Get("X-Admin-Key") != key {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleGoroutines writes every goroutine's stack with full frames (debug=2),
+// the same dump a SIGQUIT would produce but without killing the process.
+func handleGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
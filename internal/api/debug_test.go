@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandlerAuth(t *testing.T) {
+	h := NewDebugHandler("secret")
+	cases := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"missing key", "", http.StatusUnauthorized},
+		{"wrong key", "nope", http.StatusUnauthorized},
+		{"right key", "secret", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/debug/goroutines", nil)
+		if tc.key != "" {
+			req.Header.Set("X-Admin-Key", tc.key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tc.status {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.status)
+		}
+	}
+}
+
+func TestDebugGoroutineDump(t *testing.T) {
+	h := NewDebugHandler("secret")
+	req := httptest.NewRequest(http.MethodGet, "/debug/goroutines", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), "goroutine ") {
+		t.Fatalf("dump does not look like goroutine stacks: %q", w.Body.String()[:min(120, w.Body.Len())])
+	}
+}
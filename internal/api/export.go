@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+// exportBatchSize is how many rows each keyset query fetches during an
+// export. Batches are flushed to the client as they complete, so even a
+// full-board download holds neither the rows nor the response in memory.
+const exportBatchSize = 1000
+
+// HandleLeaderboardExport serves GET /api/leaderboard/export, a bulk
+// download of the standings for tournament organizers. format=csv (default)
+// or format=json picks the encoding; period= filters like the board itself.
+// Rows stream out in keyset batches with a Content-Disposition attachment
+// header, so the export never buffers the whole board.
+func HandleLeaderboardExport(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "json" {
+			http.Error(w, "format must be csv or json", http.StatusBadRequest)
+			return
+		}
+
+		var filter db.LeaderboardFilter
+		if period := r.URL.Query().Get("period"); period != "" && period != PeriodAll {
+			cutoff, ok := PeriodStart(period, time.Now().UTC())
+			if !ok {
+				http.Error(w, "invalid period", http.StatusBadRequest)
+				return
+			}
+			filter.Add("created_at >= $%d", cutoff)
+		}
+
+		filename := "leaderboard-" + time.Now().UTC().Format("20060102") + "." + format
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		flusher, _ := w.(http.Flusher)
+		flush := func() {
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		var writeRow func(rank int, row db.LeaderboardRow) error
+		var finish func()
+		switch format {
+		case "csv":
+			cw := csv.NewWriter(w)
+			cw.Write([]string{"rank", "player_id", "player_name", "score", "verified", "room_id", "created_at"})
+			writeRow = func(rank int, row db.LeaderboardRow) error {
+				return cw.Write([]string{
+					strconv.Itoa(rank),
+					row.PlayerID,
+					row.PlayerName,
+					strconv.Itoa(row.Score),
+					strconv.FormatBool(row.Verified),
+					row.RoomID,
+					row.CreatedAt.UTC().Format(time.RFC3339),
+				})
+			}
+			finish = cw.Flush
+		case "json":
+			enc := json.NewEncoder(w)
+			w.Write([]byte("[\n"))
+			first := true
+			writeRow = func(rank int, row db.LeaderboardRow) error {
+				if !first {
+					w.Write([]byte(",\n"))
+				}
+				first = false
+				return enc.Encode(LeaderboardEntry{
+					Rank:       rank,
+					PlayerID:   row.PlayerID,
+					PlayerName: row.PlayerName,
+					Score:      row.Score,
+					Verified:   row.Verified,
+					RoomID:     row.RoomID,
+					CreatedAt:  row.CreatedAt,
+				})
+			}
+			finish = func() { w.Write([]byte("]\n")) }
+		}
+
+		// Walk the board by (score, id) keyset from the top; OFFSET paging
+		// would re-scan ever-longer prefixes on a big table.
+		score, id, rank := math.MaxInt32, int64(0), 0
+		for {
+			rows, err := db.ListLeaderboardAfter(r.Context(), pool, &filter, score, id, exportBatchSize)
+			if err != nil {
+				// Headers are out the door; all that's left is to cut the
+				// download short and log why.
+				slog.Error("leaderboard export failed", "rank", rank, "error", err)
+				return
+			}
+			for _, row := range rows {
+				rank++
+				if err := writeRow(rank, row); err != nil {
+					slog.Warn("leaderboard export aborted by client", "rank", rank, "error", err)
+					return
+				}
+			}
+			if len(rows) < exportBatchSize {
+				break
+			}
+			last := rows[len(rows)-1]
+			score, id = last.Score, last.ID
+			flush()
+		}
+		finish()
+		flush()
+		slog.Info("leaderboard exported", "format", format, "rows", rank)
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The parameter checks run before any database access, so a nil pool is fine.
+func TestHandleLeaderboardExportRejections(t *testing.T) {
+	h := HandleLeaderboardExport(nil)
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		status int
+	}{
+		{"bad method", http.MethodPost, "/api/leaderboard/export", http.StatusMethodNotAllowed},
+		{"bad format", http.MethodGet, "/api/leaderboard/export?format=xml", http.StatusBadRequest},
+		{"bad period", http.MethodGet, "/api/leaderboard/export?period=hourly", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(tc.method, tc.url, nil))
+		if w.Code != tc.status {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.status)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+// friendsResponse is the overview returned by GET /api/friends.
+type friendsResponse struct {
+	Friends  []db.Friend        `json:"friends"`
+	Requests []db.FriendRequest `json:"requests"`
+}
+
+// friendAction is the body of the POST /api/friends mutations.
+type friendAction struct {
+	FromID   string `json:"fromId"`
+	FromName string `json:"fromName"`
+	ToID     string `json:"toId"`
+}
+
+// HandleFriends serves the friend system:
+//
+//	GET  /api/friends?playerId={id}  accepted friends plus pending requests
+//	POST /api/friends/request        {"fromId","fromName","toId"}
+//	POST /api/friends/accept         {"fromId","toId"} (toId accepts)
+//	POST /api/friends/decline        {"fromId","toId"}
+//	POST /api/friends/remove         {"fromId","toId"}
+func HandleFriends(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/friends":
+			playerID := r.URL.Query().Get("playerId")
+			if playerID == "" {
+				http.Error(w, "playerId is required", http.StatusBadRequest)
+				return
+			}
+			friends, err := db.ListFriends(r.Context(), pool, playerID)
+			if err != nil {
+				slog.Error("list friends failed", "player_id", playerID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			requests, err := db.ListFriendRequests(r.Context(), pool, playerID)
+			if err != nil {
+				slog.Error("list friend requests failed", "player_id", playerID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(friendsResponse{Friends: friends, Requests: requests})
+
+		case r.Method == http.MethodPost:
+			var a friendAction
+			if err := json.NewDecoder(r.Body).Decode(&a); err != nil || a.FromID == "" || a.ToID == "" {
+				http.Error(w, "fromId and toId are required", http.StatusBadRequest)
+				return
+			}
+			if a.FromID == a.ToID {
+				http.Error(w, "cannot befriend yourself", http.StatusBadRequest)
+				return
+			}
+			var err error
+			switch r.URL.Path {
+			case "/api/friends/request":
+				err = db.SendFriendRequest(r.Context(), pool, a.FromID, a.FromName, a.ToID)
+			case "/api/friends/accept":
+				var accepted bool
+				accepted, err = db.AcceptFriendRequest(r.Context(), pool, a.FromID, a.ToID)
+				if err == nil && !accepted {
+					http.Error(w, "no pending request", http.StatusNotFound)
+					return
+				}
+			case "/api/friends/decline":
+				err = db.DeclineFriendRequest(r.Context(), pool, a.FromID, a.ToID)
+			case "/api/friends/remove":
+				err = db.RemoveFriend(r.Context(), pool, a.FromID, a.ToID)
+			default:
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				slog.Error("friend action failed", "path", r.URL.Path, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// readyCheckTimeout bounds each dependency ping so a hung dependency fails
+// the probe instead of hanging it.
+const readyCheckTimeout = 2 * time.Second
+
+// Health serves the Kubernetes-style liveness and readiness probes.
+type Health struct {
+	// pool and rdb are nil when the dependency is not configured — Redis by
+	// choice, Postgres in dev mode; a nil dependency's check reports
+	// "skipped".
+	pool *pgxpool.Pool
+	rdb  redis.UniversalClient
+}
+
+// NewHealth wires up the probes against the server's dependencies.
+func NewHealth(pool *pgxpool.Pool, rdb redis.UniversalClient) *Health {
+	return &Health{pool: pool, rdb: rdb}
+}
+
+// Liveness serves GET /healthz: the process is up and able to answer HTTP.
+// It deliberately checks no dependencies, so a Postgres outage does not get
+// the pod restarted.
+func (h *Health) Liveness(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+// readyResponse is the per-dependency status JSON returned by /readyz.
+type readyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Readiness serves GET /readyz: pings every dependency with a short timeout
+// and returns 503 with per-dependency detail if any of them is down.
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	resp := readyResponse{Status: "ok", Checks: map[string]string{}}
+
+	if h.pool == nil {
+		resp.Checks["postgres"] = "skipped"
+	} else {
+		ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+		defer cancel()
+		if err := h.pool.Ping(ctx); err != nil {
+			resp.Status = "degraded"
+			resp.Checks["postgres"] = err.Error()
+		} else {
+			resp.Checks["postgres"] = "ok"
+		}
+	}
+
+	if h.rdb == nil {
+		resp.Checks["redis"] = "skipped"
+	} else {
+		ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+		defer cancel()
+		if err := h.rdb.Ping(ctx).Err(); err != nil {
+			resp.Status = "degraded"
+			resp.Checks["redis"] = err.Error()
+		} else {
+			resp.Checks["redis"] = "ok"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
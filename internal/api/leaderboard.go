@@ -0,0 +1,354 @@
+// Package api contains the HTTP endpoints that sit next to the WebSocket
+// game server, most importantly the global leaderboard.
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// LeaderboardEntry is one row of the global leaderboard.
+type LeaderboardEntry struct {
+	Rank       int    `json:"rank"`
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`
+	// Verified distinguishes registered players from guest runs.
+	Verified bool `json:"verified"`
+	// RoomID traces the score back to the match it was set in; empty for
+	// roomless runs and rows predating the column.
+	RoomID    string    `json:"roomId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LeaderboardResponse is the JSON body returned by HandleLeaderboard.
+// NextCursor, when present, fetches the page after the last entry via
+// ?after=<cursor> regardless of rows inserted in between.
+type LeaderboardResponse struct {
+	Entries    []LeaderboardEntry `json:"entries"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// HandleLeaderboard serves GET /api/leaderboard with page/limit pagination,
+// or keyset pagination when ?after=<cursor> is supplied. Filters —
+// period/season/mode plus search= (name substring), minScore=, and
+// playerId= — compose freely. Pages of the plain all-time board that fit
+// inside the cached top rows are served from cache.
+func HandleLeaderboard(pool *pgxpool.Pool, cache *LeaderboardCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		page := queryInt(r, "page", 1)
+		if page < 1 {
+			page = 1
+		}
+		limit := queryInt(r, "limit", defaultPageSize)
+		if limit < 1 || limit > maxPageSize {
+			limit = defaultPageSize
+		}
+		offset := (page - 1) * limit
+
+		// The unfiltered board — by far the most common request — comes out
+		// of the Redis cache as long as the page fits in the cached rows.
+		q := r.URL.Query()
+		plain := q.Get("period") == "" && q.Get("season") == "" &&
+			q.Get("mode") == "" && q.Get("after") == "" &&
+			q.Get("search") == "" && q.Get("minScore") == "" && q.Get("playerId") == ""
+		if cache != nil && plain && offset+limit <= topCacheSize {
+			serveCachedPage(w, r, pool, cache, page, limit, offset)
+			return
+		}
+
+		// Optional filters compose into the query layer's WHERE clause; the
+		// db package numbers the placeholders per query.
+		var filter db.LeaderboardFilter
+
+		// period=daily|weekly|monthly restricts the board to the current
+		// UTC calendar bucket; the default (or "all") is all-time.
+		if period := r.URL.Query().Get("period"); period != "" && period != PeriodAll {
+			cutoff, ok := PeriodStart(period, time.Now().UTC())
+			if !ok {
+				http.Error(w, "invalid period", http.StatusBadRequest)
+				return
+			}
+			filter.Add("created_at >= $%d", cutoff)
+		}
+
+		// season=current|<id> restricts the board to one season. Closed
+		// seasons are served from their archived final standings instead of
+		// the live table.
+		if season := r.URL.Query().Get("season"); season != "" {
+			if season == "current" {
+				s, err := db.CurrentSeason(r.Context(), pool)
+				if err != nil {
+					slog.Error("current season lookup failed", "error", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				filter.Add("season_id = $%d", s.ID)
+			} else {
+				id, err := strconv.Atoi(season)
+				if err != nil {
+					http.Error(w, "invalid season", http.StatusBadRequest)
+					return
+				}
+				s, ok, err := db.GetSeason(r.Context(), pool, id)
+				if err != nil {
+					slog.Error("season lookup failed", "season_id", id, "error", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				if !ok {
+					http.Error(w, "season not found", http.StatusNotFound)
+					return
+				}
+				if s.EndedAt != nil {
+					serveSeasonArchive(w, r, pool, id, page, limit, offset)
+					return
+				}
+				filter.Add("season_id = $%d", s.ID)
+			}
+		}
+
+		// search=<name> matches player names case-insensitively, anywhere in
+		// the name; the trigram index keeps it off a sequential scan.
+		if search := r.URL.Query().Get("search"); search != "" {
+			filter.Add("player_name ILIKE $%d", "%"+escapeLike(search)+"%")
+		}
+
+		// minScore= and playerId= serve profile and moderation lookups.
+		if min := r.URL.Query().Get("minScore"); min != "" {
+			n, err := strconv.Atoi(min)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid minScore", http.StatusBadRequest)
+				return
+			}
+			filter.Add("score >= $%d", n)
+		}
+		if playerID := r.URL.Query().Get("playerId"); playerID != "" {
+			filter.Add("player_id = $%d", playerID)
+		}
+
+		// Keyset pagination walks the board by (score, id) instead of
+		// OFFSET, so concurrent inserts can't duplicate or skip rows.
+		if after := r.URL.Query().Get("after"); after != "" {
+			if r.URL.Query().Get("mode") == "best" {
+				http.Error(w, "cursor pagination does not support mode=best", http.StatusBadRequest)
+				return
+			}
+			serveLeaderboardCursor(w, r, pool, limit, after, &filter)
+			return
+		}
+
+		// mode=best collapses the board to each player's highest score so a
+		// single player cannot fill a whole page with repeated runs.
+		best := r.URL.Query().Get("mode") == "best"
+		total, err := db.CountLeaderboard(r.Context(), pool, &filter, best)
+		if err != nil {
+			slog.Error("leaderboard count failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		rows, err := db.ListLeaderboard(r.Context(), pool, &filter, best, limit, offset)
+		if err != nil {
+			slog.Error("leaderboard query failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		entries := make([]LeaderboardEntry, 0, len(rows))
+		for i, row := range rows {
+			entries = append(entries, LeaderboardEntry{
+				Rank:       offset + 1 + i,
+				PlayerID:   row.PlayerID,
+				PlayerName: row.PlayerName,
+				Score:      row.Score,
+				Verified:   row.Verified,
+				RoomID:     row.RoomID,
+				CreatedAt:  row.CreatedAt,
+			})
+		}
+
+		resp := LeaderboardResponse{
+			Entries: entries,
+			Total:   total,
+			Page:    page,
+			Limit:   limit,
+		}
+		// mode=best rows don't chain: their cursor would skip other rows of
+		// the same player, so only the plain board hands one out.
+		if !best && len(entries) == limit {
+			last := entries[len(entries)-1]
+			resp.NextCursor = encodeCursor(last.Score, rows[len(rows)-1].ID, last.Rank)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// HandleLeaderboardStore serves GET /api/leaderboard straight from a
+// LeaderboardRepository — the Postgres-free path behind `-dev`. Only the
+// plain board with a limit is supported; filters, seasons, and pagination
+// need the SQL-backed handler.
+func HandleLeaderboardStore(repo db.LeaderboardRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		limit := queryInt(r, "limit", defaultPageSize)
+		if limit < 1 || limit > maxPageSize {
+			limit = defaultPageSize
+		}
+		rows, err := repo.TopScores(r.Context(), limit)
+		if err != nil {
+			slog.Error("leaderboard store query failed", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		entries := make([]LeaderboardEntry, 0, len(rows))
+		for i, row := range rows {
+			entries = append(entries, LeaderboardEntry{
+				Rank:       i + 1,
+				PlayerID:   row.PlayerID,
+				PlayerName: row.PlayerName,
+				Score:      row.Score,
+				Verified:   row.Verified,
+				RoomID:     row.RoomID,
+				CreatedAt:  row.CreatedAt,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LeaderboardResponse{
+			Entries: entries,
+			Total:   len(entries),
+			Page:    1,
+			Limit:   limit,
+		})
+	}
+}
+
+// escapeLike neutralizes LIKE wildcards in user-supplied search input so a
+// search for "100%" doesn't match everything.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// queryInt reads an integer query parameter with a fallback.
+func queryInt(r *http.Request, key string, fallback int) int {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// seasonArchiveResponse is the body returned for a closed season, read from
+// its snapshotted final standings.
+type seasonArchiveResponse struct {
+	Entries []db.Standing `json:"entries"`
+	Total   int           `json:"total"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+}
+
+// encodeCursor packs the keyset position (score, row id) and the rank of
+// that row into an opaque token.
+func encodeCursor(score int, id int64, rank int) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%d:%d:%d", score, id, rank)))
+}
+
+// decodeCursor is the inverse of encodeCursor.
+func decodeCursor(s string) (score int, id int64, rank int, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if _, err := fmt.Sscanf(string(raw), "%d:%d:%d", &score, &id, &rank); err != nil {
+		return 0, 0, 0, err
+	}
+	return score, id, rank, nil
+}
+
+// serveLeaderboardCursor pages the board by keyset: rows strictly after the
+// cursor position in (score DESC, id ASC) order. filter carries any
+// conditions already parsed by the caller.
+func serveLeaderboardCursor(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, limit int, after string, filter *db.LeaderboardFilter) {
+	score, id, rank, err := decodeCursor(after)
+	if err != nil {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := db.ListLeaderboardAfter(r.Context(), pool, filter, score, id, limit)
+	if err != nil {
+		slog.Error("leaderboard cursor query failed", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		rank++
+		entries = append(entries, LeaderboardEntry{
+			Rank:       rank,
+			PlayerID:   row.PlayerID,
+			PlayerName: row.PlayerName,
+			Score:      row.Score,
+			Verified:   row.Verified,
+			RoomID:     row.RoomID,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+
+	resp := LeaderboardResponse{Entries: entries, Limit: limit}
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		resp.NextCursor = encodeCursor(last.Score, rows[len(rows)-1].ID, last.Rank)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// serveSeasonArchive pages through the archived standings of a closed season.
+func serveSeasonArchive(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, seasonID, page, limit, offset int) {
+	standings, total, err := db.SeasonStandings(r.Context(), pool, seasonID, limit, offset)
+	if err != nil {
+		slog.Error("season archive query failed", "season_id", seasonID, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(seasonArchiveResponse{
+		Entries: standings,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Alice", "Alice"},
+		{"100%", `100\%`},
+		{"a_b", `a\_b`},
+		{`back\slash`, `back\\slash`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.in); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHandleLeaderboardStore(t *testing.T) {
+	store := db.NewMemStore()
+	ctx := context.Background()
+	store.SaveScore(ctx, "p1", "Alice", 10, "", "")
+	store.SaveScore(ctx, "p2", "Bert", 25, "", "")
+	store.SaveScore(ctx, "p3", "Cleo", 5, "", "")
+
+	rec := httptest.NewRecorder()
+	HandleLeaderboardStore(store)(rec, httptest.NewRequest(http.MethodGet, "/api/leaderboard?limit=2", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp LeaderboardResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(resp.Entries))
+	}
+	if resp.Entries[0].PlayerID != "p2" || resp.Entries[0].Rank != 1 {
+		t.Fatalf("top entry = %+v, want p2 at rank 1", resp.Entries[0])
+	}
+	if resp.Entries[1].PlayerID != "p1" || resp.Entries[1].Rank != 2 {
+		t.Fatalf("second entry = %+v, want p1 at rank 2", resp.Entries[1])
+	}
+
+	rec = httptest.NewRecorder()
+	HandleLeaderboardStore(store)(rec, httptest.NewRequest(http.MethodPost, "/api/leaderboard", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// roundSchedule is one round's derived obstacle layout in a verification
+// response.
+type roundSchedule struct {
+	Round int   `json:"round"`
+	Seed  int64 `json:"seed"`
+	// Obstacles is the schedule the server derived from the seed — the same
+	// one it streamed to every participant — up to the match's duration.
+	Obstacles []ws.ObstacleSpawn `json:"obstacles"`
+}
+
+// verificationResponse is the public fairness record of a finished match.
+type verificationResponse struct {
+	ws.MatchVerification
+	Rounds []roundSchedule `json:"rounds"`
+}
+
+// HandleMatchVerification serves GET /api/matches/{id}/verification: the
+// seeds of a finished match plus the obstacle schedule derived from each, so
+// third parties can confirm all participants faced identical layouts. The
+// archive is in-memory and bounded, so records expire; tournaments should
+// fetch them soon after the match.
+func HandleMatchVerification(mm *ws.Matchmaker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		roomID, suffix, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/matches/"), "/")
+		if !ok || roomID == "" || suffix != "verification" {
+			http.NotFound(w, r)
+			return
+		}
+		v, ok := mm.Verification(roomID)
+		if !ok {
+			http.Error(w, "unknown or expired match", http.StatusNotFound)
+			return
+		}
+		resp := verificationResponse{MatchVerification: v}
+		horizon := time.Duration(v.DurationMs) * time.Millisecond
+		for i, seed := range v.Seeds {
+			round := roundSchedule{Round: i + 1, Seed: seed, Obstacles: []ws.ObstacleSpawn{}}
+			for _, o := range game.NewTimeline(seed).ObstaclesUntil(horizon) {
+				round.Obstacles = append(round.Obstacles, ws.ObstacleSpawn{
+					Kind: o.Type.String(),
+					At:   o.SpawnAt.Milliseconds(),
+					Gap:  o.Gap.Milliseconds(),
+				})
+			}
+			resp.Rounds = append(resp.Rounds, round)
+		}
+		writeJSON(w, resp)
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+// Leaderboard period values accepted by the period query parameter.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodAll     = "all"
+)
+
+// PeriodStart returns the UTC start of the calendar bucket containing now
+// for the given period. The boolean is false for unknown period values.
+// PeriodAll has no cutoff, so it also returns false.
+func PeriodStart(period string, now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	switch period {
+	case PeriodDaily:
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true
+	case PeriodWeekly:
+		// Weeks start on Monday 00:00 UTC.
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		monday := now.AddDate(0, 0, -daysSinceMonday)
+		return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC), true
+	case PeriodMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), true
+	default:
+		return time.Time{}, false
+	}
+}
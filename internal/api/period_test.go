@@ -0,0 +1,97 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		period string
+		now    time.Time
+		want   time.Time
+		ok     bool
+	}{
+		{
+			name:   "daily mid-afternoon",
+			period: PeriodDaily,
+			now:    time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC),
+			want:   time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "daily exactly at midnight starts the new day",
+			period: PeriodDaily,
+			now:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "daily one nanosecond before midnight is the old day",
+			period: PeriodDaily,
+			now:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			want:   time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "daily converts non-UTC input to UTC",
+			period: PeriodDaily,
+			now:    time.Date(2024, 3, 15, 1, 0, 0, 0, time.FixedZone("UTC+7", 7*3600)),
+			want:   time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "weekly starts on monday",
+			period: PeriodWeekly,
+			now:    time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC), // Friday
+			want:   time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),   // Monday
+			ok:     true,
+		},
+		{
+			name:   "weekly on a monday is that monday",
+			period: PeriodWeekly,
+			now:    time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "weekly on a sunday reaches back six days",
+			period: PeriodWeekly,
+			now:    time.Date(2024, 3, 17, 23, 59, 59, 0, time.UTC),
+			want:   time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "monthly",
+			period: PeriodMonthly,
+			now:    time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			ok:     true,
+		},
+		{
+			name:   "all has no cutoff",
+			period: PeriodAll,
+			now:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			ok:     false,
+		},
+		{
+			name:   "unknown period rejected",
+			period: "fortnightly",
+			now:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PeriodStart(tt.period, tt.now)
+			if ok != tt.ok {
+				t.Fatalf("PeriodStart(%q) ok = %v, want %v", tt.period, ok, tt.ok)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("PeriodStart(%q, %s) = %s, want %s", tt.period, tt.now, got, tt.want)
+			}
+		})
+	}
+}
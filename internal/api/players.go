@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+// PlayerProfile is the public profile returned by /api/players/{id},
+// combining aggregate match stats with the current rating.
+type PlayerProfile struct {
+	PlayerID   string  `json:"playerId"`
+	PlayerName string  `json:"playerName"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	BestScore  int     `json:"bestScore"`
+	AvgScore   float64 `json:"avgScore"`
+	BotGames   int     `json:"botGames"`
+	Rating     int     `json:"rating"`
+}
+
+// HandlePlayer serves the player endpoints:
+//
+//	GET    /api/players/{id}               public profile
+//	GET    /api/players/{id}/achievements  unlocked milestones and skin
+//	GET    /api/players/{id}/export        full personal-data dump (admin key)
+//	DELETE /api/players/{id}               GDPR erasure (admin key)
+func HandlePlayer(pool *pgxpool.Pool, adminKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID := strings.TrimPrefix(r.URL.Path, "/api/players/")
+		if export := strings.TrimSuffix(playerID, "/export"); export != playerID {
+			handleExport(w, r, pool, adminKey, export)
+			return
+		}
+		if id := strings.TrimSuffix(playerID, "/achievements"); id != playerID {
+			handleAchievements(w, r, pool, id)
+			return
+		}
+		if playerID == "" || strings.Contains(playerID, "/") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			handleErase(w, r, pool, adminKey, playerID)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		stats, err := db.GetStats(r.Context(), pool, playerID)
+		if err != nil {
+			slog.Error("player profile failed", "player_id", playerID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		rating, err := db.GetRating(r.Context(), pool, playerID)
+		if err != nil {
+			slog.Error("player profile failed", "player_id", playerID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		profile := PlayerProfile{
+			PlayerID:   playerID,
+			PlayerName: stats.PlayerName,
+			Games:      stats.Games,
+			Wins:       stats.Wins,
+			Losses:     stats.Losses,
+			Draws:      stats.Draws,
+			BestScore:  stats.BestScore,
+			AvgScore:   stats.AvgScore,
+			BotGames:   stats.BotGames,
+			Rating:     rating.Rating,
+		}
+		if profile.PlayerName == "" {
+			profile.PlayerName = rating.PlayerName
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(profile)
+	}
+}
+
+// PlayerAchievements is the public response of
+// /api/players/{id}/achievements: the player's unlocked milestones and the
+// skin they currently show off.
+type PlayerAchievements struct {
+	PlayerID     string           `json:"playerId"`
+	Skin         string           `json:"skin,omitempty"`
+	Achievements []db.Achievement `json:"achievements"`
+}
+
+// handleAchievements serves GET /api/players/{id}/achievements.
+func handleAchievements(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, playerID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if playerID == "" || strings.Contains(playerID, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	unlocked, err := db.ListAchievements(r.Context(), pool, playerID)
+	if err != nil {
+		slog.Error("player achievements failed", "player_id", playerID, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PlayerAchievements{
+		PlayerID:     playerID,
+		Skin:         db.SkinFor(unlocked),
+		Achievements: unlocked,
+	})
+}
+
+// privacyAuth gates the GDPR endpoints behind the admin key; with no key
+// configured they are disabled entirely, like the admin API.
+func privacyAuth(w http.ResponseWriter, r *http.Request, adminKey string) bool {
+	if adminKey == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return false
+	}
+	if r.Header.Get("X-Admin-Key") != adminKey {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleExport serves GET /api/players/{id}/export: every row stored about
+// the player as one JSON document.
+func handleExport(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, adminKey, playerID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if playerID == "" || strings.Contains(playerID, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if !privacyAuth(w, r, adminKey) {
+		return
+	}
+	export, err := db.ExportPlayer(r.Context(), pool, playerID)
+	if err != nil {
+		slog.Error("player export failed", "player_id", playerID, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+playerID+`-export.json"`)
+	json.NewEncoder(w).Encode(export)
+}
+
+// handleErase serves DELETE /api/players/{id}: remove or anonymize every
+// row referencing the player and report what went away.
+func handleErase(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, adminKey, playerID string) {
+	if !privacyAuth(w, r, adminKey) {
+		return
+	}
+	report, err := db.ErasePlayer(r.Context(), pool, playerID)
+	if err != nil {
+		slog.Error("player erasure failed", "player_id", playerID, "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("player erased", "player_id", playerID, "report", report)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// ScoreboardPlayer is one participant's live state on the scoreboard.
+type ScoreboardPlayer struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+	Alive bool   `json:"alive"`
+	// Team is only set in team mode.
+	Team string `json:"team,omitempty"`
+}
+
+// Scoreboard is the live state of one room as served by
+// /api/rooms/{id}/scoreboard.
+type Scoreboard struct {
+	RoomID     string             `json:"roomId"`
+	Players    []ScoreboardPlayer `json:"players"`
+	Spectators int                `json:"spectators"`
+}
+
+// HandleScoreboard serves GET /api/rooms/{id}/scoreboard: the live scores of
+// a running match straight from the hub, so stream overlays can poll match
+// state without holding a WebSocket connection.
+func HandleScoreboard(hub *ws.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
+		roomID, action, found := strings.Cut(rest, "/")
+		if !found || roomID == "" || action != "scoreboard" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		clients := hub.GetClientsInRoom(roomID)
+		if len(clients) == 0 {
+			http.Error(w, "room not found", http.StatusNotFound)
+			return
+		}
+
+		board := Scoreboard{
+			RoomID:     roomID,
+			Players:    make([]ScoreboardPlayer, 0, len(clients)),
+			Spectators: hub.SpectatorCount(roomID),
+		}
+		for _, c := range clients {
+			board.Players = append(board.Players, ScoreboardPlayer{
+				ID: c.ID, Name: c.Name, Score: c.Score, Alive: c.IsAlive, Team: c.Team,
+			})
+		}
+		writeJSON(w, board)
+	}
+}
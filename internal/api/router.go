@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/config"
+)
+
+// Middleware wraps an http.Handler, e.g. CORS.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares around h so that the first one listed sees the
+// request first.
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Routes names every endpoint of the game server by path; main constructs
+// the handlers and this package owns where they are mounted. Nil entries are
+// skipped, which keeps tests free to wire only what they exercise.
+type Routes struct {
+	// WS is the WebSocket endpoint, mounted at /ws.
+	WS http.HandlerFunc
+	// Leaderboard and LeaderboardStream serve /api/leaderboard and its SSE
+	// push channel; LeaderboardExport serves the bulk CSV/JSON download.
+	Leaderboard       http.HandlerFunc
+	LeaderboardStream http.HandlerFunc
+	LeaderboardExport http.HandlerFunc
+	// Players serves /api/players/<id> profiles.
+	Players http.HandlerFunc
+	// Rooms serves /api/rooms/<id>/scoreboard.
+	Rooms http.HandlerFunc
+	// Matches serves /api/matches/<id>/verification.
+	Matches http.HandlerFunc
+	// Scores serves /api/scores, the API key-protected REST score
+	// ingestion path.
+	Scores http.HandlerFunc
+	// Challenge serves /api/challenge/today, the daily-challenge board.
+	Challenge http.HandlerFunc
+	// Status serves the /api/status operational summary.
+	Status http.HandlerFunc
+	// Friends serves /api/friends and /api/friends/<id>.
+	Friends http.HandlerFunc
+	// Liveness and Readiness are the /healthz and /readyz probes.
+	Liveness  http.HandlerFunc
+	Readiness http.HandlerFunc
+	// Admin carries the authenticated /admin endpoints.
+	Admin *Admin
+	// Static serves the frontend from / as the fallback route.
+	Static http.Handler
+}
+
+// NewRouter assembles the server's mux from routes and wraps it in the given
+// middleware chain.
+func NewRouter(routes Routes, mws ...Middleware) http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		if h != nil {
+			mux.HandleFunc(pattern, h)
+		}
+	}
+	handle("/ws", routes.WS)
+	handle("/api/leaderboard", routes.Leaderboard)
+	handle("/api/leaderboard/stream", routes.LeaderboardStream)
+	handle("/api/leaderboard/export", routes.LeaderboardExport)
+	handle("/api/players/", routes.Players)
+	handle("/api/rooms/", routes.Rooms)
+	handle("/api/matches/", routes.Matches)
+	handle("/api/scores", routes.Scores)
+	handle("/api/challenge/today", routes.Challenge)
+	handle("/api/status", routes.Status)
+	handle("/api/friends", routes.Friends)
+	handle("/api/friends/", routes.Friends)
+	handle("/healthz", routes.Liveness)
+	handle("/readyz", routes.Readiness)
+	if routes.Admin != nil {
+		routes.Admin.Register(mux)
+	}
+	if routes.Static != nil {
+		mux.Handle("/", routes.Static)
+	}
+	return Chain(mux, mws...)
+}
+
+// NewServer wraps the router in an http.Server hardened against slow or
+// hostile clients: header reads and idle keep-alives are bounded, and header
+// size is capped. WriteTimeout is taken from config and defaults to zero
+// because /ws hijacks its connection and the leaderboard SSE stream writes
+// for as long as the viewer stays; a global write deadline would cut both
+// off.
+func NewServer(addr string, h http.Handler, cfg *config.Config) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
+	}
+}
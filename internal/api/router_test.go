@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/config"
+)
+
+// stubHandler records which route served the request.
+func stubHandler(hit *string, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*hit = name
+	}
+}
+
+func TestRouterMountsRoutes(t *testing.T) {
+	var hit string
+	router := NewRouter(Routes{
+		WS:          stubHandler(&hit, "ws"),
+		Leaderboard: stubHandler(&hit, "leaderboard"),
+		Status:      stubHandler(&hit, "status"),
+		Friends:     stubHandler(&hit, "friends"),
+		Liveness:    stubHandler(&hit, "liveness"),
+		Static:      stubHandler(&hit, "static"),
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/ws", "ws"},
+		{"/api/leaderboard", "leaderboard"},
+		{"/api/status", "status"},
+		{"/api/friends", "friends"},
+		{"/api/friends/f123", "friends"},
+		{"/healthz", "liveness"},
+		{"/index.html", "static"},
+	}
+	for _, tt := range tests {
+		hit = ""
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if hit != tt.want {
+			t.Errorf("%s served by %q, want %q", tt.path, hit, tt.want)
+		}
+	}
+
+	// A route with no handler wired falls through to the static fallback
+	// instead of panicking at registration time.
+	hit = ""
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/rooms/r1", nil))
+	if hit != "static" {
+		t.Errorf("unwired route served by %q, want the static fallback", hit)
+	}
+}
+
+func TestChainAppliesMiddlewareInOrder(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), mw("outer"), mw("inner"))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if len(order) != 3 || order[0] != "outer" || order[1] != "inner" || order[2] != "handler" {
+		t.Fatalf("execution order = %v, want [outer inner handler]", order)
+	}
+}
+
+func TestNewServerAppliesHardening(t *testing.T) {
+	cfg := &config.Config{
+		HTTPReadHeaderTimeout: 7 * time.Second,
+		HTTPIdleTimeout:       3 * time.Minute,
+		HTTPWriteTimeout:      0,
+		HTTPMaxHeaderBytes:    1 << 15,
+	}
+	srv := NewServer(":0", http.NewServeMux(), cfg)
+	if srv.ReadHeaderTimeout != cfg.HTTPReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %v, want %v", srv.ReadHeaderTimeout, cfg.HTTPReadHeaderTimeout)
+	}
+	if srv.IdleTimeout != cfg.HTTPIdleTimeout {
+		t.Errorf("IdleTimeout = %v, want %v", srv.IdleTimeout, cfg.HTTPIdleTimeout)
+	}
+	if srv.WriteTimeout != 0 {
+		t.Errorf("WriteTimeout = %v, want 0 so /ws and the SSE stream stay open", srv.WriteTimeout)
+	}
+	if srv.MaxHeaderBytes != cfg.HTTPMaxHeaderBytes {
+		t.Errorf("MaxHeaderBytes = %d, want %d", srv.MaxHeaderBytes, cfg.HTTPMaxHeaderBytes)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// maxExternalScore mirrors the WebSocket path's sanity ceiling on reported
+// scores, far above any legitimate run.
+const maxExternalScore = 1_000_000
+
+// scoreSubmission is the POST /api/scores request body.
+type scoreSubmission struct {
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`
+}
+
+// HandleScores serves /api/scores, the REST path for score submissions from
+// non-WebSocket clients such as an offline single-player mode. POST ingests
+// one run onto the external board — a category of its own, never mixed into
+// ranked multiplayer standings — under an integration's API key from keys
+// (X-API-Key header). Submissions face the same name, score-ceiling, and ban
+// checks as the WebSocket path. GET lists the external board's top runs,
+// optionally ?integration= filtered, and needs no key. With no keys
+// configured the endpoint is disabled entirely, like the admin API.
+func HandleScores(pool *pgxpool.Pool, keys map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(keys) == 0 {
+			http.Error(w, "score API disabled", http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			limit := queryInt(r, "limit", defaultPageSize)
+			if limit < 1 || limit > maxPageSize {
+				limit = defaultPageSize
+			}
+			scores, err := db.TopExternalScores(r.Context(), pool, r.URL.Query().Get("integration"), limit)
+			if err != nil {
+				slog.Error("external scores query failed", "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, scores)
+		case http.MethodPost:
+			integration := ""
+			key := r.Header.Get("X-API-Key")
+			for name, k := range keys {
+				if key != "" && k == key {
+					integration = name
+					break
+				}
+			}
+			if integration == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			var sub scoreSubmission
+			if err := json.NewDecoder(r.Body).Decode(&sub); err != nil || sub.PlayerID == "" {
+				http.Error(w, "playerId required", http.StatusBadRequest)
+				return
+			}
+			name, err := ws.ValidateDisplayName(sub.PlayerName)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if sub.Score < 0 || sub.Score > maxExternalScore {
+				http.Error(w, "score out of range", http.StatusBadRequest)
+				return
+			}
+			if banned, err := db.IsBanned(r.Context(), pool, sub.PlayerID); err != nil {
+				slog.Error("ban check failed", "player_id", sub.PlayerID, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			} else if banned {
+				http.Error(w, "account banned", http.StatusForbidden)
+				return
+			}
+			if err := db.InsertExternalScore(r.Context(), pool, integration, sub.PlayerID, name, sub.Score); err != nil {
+				slog.Error("insert external score failed", "integration", integration, "error", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			slog.Info("external score ingested", "integration", integration, "player_id", sub.PlayerID, "score", sub.Score)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
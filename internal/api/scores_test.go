@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The rejection paths run before any database access, so a nil pool is fine.
+func TestHandleScoresRejections(t *testing.T) {
+	keys := map[string]string{"offline-app": "secret"}
+	cases := []struct {
+		name   string
+		keys   map[string]string
+		method string
+		key    string
+		body   string
+		status int
+	}{
+		{"disabled without keys", nil, http.MethodPost, "", `{}`, http.StatusNotFound},
+		{"missing key", keys, http.MethodPost, "", `{"playerId":"p1","playerName":"Alice","score":10}`, http.StatusUnauthorized},
+		{"wrong key", keys, http.MethodPost, "nope", `{"playerId":"p1","playerName":"Alice","score":10}`, http.StatusUnauthorized},
+		{"missing playerId", keys, http.MethodPost, "secret", `{"playerName":"Alice","score":10}`, http.StatusBadRequest},
+		{"bad name", keys, http.MethodPost, "secret", `{"playerId":"p1","playerName":"<Alice>","score":10}`, http.StatusBadRequest},
+		{"negative score", keys, http.MethodPost, "secret", `{"playerId":"p1","playerName":"Alice","score":-1}`, http.StatusBadRequest},
+		{"absurd score", keys, http.MethodPost, "secret", `{"playerId":"p1","playerName":"Alice","score":2000000}`, http.StatusBadRequest},
+		{"bad method", keys, http.MethodDelete, "secret", ``, http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		h := HandleScores(nil, tc.keys)
+		req := httptest.NewRequest(tc.method, "/api/scores", strings.NewReader(tc.body))
+		if tc.key != "" {
+			req.Header.Set("X-API-Key", tc.key)
+		}
+		w := httptest.NewRecorder()
+		h(w, req)
+		if w.Code != tc.status {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.status)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/ws"
+)
+
+// StatusResponse is the live server snapshot served by /api/status.
+type StatusResponse struct {
+	ClientsOnline int    `json:"clientsOnline"`
+	QueueLength   int    `json:"queueLength"`
+	ActiveRooms   int    `json:"activeRooms"`
+	MatchesToday  int    `json:"matchesToday"`
+	Version       string `json:"version"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+}
+
+// Status serves GET /api/status: the numbers behind the frontend's
+// "players online" counter, in a shape ops dashboards can scrape as JSON.
+type Status struct {
+	hub        *ws.Hub
+	matchmaker *ws.Matchmaker
+	version    string
+	started    time.Time
+
+	mu sync.Mutex
+	// day anchors the matches-played counter; it resets at local midnight.
+	day     time.Time
+	matches int
+}
+
+// NewStatus wires the status endpoint against the hub and matchmaker.
+// version is whatever the build stamped into the binary.
+func NewStatus(hub *ws.Hub, matchmaker *ws.Matchmaker, version string) *Status {
+	now := time.Now()
+	return &Status{hub: hub, matchmaker: matchmaker, version: version, started: now, day: midnight(now)}
+}
+
+// CountMatch records one finished match; subscribe it to the event bus's
+// MatchFinished stream. The counter is in-process, so it restarts from zero
+// with the server.
+func (s *Status) CountMatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollover(time.Now())
+	s.matches++
+}
+
+// matchesToday returns the current day's match count.
+func (s *Status) matchesToday() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollover(time.Now())
+	return s.matches
+}
+
+// rollover resets the counter when the day changes. Caller holds s.mu.
+func (s *Status) rollover(now time.Time) {
+	if day := midnight(now); day.After(s.day) {
+		s.day, s.matches = day, 0
+	}
+}
+
+// midnight truncates a time to the start of its day.
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// Handle serves the endpoint.
+func (s *Status) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, StatusResponse{
+		ClientsOnline: s.hub.Count(),
+		QueueLength:   s.matchmaker.QueueLength(),
+		ActiveRooms:   s.hub.RoomCount(),
+		MatchesToday:  s.matchesToday(),
+		Version:       s.version,
+		UptimeSeconds: int64(time.Since(s.started).Seconds()),
+	})
+}
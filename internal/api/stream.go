@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+// Leaderboard stream tuning.
+const (
+	// streamTopN is how many entries the live leaderboard tracks and pushes.
+	streamTopN = 10
+	// streamQueryTimeout bounds the DB re-query triggered by a new score.
+	streamQueryTimeout = 2 * time.Second
+	// streamHeartbeat keeps idle SSE connections from being closed by
+	// intermediaries.
+	streamHeartbeat = 30 * time.Second
+)
+
+// LeaderboardStream pushes the current top N to subscribed clients over
+// Server-Sent Events whenever a newly saved score cracks it, so frontends
+// update in real time without polling.
+type LeaderboardStream struct {
+	repo db.LeaderboardRepository
+
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+	// minScore and count describe the cached top N so most scores are
+	// dismissed without touching the database.
+	minScore int
+	count    int
+}
+
+// NewLeaderboardStream returns a stream serving the repository's top scores.
+func NewLeaderboardStream(repo db.LeaderboardRepository) *LeaderboardStream {
+	return &LeaderboardStream{repo: repo, subs: make(map[chan []byte]struct{})}
+}
+
+// PublishScore is called after a score is persisted. If the score belongs in
+// the current top N, the refreshed standings are pushed to every subscriber.
+func (s *LeaderboardStream) PublishScore(playerID, playerName string, score int) {
+	s.mu.Lock()
+	qualifies := s.count < streamTopN || score > s.minScore
+	hasSubs := len(s.subs) > 0
+	s.mu.Unlock()
+	if !qualifies {
+		return
+	}
+
+	frame, err := s.snapshot()
+	if err != nil {
+		slog.Error("leaderboard stream refresh failed", "error", err)
+		return
+	}
+	if !hasSubs {
+		return
+	}
+	s.mu.Lock()
+	for sub := range s.subs {
+		select {
+		case sub <- frame:
+		default:
+			// Slow subscriber; it will catch up on the next push.
+		}
+	}
+	s.mu.Unlock()
+}
+
+// snapshot re-queries the top N, refreshes the qualification cache, and
+// returns the standings encoded as one SSE data payload.
+func (s *LeaderboardStream) snapshot() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), streamQueryTimeout)
+	defer cancel()
+	rows, err := s.repo.TopScores(ctx, streamTopN)
+	if err != nil {
+		return nil, fmt.Errorf("query top scores: %w", err)
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, LeaderboardEntry{
+			Rank:       len(entries) + 1,
+			PlayerID:   row.PlayerID,
+			PlayerName: row.PlayerName,
+			Score:      row.Score,
+			RoomID:     row.RoomID,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+
+	s.mu.Lock()
+	s.count = len(entries)
+	s.minScore = 0
+	if len(entries) > 0 {
+		s.minScore = entries[len(entries)-1].Score
+	}
+	s.mu.Unlock()
+
+	return json.Marshal(entries)
+}
+
+// Handle serves GET /api/leaderboard/stream as an SSE endpoint. Each event's
+// data is the full top-N standings as a JSON array.
+func (s *LeaderboardStream) Handle(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	sub := make(chan []byte, 1)
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, sub)
+		s.mu.Unlock()
+	}()
+
+	// Send the current standings straight away so new subscribers don't wait
+	// for the next score.
+	if frame, err := s.snapshot(); err == nil {
+		fmt.Fprintf(w, "data: %s\n\n", frame)
+		flusher.Flush()
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame := <-sub:
+			fmt.Fprintf(w, "data: %s\n\n", frame)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
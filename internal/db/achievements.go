@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Achievement milestones. Each one unlocks the cosmetic skin named in
+// AchievementSkins.
+const (
+	// AchievementFirstWin marks a player's first match win.
+	AchievementFirstWin = "first_win"
+	// AchievementScore1K marks a run reaching AchievementScoreTarget points.
+	AchievementScore1K = "score_1000"
+	// AchievementStreak10 marks AchievementStreakTarget consecutive wins.
+	AchievementStreak10 = "streak_10"
+)
+
+// Thresholds behind the score and streak milestones.
+const (
+	AchievementScoreTarget  = 1000
+	AchievementStreakTarget = 10
+)
+
+// AchievementSkins maps each milestone to the dino skin it unlocks. The IDs
+// are opaque to the server; clients pick the matching sprite sheet.
+var AchievementSkins = map[string]string{
+	AchievementFirstWin: "dino_gold",
+	AchievementScore1K:  "dino_neon",
+	AchievementStreak10: "dino_inferno",
+}
+
+// skinPrecedence orders milestones from hardest to easiest; SkinFor shows a
+// player off with the best skin they have earned.
+var skinPrecedence = []string{AchievementStreak10, AchievementScore1K, AchievementFirstWin}
+
+// Achievement is one unlocked milestone.
+type Achievement struct {
+	Name       string    `json:"name"`
+	UnlockedAt time.Time `json:"unlockedAt"`
+}
+
+// SkinFor returns the skin ID for the best milestone in unlocked, or ""
+// for the default dino.
+func SkinFor(unlocked []Achievement) string {
+	for _, name := range skinPrecedence {
+		for _, a := range unlocked {
+			if a.Name == name {
+				return AchievementSkins[name]
+			}
+		}
+	}
+	return ""
+}
+
+// UnlockAchievement records a milestone for a player; repeat unlocks are
+// no-ops.
+func UnlockAchievement(ctx context.Context, pool *pgxpool.Pool, playerID, name string) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO achievements (player_id, achievement)
+		VALUES ($1, $2)
+		ON CONFLICT (player_id, achievement) DO NOTHING`,
+		playerID, name)
+	if err != nil {
+		return fmt.Errorf("unlock achievement: %w", err)
+	}
+	return nil
+}
+
+// ListAchievements returns a player's unlocked milestones, oldest first.
+func ListAchievements(ctx context.Context, pool *pgxpool.Pool, playerID string) ([]Achievement, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT achievement, unlocked_at
+		FROM achievements
+		WHERE player_id = $1
+		ORDER BY unlocked_at ASC`, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+	defer rows.Close()
+	unlocked := []Achievement{}
+	for rows.Next() {
+		var a Achievement
+		if err := rows.Scan(&a.Name, &a.UnlockedAt); err != nil {
+			return nil, fmt.Errorf("list achievements: %w", err)
+		}
+		unlocked = append(unlocked, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+	return unlocked, nil
+}
+
+// WinStreak returns a player's current run of consecutive wins; players
+// without a stats row are on a streak of zero.
+func WinStreak(ctx context.Context, pool *pgxpool.Pool, playerID string) (int, error) {
+	var streak int
+	err := pool.QueryRow(ctx,
+		`SELECT win_streak FROM player_stats WHERE player_id = $1`,
+		playerID).Scan(&streak)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get win streak: %w", err)
+	}
+	return streak, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ban kinds.
+const (
+	BanKindPlayer = "player"
+	BanKindIP     = "ip"
+)
+
+// Ban is one row of the bans table.
+type Ban struct {
+	ID        int64     `json:"id"`
+	Target    string    `json:"target"`
+	Kind      string    `json:"kind"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AddBan records a ban on a player ID or IP address.
+func AddBan(ctx context.Context, pool *pgxpool.Pool, target, kind, reason string) error {
+	_, err := pool.Exec(ctx,
+		`INSERT INTO bans (target, kind, reason) VALUES ($1, $2, $3)`,
+		target, kind, reason)
+	if err != nil {
+		return fmt.Errorf("add ban: %w", err)
+	}
+	return nil
+}
+
+// RemoveBan lifts every ban on a target.
+func RemoveBan(ctx context.Context, pool *pgxpool.Pool, target string) error {
+	_, err := pool.Exec(ctx, `DELETE FROM bans WHERE target = $1`, target)
+	if err != nil {
+		return fmt.Errorf("remove ban: %w", err)
+	}
+	return nil
+}
+
+// IsBanned reports whether a player ID or IP address is banned.
+func IsBanned(ctx context.Context, pool *pgxpool.Pool, target string) (bool, error) {
+	var banned bool
+	err := pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM bans WHERE target = $1)`, target).Scan(&banned)
+	if err != nil {
+		return false, fmt.Errorf("check ban: %w", err)
+	}
+	return banned, nil
+}
+
+// ListBans returns every active ban.
+func ListBans(ctx context.Context, pool *pgxpool.Pool) ([]Ban, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT id, target, kind, reason, created_at FROM bans ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list bans: %w", err)
+	}
+	defer rows.Close()
+	var bans []Ban
+	for rows.Next() {
+		var b Ban
+		if err := rows.Scan(&b.ID, &b.Target, &b.Kind, &b.Reason, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan ban: %w", err)
+		}
+		bans = append(bans, b)
+	}
+	return bans, rows.Err()
+}
+
+// DeleteScores removes every leaderboard row for a player, returning how
+// many rows went away.
+func DeleteScores(ctx context.Context, pool *pgxpool.Pool, playerID string) (int64, error) {
+	tag, err := pool.Exec(ctx, `DELETE FROM leaderboard WHERE player_id = $1`, playerID)
+	if err != nil {
+		return 0, fmt.Errorf("delete scores: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
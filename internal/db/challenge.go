@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChallengeRow is one player's best daily-challenge attempt.
+type ChallengeRow struct {
+	PlayerID   string    `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	Score      int       `json:"score"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// SaveChallengeScore records one daily-challenge attempt, keeping the
+// player's best score of the day: attempts below the stored best change
+// nothing.
+func SaveChallengeScore(ctx context.Context, pool *pgxpool.Pool, day, playerID, playerName string, score int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := pool.Exec(ctx, `
+		INSERT INTO challenge_scores (challenge_day, player_id, player_name, score)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (challenge_day, player_id) DO UPDATE
+		SET score = EXCLUDED.score, player_name = EXCLUDED.player_name, updated_at = now()
+		WHERE challenge_scores.score < EXCLUDED.score`,
+		day, playerID, playerName, score)
+	if err != nil {
+		return fmt.Errorf("save challenge score: %w", err)
+	}
+	return nil
+}
+
+// TopChallengeScores returns one day's challenge board, best first; ties go
+// to whoever set the score earlier.
+func TopChallengeScores(ctx context.Context, pool *pgxpool.Pool, day string, limit int) ([]ChallengeRow, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := pool.Query(ctx, `
+		SELECT player_id, player_name, score, updated_at
+		FROM challenge_scores
+		WHERE challenge_day = $1
+		ORDER BY score DESC, updated_at ASC
+		LIMIT $2`, day, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list challenge scores: %w", err)
+	}
+	defer rows.Close()
+	board := []ChallengeRow{}
+	for rows.Next() {
+		var row ChallengeRow
+		if err := rows.Scan(&row.PlayerID, &row.PlayerName, &row.Score, &row.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan challenge score: %w", err)
+		}
+		board = append(board, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list challenge scores: %w", err)
+	}
+	return board, nil
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reasons recorded into cheat_flags.
+const (
+	// CheatReasonScoreJump marks a score that moved backwards or grew
+	// faster than the per-update delta cap allows.
+	CheatReasonScoreJump = "score_jump"
+	// CheatReasonScoreEnvelope marks a score above the points/second
+	// envelope derived from the match's elapsed time.
+	CheatReasonScoreEnvelope = "score_envelope"
+	// CheatReasonReported marks a flag an admin raised while reviewing a
+	// player report, rather than an automatic rejection.
+	CheatReasonReported = "player_report"
+)
+
+// CheatFlag is one recorded anti-cheat rejection, kept for admin review.
+type CheatFlag struct {
+	ID         int64     `json:"id"`
+	PlayerID   string    `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	RoomID     string    `json:"roomId"`
+	Score      int       `json:"score"`
+	Reason     string    `json:"reason"`
+	Detail     string    `json:"detail"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// RecordCheatFlag stores one anti-cheat rejection.
+func RecordCheatFlag(ctx context.Context, pool *pgxpool.Pool, playerID, playerName, roomID string, score int, reason, detail string) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO cheat_flags (player_id, player_name, room_id, score, reason, detail)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		playerID, playerName, roomID, score, reason, detail)
+	if err != nil {
+		return fmt.Errorf("record cheat flag: %w", err)
+	}
+	return nil
+}
+
+// ListCheatFlags returns recent flags, newest first, optionally filtered to
+// one player.
+func ListCheatFlags(ctx context.Context, pool *pgxpool.Pool, playerID string, limit int) ([]CheatFlag, error) {
+	query := `
+		SELECT id, player_id, player_name, room_id, score, reason, detail, created_at
+		FROM cheat_flags`
+	args := []interface{}{}
+	if playerID != "" {
+		query += ` WHERE player_id = $1`
+		args = append(args, playerID)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list cheat flags: %w", err)
+	}
+	defer rows.Close()
+	flags := []CheatFlag{}
+	for rows.Next() {
+		var f CheatFlag
+		if err := rows.Scan(&f.ID, &f.PlayerID, &f.PlayerName, &f.RoomID, &f.Score, &f.Reason, &f.Detail, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("list cheat flags: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	return flags, rows.Err()
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExternalScore is one row of the external board, fed by REST submissions
+// from non-WebSocket clients such as the offline single-player mode.
+type ExternalScore struct {
+	PlayerID    string    `json:"playerId"`
+	PlayerName  string    `json:"playerName"`
+	Score       int       `json:"score"`
+	Integration string    `json:"integration"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// InsertExternalScore stores one submitted run on the external board.
+// Submissions are low-volume and callers want a definitive answer, so unlike
+// leaderboard writes there is no async queue in front.
+func InsertExternalScore(ctx context.Context, pool *pgxpool.Pool, integration, playerID, playerName string, score int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := pool.Exec(ctx, `
+		INSERT INTO external_scores (integration, player_id, player_name, score)
+		VALUES ($1, $2, $3, $4)`, integration, playerID, playerName, score)
+	if err != nil {
+		return fmt.Errorf("insert external score: %w", err)
+	}
+	return nil
+}
+
+// TopExternalScores returns the external board's highest runs, best first,
+// optionally restricted to one integration.
+func TopExternalScores(ctx context.Context, pool *pgxpool.Pool, integration string, limit int) ([]ExternalScore, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT player_id, player_name, score, integration, created_at
+		FROM external_scores`
+	args := []interface{}{limit}
+	if integration != "" {
+		query += ` WHERE integration = $2`
+		args = append(args, integration)
+	}
+	query += ` ORDER BY score DESC, created_at ASC LIMIT $1`
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("top external scores: %w", err)
+	}
+	defer rows.Close()
+	scores := []ExternalScore{}
+	for rows.Next() {
+		var s ExternalScore
+		if err := rows.Scan(&s.PlayerID, &s.PlayerName, &s.Score, &s.Integration, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("top external scores: %w", err)
+		}
+		scores = append(scores, s)
+	}
+	return scores, rows.Err()
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FriendRequest is one pending row of the friend_requests table.
+type FriendRequest struct {
+	FromID    string    `json:"fromId"`
+	FromName  string    `json:"fromName"`
+	ToID      string    `json:"toId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Friend is one accepted friendship as seen from one player's side.
+type Friend struct {
+	PlayerID  string    `json:"playerId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SendFriendRequest records a pending request from one player to another.
+// Re-sending the same request is a no-op.
+func SendFriendRequest(ctx context.Context, pool *pgxpool.Pool, fromID, fromName, toID string) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO friend_requests (from_id, from_name, to_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (from_id, to_id) DO NOTHING`,
+		fromID, fromName, toID)
+	if err != nil {
+		return fmt.Errorf("send friend request: %w", err)
+	}
+	return nil
+}
+
+// AcceptFriendRequest turns a pending request into an accepted pair, stored
+// once per direction so each player's friend list is a single-column lookup.
+// It reports whether a pending request actually existed.
+func AcceptFriendRequest(ctx context.Context, pool *pgxpool.Pool, fromID, toID string) (bool, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("accept friend request: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`DELETE FROM friend_requests WHERE from_id = $1 AND to_id = $2`, fromID, toID)
+	if err != nil {
+		return false, fmt.Errorf("accept friend request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO friends (player_id, friend_id)
+		VALUES ($1, $2), ($2, $1)
+		ON CONFLICT (player_id, friend_id) DO NOTHING`,
+		fromID, toID)
+	if err != nil {
+		return false, fmt.Errorf("accept friend request: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("accept friend request: %w", err)
+	}
+	return true, nil
+}
+
+// DeclineFriendRequest drops a pending request without creating a pair.
+func DeclineFriendRequest(ctx context.Context, pool *pgxpool.Pool, fromID, toID string) error {
+	_, err := pool.Exec(ctx,
+		`DELETE FROM friend_requests WHERE from_id = $1 AND to_id = $2`, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("decline friend request: %w", err)
+	}
+	return nil
+}
+
+// RemoveFriend deletes both directions of an accepted pair.
+func RemoveFriend(ctx context.Context, pool *pgxpool.Pool, playerID, friendID string) error {
+	_, err := pool.Exec(ctx, `
+		DELETE FROM friends
+		WHERE (player_id = $1 AND friend_id = $2) OR (player_id = $2 AND friend_id = $1)`,
+		playerID, friendID)
+	if err != nil {
+		return fmt.Errorf("remove friend: %w", err)
+	}
+	return nil
+}
+
+// ListFriends returns a player's accepted friends, newest first.
+func ListFriends(ctx context.Context, pool *pgxpool.Pool, playerID string) ([]Friend, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT friend_id, created_at FROM friends
+		WHERE player_id = $1 ORDER BY created_at DESC`, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("list friends: %w", err)
+	}
+	defer rows.Close()
+	friends := []Friend{}
+	for rows.Next() {
+		var f Friend
+		if err := rows.Scan(&f.PlayerID, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan friend: %w", err)
+		}
+		friends = append(friends, f)
+	}
+	return friends, rows.Err()
+}
+
+// ListFriendRequests returns the pending requests addressed to a player.
+func ListFriendRequests(ctx context.Context, pool *pgxpool.Pool, playerID string) ([]FriendRequest, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT from_id, from_name, to_id, created_at FROM friend_requests
+		WHERE to_id = $1 ORDER BY created_at DESC`, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("list friend requests: %w", err)
+	}
+	defer rows.Close()
+	requests := []FriendRequest{}
+	for rows.Next() {
+		var fr FriendRequest
+		if err := rows.Scan(&fr.FromID, &fr.FromName, &fr.ToID, &fr.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan friend request: %w", err)
+		}
+		requests = append(requests, fr)
+	}
+	return requests, rows.Err()
+}
+
+// AreFriends reports whether two players have an accepted friendship.
+func AreFriends(ctx context.Context, pool *pgxpool.Pool, playerID, friendID string) (bool, error) {
+	var friends bool
+	err := pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM friends WHERE player_id = $1 AND friend_id = $2)`,
+		playerID, friendID).Scan(&friends)
+	if err != nil {
+		return false, fmt.Errorf("check friendship: %w", err)
+	}
+	return friends, nil
+}
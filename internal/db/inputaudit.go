@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Input writer tuning. Inputs arrive far more often than score updates --
+// every jump and duck from every live player -- so the queue is the largest
+// of the three async writers.
+const (
+	inputQueueSize     = 8192
+	inputBatchSize     = 500
+	inputFlushInterval = time.Second
+)
+
+// ErrInputQueueFull is returned when the input log queue has no room left.
+var ErrInputQueueFull = errors.New("db: input log queue full")
+
+// InputEvent is one accepted jump/duck action in the replay log. ClientTS is
+// the client's own clock in Unix milliseconds; RecordedAt is the server's.
+type InputEvent struct {
+	RoomID     string    `json:"roomId"`
+	PlayerID   string    `json:"playerId"`
+	Action     string    `json:"action"`
+	ClientTS   int64     `json:"clientTs"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// ListInputEvents returns one player's logged inputs from one room in client
+// clock order, ready to feed back into a replay simulation.
+func ListInputEvents(ctx context.Context, pool *pgxpool.Pool, roomID, playerID string, limit int) ([]InputEvent, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT room_id, player_id, action, client_ts, recorded_at
+		FROM input_events
+		WHERE room_id = $1 AND player_id = $2
+		ORDER BY client_ts ASC, recorded_at ASC
+		LIMIT $3`, roomID, playerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list input events: %w", err)
+	}
+	defer rows.Close()
+	events := []InputEvent{}
+	for rows.Next() {
+		var e InputEvent
+		if err := rows.Scan(&e.RoomID, &e.PlayerID, &e.Action, &e.ClientTS, &e.RecordedAt); err != nil {
+			return nil, fmt.Errorf("list input events: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// SetScoreVerified is the replay verifier's write-back: it flips the verified
+// flag on a player's leaderboard rows at the given score and reports how many
+// rows changed.
+func SetScoreVerified(ctx context.Context, pool *pgxpool.Pool, playerID string, score int, verified bool) (int64, error) {
+	tag, err := pool.Exec(ctx, `
+		UPDATE leaderboard SET verified = $3
+		WHERE player_id = $1 AND score = $2 AND verified <> $3`,
+		playerID, score, verified)
+	if err != nil {
+		return 0, fmt.Errorf("set score verified: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// InputWriter appends accepted player inputs to input_events asynchronously,
+// following the AuditWriter's no-retry stance: the log is replay evidence,
+// not game state, so a batch that fails against a struggling database is
+// logged and dropped. Pruning rides the score audit's retention window,
+// since a replay without its score trail is no more useful than the reverse.
+type InputWriter struct {
+	pool  *pgxpool.Pool
+	queue chan InputEvent
+	done  chan struct{}
+}
+
+// NewInputWriter starts the input log worker and its retention pruner.
+func NewInputWriter(pool *pgxpool.Pool) *InputWriter {
+	w := &InputWriter{
+		pool:  pool,
+		queue: make(chan InputEvent, inputQueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	go w.prune()
+	return w
+}
+
+// Enqueue queues one accepted input. It never blocks: when the queue is full
+// the event is dropped and ErrInputQueueFull comes back.
+func (w *InputWriter) Enqueue(roomID, playerID, action string, clientTS int64) error {
+	e := InputEvent{RoomID: roomID, PlayerID: playerID, Action: action, ClientTS: clientTS, RecordedAt: time.Now()}
+	select {
+	case w.queue <- e:
+		return nil
+	default:
+		return ErrInputQueueFull
+	}
+}
+
+// Close stops accepting inputs and blocks until the queue has drained.
+func (w *InputWriter) Close() {
+	close(w.queue)
+	<-w.done
+}
+
+// run collects queued inputs into batches and flushes them when a batch
+// fills up or the flush interval elapses.
+func (w *InputWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(inputFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]InputEvent, 0, inputBatchSize)
+	for {
+		select {
+		case e, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= inputBatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush writes one batch in a single multi-row INSERT, dropping it on error.
+func (w *InputWriter) flush(batch []InputEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO input_events (room_id, player_id, action, client_ts, recorded_at) VALUES `)
+	args := make([]interface{}, 0, len(batch)*5)
+	for i, e := range batch {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		base := i * 5
+		sb.WriteString("($" + strconv.Itoa(base+1) + ", $" + strconv.Itoa(base+2) +
+			", $" + strconv.Itoa(base+3) + ", $" + strconv.Itoa(base+4) + ", $" + strconv.Itoa(base+5) + ")")
+		args = append(args, e.RoomID, e.PlayerID, e.Action, e.ClientTS, e.RecordedAt)
+	}
+	if _, err := w.pool.Exec(ctx, sb.String(), args...); err != nil {
+		slog.Warn("input log batch dropped", "rows", len(batch), "error", err)
+	}
+}
+
+// prune deletes input rows older than the score audit retention window, once
+// per interval, until the writer is closed.
+func (w *InputWriter) prune() {
+	ticker := time.NewTicker(auditPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if ScoreEventRetention <= 0 {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			tag, err := w.pool.Exec(ctx, `DELETE FROM input_events WHERE recorded_at < $1`,
+				time.Now().Add(-ScoreEventRetention))
+			cancel()
+			if err != nil {
+				slog.Error("input log prune failed", "error", err)
+				continue
+			}
+			if tag.RowsAffected() > 0 {
+				slog.Info("input log pruned", "rows", tag.RowsAffected(), "retention", ScoreEventRetention)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
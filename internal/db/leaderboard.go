@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+)
+
+// LeaderboardFilter collects the optional WHERE conditions of a board query.
+// Each expr is a fmt template whose %d receives its argument's placeholder
+// number when a query is built, so one filter serves queries whose fixed
+// argument counts differ.
+type LeaderboardFilter struct {
+	exprs []string
+	args  []interface{}
+}
+
+// Add appends one condition, e.g. Add("score >= $%d", 100).
+func (f *LeaderboardFilter) Add(expr string, arg interface{}) {
+	f.exprs = append(f.exprs, expr)
+	f.args = append(f.args, arg)
+}
+
+// conds renders the conditions with placeholders numbered from first.
+func (f *LeaderboardFilter) conds(first int) []string {
+	rendered := make([]string, len(f.exprs))
+	for i, expr := range f.exprs {
+		rendered[i] = fmt.Sprintf(expr, first+i)
+	}
+	return rendered
+}
+
+// clause renders the full WHERE clause with placeholders numbered from
+// first, or "" for an empty filter.
+func (f *LeaderboardFilter) clause(first int) string {
+	if len(f.exprs) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(f.conds(first), " AND ")
+}
+
+// LeaderboardRow is one board row including its row id, which keyset
+// pagination cursors point at.
+type LeaderboardRow struct {
+	ID int64
+	ScoreRow
+}
+
+// CountLeaderboard counts the board rows matching the filter; best counts
+// each player once.
+func CountLeaderboard(ctx context.Context, pool *pgxpool.Pool, f *LeaderboardFilter, best bool) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query := `SELECT COUNT(*) FROM leaderboard` + f.clause(1)
+	if best {
+		query = `SELECT COUNT(DISTINCT player_id) FROM leaderboard` + f.clause(1)
+	}
+	var total int
+	if err := pool.QueryRow(ctx, query, f.args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("count leaderboard: %w", err)
+	}
+	return total, nil
+}
+
+// ListLeaderboard returns one offset-paged slice of the board in (score
+// DESC, id ASC) order. best collapses the board to each player's highest
+// run, so a single player cannot fill a whole page.
+func ListLeaderboard(ctx context.Context, pool *pgxpool.Pool, f *LeaderboardFilter, best bool, limit, offset int) ([]LeaderboardRow, error) {
+	ctx, span := tracing.Start(ctx, "db.leaderboard_query")
+	defer span.End()
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	filter := f.clause(3)
+	args := append([]interface{}{limit, offset}, f.args...)
+	query := `
+		SELECT id, player_id, player_name, score, verified, room_id, created_at
+		FROM leaderboard` + filter + `
+		ORDER BY score DESC, id ASC
+		LIMIT $1 OFFSET $2`
+	if best {
+		query = `
+			SELECT id, player_id, player_name, score, verified, room_id, created_at FROM (
+				SELECT DISTINCT ON (player_id) id, player_id, player_name, score, verified, room_id, created_at
+				FROM leaderboard` + filter + `
+				ORDER BY player_id, score DESC, created_at ASC
+			) best
+			ORDER BY score DESC, id ASC
+			LIMIT $1 OFFSET $2`
+	}
+	rows, err := pool.Query(ctx, query, args...)
+	return scanLeaderboardRows(rows, err)
+}
+
+// ListLeaderboardAfter pages the board by keyset: rows strictly after the
+// (score, id) position in (score DESC, id ASC) order, so concurrent inserts
+// can't duplicate or skip rows.
+func ListLeaderboardAfter(ctx context.Context, pool *pgxpool.Pool, f *LeaderboardFilter, score int, id int64, limit int) ([]LeaderboardRow, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	n := len(f.args)
+	conds := append(f.conds(1),
+		fmt.Sprintf("(score < $%d OR (score = $%d AND id > $%d))", n+1, n+1, n+2))
+	args := append(append([]interface{}{}, f.args...), score, id, limit)
+	query := `
+		SELECT id, player_id, player_name, score, verified, room_id, created_at
+		FROM leaderboard WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY score DESC, id ASC
+		LIMIT $` + strconv.Itoa(n+3)
+	rows, err := pool.Query(ctx, query, args...)
+	return scanLeaderboardRows(rows, err)
+}
+
+// ScoreRank returns the global board position a run of the given score lands
+// at: one more than the number of strictly higher runs. It doesn't depend on
+// the run's own row, which may still be in the async persistence queue.
+func ScoreRank(ctx context.Context, pool *pgxpool.Pool, score int) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var higher int
+	if err := pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM leaderboard WHERE score > $1`, score).Scan(&higher); err != nil {
+		return 0, fmt.Errorf("score rank: %w", err)
+	}
+	return higher + 1, nil
+}
+
+// scanLeaderboardRows drains one board query into rows, folding the query
+// error in so callers handle a single error site.
+func scanLeaderboardRows(rows pgx.Rows, err error) ([]LeaderboardRow, error) {
+	if err != nil {
+		return nil, fmt.Errorf("list leaderboard: %w", err)
+	}
+	defer rows.Close()
+	list := []LeaderboardRow{}
+	for rows.Next() {
+		var row LeaderboardRow
+		if err := rows.Scan(&row.ID, &row.PlayerID, &row.PlayerName, &row.Score, &row.Verified, &row.RoomID, &row.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan leaderboard: %w", err)
+		}
+		list = append(list, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list leaderboard: %w", err)
+	}
+	return list, nil
+}
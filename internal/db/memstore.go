@@ -0,0 +1,303 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// MemStore is an in-memory Store for tests and Postgres-free development.
+type MemStore struct {
+	mu sync.Mutex
+	// Scores holds every saved run in insertion order.
+	Scores []ScoreRow
+	// Ratings and Banned mirror player_ratings and bans by player ID.
+	Ratings map[string]Rating
+	Banned  map[string]bool
+	// StatsByPlayer mirrors player_stats, including the derived AvgScore.
+	StatsByPlayer map[string]Stats
+	// Friends holds accepted pairs, both directions.
+	Friends map[string]map[string]bool
+	// Flags holds recorded anti-cheat rejections in insertion order.
+	Flags []CheatFlag
+	// Events holds the score audit trail in insertion order.
+	Events []ScoreEvent
+	// Inputs holds the replay input log in insertion order.
+	Inputs []InputEvent
+	// Reports holds filed player reports in insertion order.
+	Reports []Report
+	// Unlocked holds each player's achievements in unlock order.
+	Unlocked map[string][]Achievement
+	// ChallengeBests holds daily-challenge bests by day key, then player ID.
+	ChallengeBests map[string]map[string]ChallengeRow
+	// DailyScoreCap mirrors the ScoreWriter daily cap; zero keeps everything.
+	DailyScoreCap int
+	totals        map[string]int
+}
+
+// NewMemStore returns an empty in-memory store.
+func NewMemStore() *MemStore {
+	return &MemStore{
+		Ratings:        make(map[string]Rating),
+		Banned:         make(map[string]bool),
+		StatsByPlayer:  make(map[string]Stats),
+		Friends:        make(map[string]map[string]bool),
+		Unlocked:       make(map[string][]Achievement),
+		ChallengeBests: make(map[string]map[string]ChallengeRow),
+		totals:         make(map[string]int),
+	}
+}
+
+func (s *MemStore) SaveScore(ctx context.Context, playerID, playerName string, score int, country, roomID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	today, best := 0, -1
+	dayStart := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, row := range s.Scores {
+		if row.PlayerID != playerID {
+			continue
+		}
+		if !row.CreatedAt.Before(dayStart) {
+			today++
+		}
+		if row.Score > best {
+			best = row.Score
+		}
+	}
+	if !keepScore(today, s.DailyScoreCap, score, best) {
+		return nil
+	}
+	s.Scores = append(s.Scores, ScoreRow{
+		PlayerID: playerID, PlayerName: playerName, Score: score,
+		Verified: Verified(playerID), Country: country, RoomID: roomID, CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) RecordCheatFlag(ctx context.Context, playerID, playerName, roomID string, score int, reason, detail string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Flags = append(s.Flags, CheatFlag{
+		PlayerID: playerID, PlayerName: playerName, RoomID: roomID,
+		Score: score, Reason: reason, Detail: detail, CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) RecordScoreEvent(ctx context.Context, playerID, roomID string, score int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Events = append(s.Events, ScoreEvent{
+		PlayerID: playerID, RoomID: roomID, Score: score, RecordedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) RecordInput(ctx context.Context, roomID, playerID, action string, clientTS int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Inputs = append(s.Inputs, InputEvent{
+		RoomID: roomID, PlayerID: playerID, Action: action, ClientTS: clientTS, RecordedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) RecordReport(ctx context.Context, reporterID, reporterName, reportedID, reportedName, roomID, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rep := range s.Reports {
+		if rep.ReporterID == reporterID && rep.ReportedID == reportedID && rep.RoomID == roomID {
+			return nil
+		}
+	}
+	s.Reports = append(s.Reports, Report{
+		ReporterID: reporterID, ReporterName: reporterName,
+		ReportedID: reportedID, ReportedName: reportedName,
+		RoomID: roomID, Reason: reason,
+		Status: ReportStatusOpen, CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) RecordMatchResult(ctx context.Context, playerID, playerName string, score int, outcome string, vsBot bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.StatsByPlayer[playerID]
+	st.PlayerID = playerID
+	st.PlayerName = playerName
+	st.Games++
+	switch outcome {
+	case OutcomeWin:
+		st.Wins++
+	case OutcomeLoss:
+		st.Losses++
+	case OutcomeDraw:
+		st.Draws++
+	}
+	if vsBot {
+		st.BotGames++
+	}
+	if outcome == OutcomeWin {
+		st.WinStreak++
+	} else {
+		st.WinStreak = 0
+	}
+	if score > st.BestScore {
+		st.BestScore = score
+	}
+	s.totals[playerID] += score
+	st.AvgScore = float64(s.totals[playerID]) / float64(st.Games)
+	s.StatsByPlayer[playerID] = st
+	return nil
+}
+
+func (s *MemStore) GetRating(ctx context.Context, playerID string) (Rating, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.Ratings[playerID]; ok {
+		return r, nil
+	}
+	return Rating{PlayerID: playerID, Rating: game.DefaultRating}, nil
+}
+
+func (s *MemStore) SaveRating(ctx context.Context, playerID, playerName string, rating int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.Ratings[playerID]
+	r.PlayerID = playerID
+	r.PlayerName = playerName
+	r.Rating = rating
+	r.Games++
+	s.Ratings[playerID] = r
+	return nil
+}
+
+func (s *MemStore) IsBanned(ctx context.Context, target string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Banned[target], nil
+}
+
+func (s *MemStore) NameTaken(ctx context.Context, name, playerID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lower := strings.ToLower(name)
+	for id, st := range s.StatsByPlayer {
+		if id != playerID && strings.ToLower(st.PlayerName) == lower {
+			return true, nil
+		}
+	}
+	for id, r := range s.Ratings {
+		if id != playerID && strings.ToLower(r.PlayerName) == lower {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemStore) AreFriends(ctx context.Context, playerID, friendID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Friends[playerID][friendID], nil
+}
+
+func (s *MemStore) BestScore(ctx context.Context, playerID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.StatsByPlayer[playerID].BestScore, nil
+}
+
+func (s *MemStore) ScoreRank(ctx context.Context, score int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	higher := 0
+	for _, row := range s.Scores {
+		if row.Score > score {
+			higher++
+		}
+	}
+	return higher + 1, nil
+}
+
+func (s *MemStore) UnlockAchievement(ctx context.Context, playerID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range s.Unlocked[playerID] {
+		if a.Name == name {
+			return nil
+		}
+	}
+	s.Unlocked[playerID] = append(s.Unlocked[playerID], Achievement{Name: name, UnlockedAt: time.Now()})
+	return nil
+}
+
+func (s *MemStore) ListAchievements(ctx context.Context, playerID string) ([]Achievement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlocked := make([]Achievement, len(s.Unlocked[playerID]))
+	copy(unlocked, s.Unlocked[playerID])
+	return unlocked, nil
+}
+
+func (s *MemStore) WinStreak(ctx context.Context, playerID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.StatsByPlayer[playerID].WinStreak, nil
+}
+
+// AddFriendPair records an accepted friendship in both directions.
+func (s *MemStore) AddFriendPair(a, b string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if s.Friends[pair[0]] == nil {
+			s.Friends[pair[0]] = make(map[string]bool)
+		}
+		s.Friends[pair[0]][pair[1]] = true
+	}
+}
+
+func (s *MemStore) SaveChallengeScore(ctx context.Context, day, playerID, playerName string, score int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ChallengeBests[day] == nil {
+		s.ChallengeBests[day] = make(map[string]ChallengeRow)
+	}
+	if best, ok := s.ChallengeBests[day][playerID]; ok && best.Score >= score {
+		return nil
+	}
+	s.ChallengeBests[day][playerID] = ChallengeRow{
+		PlayerID: playerID, PlayerName: playerName, Score: score, UpdatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (s *MemStore) TopChallengeScores(ctx context.Context, day string, limit int) ([]ChallengeRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	board := make([]ChallengeRow, 0, len(s.ChallengeBests[day]))
+	for _, row := range s.ChallengeBests[day] {
+		board = append(board, row)
+	}
+	sort.SliceStable(board, func(i, j int) bool { return board[i].Score > board[j].Score })
+	if len(board) > limit {
+		board = board[:limit]
+	}
+	return board, nil
+}
+
+func (s *MemStore) TopScores(ctx context.Context, limit int) ([]ScoreRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	top := make([]ScoreRow, len(s.Scores))
+	copy(top, s.Scores)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Score > top[j].Score })
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top, nil
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migration is one versioned schema change with its up and down SQL.
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// loadMigrations parses the embedded migration files, sorted by version.
+// Files are named NNNN_name.up.sql / NNNN_name.down.sql.
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+	byVersion := map[int]*migration{}
+	for _, e := range entries {
+		name := e.Name()
+		base, direction, ok := splitMigrationName(name)
+		if !ok {
+			return nil, fmt.Errorf("unexpected migration filename %q", name)
+		}
+		versionStr, _, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q has no numeric version", name)
+		}
+		sql, err := migrationFS.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &migration{Version: version, Name: base}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(sql)
+		} else {
+			m.Down = string(sql)
+		}
+	}
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitMigrationName splits "0001_name.up.sql" into ("0001_name", "up").
+func splitMigrationName(name string) (base, direction string, ok bool) {
+	switch {
+	case strings.HasSuffix(name, ".up.sql"):
+		return strings.TrimSuffix(name, ".up.sql"), "up", true
+	case strings.HasSuffix(name, ".down.sql"):
+		return strings.TrimSuffix(name, ".down.sql"), "down", true
+	}
+	return "", "", false
+}
+
+// currentVersion reads the highest applied migration version, creating the
+// tracking table if needed.
+func currentVersion(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	_, err := pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	if err != nil {
+		return 0, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	var version int
+	err = pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Migrate applies every pending migration in order. It runs at server
+// startup and from the migrate command.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	version, err := currentVersion(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", m.Version, err)
+		}
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("record migration %d: %w", m.Version, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit migration %d: %w", m.Version, err)
+		}
+		slog.Info("applied migration", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
+
+// MigrateDown rolls back the given number of applied migrations, most recent
+// first.
+func MigrateDown(ctx context.Context, pool *pgxpool.Pool, steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	version, err := currentVersion(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if m.Version > version {
+			continue
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %d (%s) has no down file", m.Version, m.Name)
+		}
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("begin rollback %d: %w", m.Version, err)
+		}
+		if _, err := tx.Exec(ctx, m.Down); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("roll back migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("unrecord migration %d: %w", m.Version, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit rollback %d: %w", m.Version, err)
+		}
+		slog.Info("rolled back migration", "version", m.Version, "name", m.Name)
+		steps--
+	}
+	return nil
+}
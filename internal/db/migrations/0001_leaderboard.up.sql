@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS leaderboard (
+    id          BIGSERIAL PRIMARY KEY,
+    player_id   TEXT NOT NULL,
+    player_name TEXT NOT NULL,
+    score       INT  NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_score ON leaderboard (score DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_leaderboard_player_score;
+DROP INDEX IF EXISTS idx_leaderboard_created_score;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_leaderboard_player_score ON leaderboard (player_id, score DESC);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_created_score ON leaderboard (created_at, score DESC);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS player_ratings (
+    player_id   TEXT PRIMARY KEY,
+    player_name TEXT NOT NULL,
+    rating      INT  NOT NULL,
+    games       INT  NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS bans (
+    id         BIGSERIAL PRIMARY KEY,
+    target     TEXT NOT NULL,
+    kind       TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_bans_target ON bans (target);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS player_stats (
+    player_id   TEXT PRIMARY KEY,
+    player_name TEXT NOT NULL,
+    games       INT  NOT NULL DEFAULT 0,
+    wins        INT  NOT NULL DEFAULT 0,
+    losses      INT  NOT NULL DEFAULT 0,
+    draws       INT  NOT NULL DEFAULT 0,
+    best_score  INT  NOT NULL DEFAULT 0,
+    total_score BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS season_standings;
+DROP INDEX IF EXISTS leaderboard_season_idx;
+ALTER TABLE leaderboard DROP COLUMN IF EXISTS season_id;
+DROP TABLE IF EXISTS seasons;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS seasons (
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ended_at   TIMESTAMPTZ
+);
+
+INSERT INTO seasons (name) VALUES ('Season 1');
+
+ALTER TABLE leaderboard ADD COLUMN season_id INT REFERENCES seasons (id);
+CREATE INDEX IF NOT EXISTS leaderboard_season_idx ON leaderboard (season_id, score DESC);
+
+CREATE TABLE IF NOT EXISTS season_standings (
+    season_id   INT NOT NULL REFERENCES seasons (id),
+    rank        INT NOT NULL,
+    player_id   TEXT NOT NULL,
+    player_name TEXT NOT NULL,
+    score       INT NOT NULL,
+    PRIMARY KEY (season_id, rank)
+);
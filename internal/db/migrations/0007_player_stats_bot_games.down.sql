@@ -0,0 +1 @@
+ALTER TABLE player_stats DROP COLUMN IF EXISTS bot_games;
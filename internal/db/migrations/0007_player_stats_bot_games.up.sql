@@ -0,0 +1 @@
+ALTER TABLE player_stats ADD COLUMN bot_games INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS friends;
+DROP TABLE IF EXISTS friend_requests;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS friend_requests (
+    from_id    TEXT NOT NULL,
+    from_name  TEXT NOT NULL DEFAULT '',
+    to_id      TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (from_id, to_id)
+);
+CREATE INDEX IF NOT EXISTS friend_requests_to_idx ON friend_requests (to_id);
+
+CREATE TABLE IF NOT EXISTS friends (
+    player_id  TEXT NOT NULL,
+    friend_id  TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (player_id, friend_id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS cheat_flags (
+    id          BIGSERIAL PRIMARY KEY,
+    player_id   TEXT NOT NULL,
+    player_name TEXT NOT NULL DEFAULT '',
+    room_id     TEXT NOT NULL,
+    score       INTEGER NOT NULL,
+    reason      TEXT NOT NULL,
+    detail      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cheat_flags_player ON cheat_flags (player_id, created_at DESC);
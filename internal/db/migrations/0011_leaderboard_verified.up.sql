@@ -0,0 +1,5 @@
+ALTER TABLE leaderboard ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Backfill: guests get ephemeral guest_* IDs, everything else is a
+-- registered identity.
+UPDATE leaderboard SET verified = TRUE WHERE player_id NOT LIKE 'guest\_%';
@@ -0,0 +1,17 @@
+-- score_events is the audit trail of accepted score updates: one row per
+-- UPDATE_SCORE the server let through, as ground truth for cheating
+-- investigations and replay reconstruction. Rows are pruned after the
+-- configured retention window.
+CREATE TABLE IF NOT EXISTS score_events (
+    id BIGSERIAL PRIMARY KEY,
+    player_id TEXT NOT NULL,
+    room_id TEXT NOT NULL,
+    score INTEGER NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Investigations read one room's updates in order.
+CREATE INDEX IF NOT EXISTS idx_score_events_room ON score_events (room_id, recorded_at);
+
+-- The pruner deletes by age.
+CREATE INDEX IF NOT EXISTS idx_score_events_recorded_at ON score_events (recorded_at);
@@ -0,0 +1,22 @@
+-- reports is the player-report review queue: one row per REPORT_PLAYER a
+-- client filed against an opponent. The room id ties a report to the
+-- score_events audit trail of that match for review. A player can report a
+-- given opponent once per match.
+CREATE TABLE IF NOT EXISTS reports (
+    id            BIGSERIAL PRIMARY KEY,
+    reporter_id   TEXT NOT NULL,
+    reporter_name TEXT NOT NULL DEFAULT '',
+    reported_id   TEXT NOT NULL,
+    reported_name TEXT NOT NULL DEFAULT '',
+    room_id       TEXT NOT NULL,
+    reason        TEXT NOT NULL,
+    status        TEXT NOT NULL DEFAULT 'open',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_once_per_match
+    ON reports (reporter_id, reported_id, room_id);
+
+-- The review queue lists open reports, oldest first.
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports (status, created_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_leaderboard_player_name_trgm;
@@ -0,0 +1,6 @@
+-- Trigram index behind /api/leaderboard?search=: ILIKE '%name%' can't use a
+-- btree, so without this every search is a sequential scan.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_leaderboard_player_name_trgm
+    ON leaderboard USING gin (player_name gin_trgm_ops);
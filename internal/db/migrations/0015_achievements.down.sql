@@ -0,0 +1,3 @@
+ALTER TABLE player_stats DROP COLUMN IF EXISTS win_streak;
+
+DROP TABLE IF EXISTS achievements;
@@ -0,0 +1,12 @@
+-- achievements records milestone unlocks, one row per player and milestone.
+-- Each achievement maps to a cosmetic dino skin rendered by clients.
+CREATE TABLE IF NOT EXISTS achievements (
+    player_id   TEXT NOT NULL,
+    achievement TEXT NOT NULL,
+    unlocked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (player_id, achievement)
+);
+
+-- win_streak is the current run of consecutive wins, maintained by
+-- RecordMatchResult and consulted for the streak achievement.
+ALTER TABLE player_stats ADD COLUMN IF NOT EXISTS win_streak INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- input_events logs every jump/duck the server accepted, stamped with the
+-- client's own clock. Together with the room's seed-derived obstacle
+-- schedule an offline verifier can re-simulate a run and decide whether the
+-- reported score was reachable. Rows are pruned on the same retention window as
+-- score_events.
+CREATE TABLE IF NOT EXISTS input_events (
+    id BIGSERIAL PRIMARY KEY,
+    room_id TEXT NOT NULL,
+    player_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    client_ts BIGINT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The verifier replays one player's inputs from one room in order.
+CREATE INDEX IF NOT EXISTS idx_input_events_room_player ON input_events (room_id, player_id, recorded_at);
+
+-- The pruner deletes by age.
+CREATE INDEX IF NOT EXISTS idx_input_events_recorded_at ON input_events (recorded_at);
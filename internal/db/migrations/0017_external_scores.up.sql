@@ -0,0 +1,16 @@
+-- external_scores is the separate board for score submissions arriving over
+-- REST from non-WebSocket clients, e.g. an offline single-player mode. It is
+-- kept apart from the live leaderboard so offline runs never mix into ranked
+-- multiplayer standings. integration names the API key that submitted the
+-- row.
+CREATE TABLE IF NOT EXISTS external_scores (
+    id BIGSERIAL PRIMARY KEY,
+    integration TEXT NOT NULL,
+    player_id TEXT NOT NULL,
+    player_name TEXT NOT NULL,
+    score INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_scores_score ON external_scores (score DESC);
+CREATE INDEX IF NOT EXISTS idx_external_scores_integration ON external_scores (integration, score DESC);
@@ -0,0 +1,4 @@
+-- country is the ISO 3166-1 alpha-2 code of where the run was played, taken
+-- from the proxy's GeoIP header at connect time. Empty when the deployment
+-- has no trusted proxy or the lookup failed; existing rows stay empty.
+ALTER TABLE leaderboard ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
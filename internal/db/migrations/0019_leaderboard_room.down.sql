@@ -0,0 +1 @@
+ALTER TABLE leaderboard DROP COLUMN IF EXISTS room_id;
@@ -0,0 +1,5 @@
+-- room_id links a leaderboard row back to the match it was scored in, so a
+-- run can be traced to its input replay, verification archive, and
+-- opponents. Empty for pre-migration rows and for scores saved outside a
+-- room (e.g. external integrations).
+ALTER TABLE leaderboard ADD COLUMN IF NOT EXISTS room_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- challenge_scores holds daily-challenge results: one row per player and UTC
+-- day ("2006-01-02"), upserted to keep only the player's best attempt. The
+-- day's shared seed is derived from the day key, so rows don't store it.
+CREATE TABLE IF NOT EXISTS challenge_scores (
+    challenge_day TEXT NOT NULL,
+    player_id     TEXT NOT NULL,
+    player_name   TEXT NOT NULL,
+    score         INTEGER NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (challenge_day, player_id)
+);
+
+-- Serves the per-day board in score order.
+CREATE INDEX IF NOT EXISTS challenge_scores_board_idx
+    ON challenge_scores (challenge_day, score DESC);
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Verified reports whether a player ID belongs to a registered identity.
+// Guests connect with ephemeral guest_* IDs and are never verified.
+func Verified(playerID string) bool {
+	return !strings.HasPrefix(playerID, "guest_")
+}
+
+// NameTaken reports whether a display name is already used by a registered
+// player other than playerID. Names are compared case-insensitively across
+// the tables a registered identity shows up in.
+func NameTaken(ctx context.Context, pool *pgxpool.Pool, name, playerID string) (bool, error) {
+	var taken bool
+	err := pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM player_stats
+			WHERE lower(player_name) = lower($1) AND player_id <> $2
+			UNION ALL
+			SELECT 1 FROM player_ratings
+			WHERE lower(player_name) = lower($1) AND player_id <> $2
+		)`, name, playerID).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("name taken: %w", err)
+	}
+	return taken, nil
+}
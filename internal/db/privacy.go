@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PlayerExport is the full personal-data dump served by the GDPR export
+// endpoint: every stored row that references the player.
+type PlayerExport struct {
+	PlayerID     string          `json:"playerId"`
+	Profile      Stats           `json:"profile"`
+	Rating       Rating          `json:"rating"`
+	Scores       []ScoreRow      `json:"scores"`
+	Friends      []Friend        `json:"friends"`
+	Requests     []FriendRequest `json:"friendRequests"`
+	Achievements []Achievement   `json:"achievements"`
+	// ScoreEvents and InputEvents are the anti-cheat audit and replay
+	// trails; both are pruned on their retention window, so they cover
+	// recent matches only.
+	ScoreEvents []ScoreEvent `json:"scoreEvents"`
+	InputEvents []InputEvent `json:"inputEvents"`
+	// CheatFlags and Reports cover moderation: flags raised against the
+	// player, and reports the player filed or was the subject of.
+	CheatFlags []CheatFlag `json:"cheatFlags"`
+	Reports    []Report    `json:"reports"`
+	// ExternalScores are REST-submitted runs from non-WebSocket clients.
+	ExternalScores []ExternalScore `json:"externalScores"`
+	// ChallengeScores are the player's daily-challenge bests, one per day.
+	ChallengeScores []ChallengeScoreExport `json:"challengeScores"`
+}
+
+// ChallengeScoreExport is one daily-challenge row in an export, carrying the
+// day that ChallengeRow leaves implicit on the per-day board.
+type ChallengeScoreExport struct {
+	Day string `json:"day"`
+	ChallengeRow
+}
+
+// ExportPlayer collects everything stored about a player.
+func ExportPlayer(ctx context.Context, pool *pgxpool.Pool, playerID string) (PlayerExport, error) {
+	export := PlayerExport{PlayerID: playerID}
+	var err error
+	if export.Profile, err = GetStats(ctx, pool, playerID); err != nil {
+		return export, fmt.Errorf("export player: %w", err)
+	}
+	if export.Rating, err = GetRating(ctx, pool, playerID); err != nil {
+		return export, fmt.Errorf("export player: %w", err)
+	}
+	if export.Friends, err = ListFriends(ctx, pool, playerID); err != nil {
+		return export, fmt.Errorf("export player: %w", err)
+	}
+	if export.Requests, err = ListFriendRequests(ctx, pool, playerID); err != nil {
+		return export, fmt.Errorf("export player: %w", err)
+	}
+	if export.Achievements, err = ListAchievements(ctx, pool, playerID); err != nil {
+		return export, fmt.Errorf("export player: %w", err)
+	}
+	if err := exportScores(ctx, pool, playerID, &export); err != nil {
+		return export, err
+	}
+	if err := exportAuditTrails(ctx, pool, playerID, &export); err != nil {
+		return export, err
+	}
+	if err := exportModeration(ctx, pool, playerID, &export); err != nil {
+		return export, err
+	}
+	if err := exportSideBoards(ctx, pool, playerID, &export); err != nil {
+		return export, err
+	}
+	return export, nil
+}
+
+// exportScores collects the player's leaderboard rows.
+func exportScores(ctx context.Context, pool *pgxpool.Pool, playerID string, export *PlayerExport) error {
+	rows, err := pool.Query(ctx, `
+		SELECT player_id, player_name, score, verified, country, room_id, created_at
+		FROM leaderboard WHERE player_id = $1 ORDER BY created_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: %w", err)
+	}
+	defer rows.Close()
+	export.Scores = []ScoreRow{}
+	for rows.Next() {
+		var row ScoreRow
+		if err := rows.Scan(&row.PlayerID, &row.PlayerName, &row.Score, &row.Verified, &row.Country, &row.RoomID, &row.CreatedAt); err != nil {
+			return fmt.Errorf("export player: %w", err)
+		}
+		export.Scores = append(export.Scores, row)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: %w", err)
+	}
+	return nil
+}
+
+// exportAuditTrails collects the player's score audit and input replay rows.
+func exportAuditTrails(ctx context.Context, pool *pgxpool.Pool, playerID string, export *PlayerExport) error {
+	rows, err := pool.Query(ctx, `
+		SELECT player_id, room_id, score, recorded_at
+		FROM score_events WHERE player_id = $1 ORDER BY recorded_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: score_events: %w", err)
+	}
+	defer rows.Close()
+	export.ScoreEvents = []ScoreEvent{}
+	for rows.Next() {
+		var e ScoreEvent
+		if err := rows.Scan(&e.PlayerID, &e.RoomID, &e.Score, &e.RecordedAt); err != nil {
+			return fmt.Errorf("export player: score_events: %w", err)
+		}
+		export.ScoreEvents = append(export.ScoreEvents, e)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: score_events: %w", err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT room_id, player_id, action, client_ts, recorded_at
+		FROM input_events WHERE player_id = $1 ORDER BY recorded_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: input_events: %w", err)
+	}
+	defer rows.Close()
+	export.InputEvents = []InputEvent{}
+	for rows.Next() {
+		var e InputEvent
+		if err := rows.Scan(&e.RoomID, &e.PlayerID, &e.Action, &e.ClientTS, &e.RecordedAt); err != nil {
+			return fmt.Errorf("export player: input_events: %w", err)
+		}
+		export.InputEvents = append(export.InputEvents, e)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: input_events: %w", err)
+	}
+	return nil
+}
+
+// exportModeration collects cheat flags against the player and the reports
+// the player filed or was the subject of.
+func exportModeration(ctx context.Context, pool *pgxpool.Pool, playerID string, export *PlayerExport) error {
+	rows, err := pool.Query(ctx, `
+		SELECT id, player_id, player_name, room_id, score, reason, detail, created_at
+		FROM cheat_flags WHERE player_id = $1 ORDER BY created_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: cheat_flags: %w", err)
+	}
+	defer rows.Close()
+	export.CheatFlags = []CheatFlag{}
+	for rows.Next() {
+		var f CheatFlag
+		if err := rows.Scan(&f.ID, &f.PlayerID, &f.PlayerName, &f.RoomID, &f.Score, &f.Reason, &f.Detail, &f.CreatedAt); err != nil {
+			return fmt.Errorf("export player: cheat_flags: %w", err)
+		}
+		export.CheatFlags = append(export.CheatFlags, f)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: cheat_flags: %w", err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT id, reporter_id, reporter_name, reported_id, reported_name,
+		       room_id, reason, status, created_at, resolved_at
+		FROM reports WHERE reporter_id = $1 OR reported_id = $1
+		ORDER BY created_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: reports: %w", err)
+	}
+	defer rows.Close()
+	export.Reports = []Report{}
+	for rows.Next() {
+		var rep Report
+		if err := rows.Scan(&rep.ID, &rep.ReporterID, &rep.ReporterName, &rep.ReportedID, &rep.ReportedName,
+			&rep.RoomID, &rep.Reason, &rep.Status, &rep.CreatedAt, &rep.ResolvedAt); err != nil {
+			return fmt.Errorf("export player: reports: %w", err)
+		}
+		export.Reports = append(export.Reports, rep)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: reports: %w", err)
+	}
+	return nil
+}
+
+// exportSideBoards collects the player's external and daily-challenge rows.
+func exportSideBoards(ctx context.Context, pool *pgxpool.Pool, playerID string, export *PlayerExport) error {
+	rows, err := pool.Query(ctx, `
+		SELECT player_id, player_name, score, integration, created_at
+		FROM external_scores WHERE player_id = $1 ORDER BY created_at ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: external_scores: %w", err)
+	}
+	defer rows.Close()
+	export.ExternalScores = []ExternalScore{}
+	for rows.Next() {
+		var s ExternalScore
+		if err := rows.Scan(&s.PlayerID, &s.PlayerName, &s.Score, &s.Integration, &s.CreatedAt); err != nil {
+			return fmt.Errorf("export player: external_scores: %w", err)
+		}
+		export.ExternalScores = append(export.ExternalScores, s)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: external_scores: %w", err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT challenge_day, player_id, player_name, score, updated_at
+		FROM challenge_scores WHERE player_id = $1 ORDER BY challenge_day ASC`, playerID)
+	if err != nil {
+		return fmt.Errorf("export player: challenge_scores: %w", err)
+	}
+	defer rows.Close()
+	export.ChallengeScores = []ChallengeScoreExport{}
+	for rows.Next() {
+		var row ChallengeScoreExport
+		if err := rows.Scan(&row.Day, &row.PlayerID, &row.PlayerName, &row.Score, &row.UpdatedAt); err != nil {
+			return fmt.Errorf("export player: challenge_scores: %w", err)
+		}
+		export.ChallengeScores = append(export.ChallengeScores, row)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export player: challenge_scores: %w", err)
+	}
+	return nil
+}
+
+// ErasureReport counts, per table, the rows removed or anonymized by
+// ErasePlayer.
+type ErasureReport map[string]int64
+
+// ErasePlayer removes every row referencing a player across the live
+// tables and anonymizes archived season standings, whose ranks must stay
+// intact. The erasure itself is recorded in the audit log inside the same
+// transaction.
+func ErasePlayer(ctx context.Context, pool *pgxpool.Pool, playerID string) (ErasureReport, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erase player: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	report := ErasureReport{}
+	deletes := []struct {
+		table, query string
+	}{
+		{"leaderboard", `DELETE FROM leaderboard WHERE player_id = $1`},
+		{"player_stats", `DELETE FROM player_stats WHERE player_id = $1`},
+		{"player_ratings", `DELETE FROM player_ratings WHERE player_id = $1`},
+		{"friends", `DELETE FROM friends WHERE player_id = $1 OR friend_id = $1`},
+		{"friend_requests", `DELETE FROM friend_requests WHERE from_id = $1 OR to_id = $1`},
+		{"achievements", `DELETE FROM achievements WHERE player_id = $1`},
+		{"score_events", `DELETE FROM score_events WHERE player_id = $1`},
+		{"input_events", `DELETE FROM input_events WHERE player_id = $1`},
+		{"cheat_flags", `DELETE FROM cheat_flags WHERE player_id = $1`},
+		{"reports", `DELETE FROM reports WHERE reporter_id = $1 OR reported_id = $1`},
+		{"external_scores", `DELETE FROM external_scores WHERE player_id = $1`},
+		{"challenge_scores", `DELETE FROM challenge_scores WHERE player_id = $1`},
+	}
+	for _, d := range deletes {
+		tag, err := tx.Exec(ctx, d.query, playerID)
+		if err != nil {
+			return nil, fmt.Errorf("erase player: %s: %w", d.table, err)
+		}
+		report[d.table] = tag.RowsAffected()
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE season_standings SET player_id = 'deleted', player_name = 'Deleted Player'
+		WHERE player_id = $1`, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("erase player: season_standings: %w", err)
+	}
+	report["season_standings"] = tag.RowsAffected()
+
+	detail, _ := json.Marshal(report)
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO audit_log (action, target, detail) VALUES ('gdpr_delete', $1, $2)`,
+		playerID, string(detail)); err != nil {
+		return nil, fmt.Errorf("erase player: audit: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erase player: %w", err)
+	}
+	return report, nil
+}
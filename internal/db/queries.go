@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// This package is the only place SQL lives: HTTP and WS handlers describe
+// what they want through filter values and typed rows, never query text.
+// pgx prepares each distinct statement on first use and reuses it from its
+// per-connection cache, so everything below runs as a named prepared
+// statement without explicit Prepare calls — which also keeps startup order
+// simple, since nothing has to exist before the migrations run.
+
+// QueryTimeout bounds any single read issued through the query layer, so a
+// slow or wedged Postgres can't pin request handlers indefinitely. Writes
+// with their own retry machinery (ScoreWriter, AuditWriter) set their own.
+const QueryTimeout = 5 * time.Second
+
+// ErrNotFound is the shared sentinel for lookups of rows that don't exist.
+// Specific sentinels such as ErrReportNotFound wrap it, so callers can test
+// errors.Is against either the specific or the general case.
+var ErrNotFound = errors.New("not found")
+
+// withTimeout caps ctx at QueryTimeout unless it already carries a sooner
+// deadline.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d, ok := ctx.Deadline(); ok && time.Until(d) <= QueryTimeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, QueryTimeout)
+}
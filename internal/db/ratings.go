@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Rating is one row of player_ratings.
+type Rating struct {
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Rating     int    `json:"rating"`
+	Games      int    `json:"games"`
+}
+
+// GetRating returns a player's rating, or the default for unknown players.
+func GetRating(ctx context.Context, pool *pgxpool.Pool, playerID string) (Rating, error) {
+	var r Rating
+	err := pool.QueryRow(ctx,
+		`SELECT player_id, player_name, rating, games FROM player_ratings WHERE player_id = $1`,
+		playerID).Scan(&r.PlayerID, &r.PlayerName, &r.Rating, &r.Games)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Rating{PlayerID: playerID, Rating: game.DefaultRating}, nil
+	}
+	if err != nil {
+		return Rating{}, fmt.Errorf("get rating: %w", err)
+	}
+	return r, nil
+}
+
+// SaveRating upserts a player's rating after a match and bumps their game
+// count.
+func SaveRating(ctx context.Context, pool *pgxpool.Pool, playerID, playerName string, rating int) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO player_ratings (player_id, player_name, rating, games)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (player_id) DO UPDATE
+		SET player_name = EXCLUDED.player_name,
+		    rating = EXCLUDED.rating,
+		    games = player_ratings.games + 1,
+		    updated_at = now()`,
+		playerID, playerName, rating)
+	if err != nil {
+		return fmt.Errorf("save rating: %w", err)
+	}
+	return nil
+}
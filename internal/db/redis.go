@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis connection resilience. Boot retries cover a Redis that comes up a
+// moment after the server (common in compose and rolling deploys); command
+// retries let go-redis absorb brief blips and failovers at runtime, so a
+// transient outage degrades the Redis-backed features instead of erroring
+// every call on the first try.
+const (
+	redisConnectAttempts = 5
+	redisConnectBackoff  = 500 * time.Millisecond
+	redisCommandRetries  = 3
+)
+
+// ConnectRedis opens a Redis client against the given connection URL and
+// verifies it with a ping, retrying with backoff while Redis comes up. Redis
+// is optional; callers skip this entirely when no URL is configured.
+//
+// Three deployments are supported, selected by scheme:
+//
+//	redis://host:6379/0                                  single node
+//	redis+sentinel://host1:26379,host2:26379/master/0    Sentinel failover
+//	redis+cluster://host1:6379?addr=host2:6379           Cluster
+func ConnectRedis(ctx context.Context, rawURL string) (redis.UniversalClient, error) {
+	client, err := newRedisClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	backoff := redisConnectBackoff
+	for attempt := 1; ; attempt++ {
+		if err = client.Ping(ctx).Err(); err == nil {
+			return client, nil
+		}
+		if attempt == redisConnectAttempts {
+			break
+		}
+		slog.Warn("redis not reachable, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			client.Close()
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+	client.Close()
+	return nil, fmt.Errorf("ping redis: %w", err)
+}
+
+// newRedisClient builds the client matching the URL's scheme.
+func newRedisClient(rawURL string) (redis.UniversalClient, error) {
+	switch {
+	case strings.HasPrefix(rawURL, "redis+sentinel://"):
+		opts, err := parseSentinelURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		opts.MaxRetries = redisCommandRetries
+		return redis.NewFailoverClient(opts), nil
+	case strings.HasPrefix(rawURL, "redis+cluster://"):
+		opts, err := redis.ParseClusterURL("redis://" + strings.TrimPrefix(rawURL, "redis+cluster://"))
+		if err != nil {
+			return nil, fmt.Errorf("parse redis cluster url: %w", err)
+		}
+		opts.MaxRetries = redisCommandRetries
+		return redis.NewClusterClient(opts), nil
+	default:
+		opts, err := redis.ParseURL(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse redis url: %w", err)
+		}
+		opts.MaxRetries = redisCommandRetries
+		return redis.NewClient(opts), nil
+	}
+}
+
+// parseSentinelURL parses
+// redis+sentinel://[:password@]host1:port1,host2:port2/master-name[/db].
+// The password, when set, is used for both the sentinels and the master.
+func parseSentinelURL(rawURL string) (*redis.FailoverOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis sentinel url: %w", err)
+	}
+	opts := &redis.FailoverOptions{
+		SentinelAddrs: strings.Split(u.Host, ","),
+	}
+	if u.User != nil {
+		if pw, ok := u.User.Password(); ok {
+			opts.Password = pw
+			opts.SentinelPassword = pw
+		}
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return nil, fmt.Errorf("parse redis sentinel url: master name missing, want redis+sentinel://host:port,.../master-name")
+	}
+	opts.MasterName = parts[0]
+	if len(parts) > 1 {
+		db, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse redis sentinel url: bad db %q", parts[1])
+		}
+		opts.DB = db
+	}
+	return opts, nil
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+)
+
+// Repository interfaces decouple the game server from Postgres so handlers
+// can be unit-tested against the in-memory fake in MemStore.
+
+// ScoreRepository persists finished runs and per-player match aggregates.
+type ScoreRepository interface {
+	// SaveScore persists one finished run. country is the connection's
+	// GeoIP country code and roomID the match the run was scored in; both
+	// may be empty when unknown.
+	SaveScore(ctx context.Context, playerID, playerName string, score int, country, roomID string) error
+	RecordMatchResult(ctx context.Context, playerID, playerName string, score int, outcome string, vsBot bool) error
+	// RecordCheatFlag stores one anti-cheat rejection for admin review.
+	RecordCheatFlag(ctx context.Context, playerID, playerName, roomID string, score int, reason, detail string) error
+	// RecordScoreEvent appends one accepted score update to the audit trail.
+	RecordScoreEvent(ctx context.Context, playerID, roomID string, score int) error
+	// RecordInput appends one accepted jump/duck action to the replay log.
+	RecordInput(ctx context.Context, roomID, playerID, action string, clientTS int64) error
+	// RecordReport stores one player-filed cheating report for admin review.
+	RecordReport(ctx context.Context, reporterID, reporterName, reportedID, reportedName, roomID, reason string) error
+	// BestScore returns the player's stored best score, zero for players
+	// without a stats row yet.
+	BestScore(ctx context.Context, playerID string) (int, error)
+}
+
+// PlayerRepository serves per-player state consulted during matchmaking.
+type PlayerRepository interface {
+	GetRating(ctx context.Context, playerID string) (Rating, error)
+	SaveRating(ctx context.Context, playerID, playerName string, rating int) error
+	IsBanned(ctx context.Context, target string) (bool, error)
+	// NameTaken reports whether another registered player already uses name.
+	NameTaken(ctx context.Context, name, playerID string) (bool, error)
+}
+
+// LeaderboardRepository reads back saved scores for leaderboard consumers.
+type LeaderboardRepository interface {
+	// TopScores returns the highest runs, best first.
+	TopScores(ctx context.Context, limit int) ([]ScoreRow, error)
+	// ScoreRank returns the global board position a run of the given score
+	// lands at.
+	ScoreRank(ctx context.Context, score int) (int, error)
+}
+
+// ChallengeRepository stores daily-challenge results: one best score per
+// player per UTC day.
+type ChallengeRepository interface {
+	// SaveChallengeScore records one attempt; scores below the player's
+	// stored best for the day are no-ops.
+	SaveChallengeScore(ctx context.Context, day, playerID, playerName string, score int) error
+	// TopChallengeScores returns one day's board, best first.
+	TopChallengeScores(ctx context.Context, day string, limit int) ([]ChallengeRow, error)
+}
+
+// FriendRepository answers the friendship checks behind direct challenges.
+type FriendRepository interface {
+	AreFriends(ctx context.Context, playerID, friendID string) (bool, error)
+}
+
+// AchievementRepository tracks the milestone unlocks behind cosmetic skins.
+type AchievementRepository interface {
+	// UnlockAchievement records a milestone; repeat unlocks are no-ops.
+	UnlockAchievement(ctx context.Context, playerID, name string) error
+	ListAchievements(ctx context.Context, playerID string) ([]Achievement, error)
+	// WinStreak returns the player's current run of consecutive wins.
+	WinStreak(ctx context.Context, playerID string) (int, error)
+}
+
+// Store bundles every repository the game server needs.
+type Store interface {
+	ScoreRepository
+	PlayerRepository
+	LeaderboardRepository
+	ChallengeRepository
+	FriendRepository
+	AchievementRepository
+}
+
+// ScoreRow is one persisted run as returned by LeaderboardRepository.
+type ScoreRow struct {
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`
+	// Verified distinguishes registered players from guests on the board.
+	Verified bool `json:"verified"`
+	// Country is where the run was played, as an ISO 3166-1 alpha-2 code
+	// from the proxy's GeoIP header; empty when unknown.
+	Country string `json:"country,omitempty"`
+	// RoomID is the match the run was scored in, linking the row to its
+	// replay and verification records; empty for roomless scores.
+	RoomID    string    `json:"roomId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PgStore is the production Store, backed by the pgx pool. Score saves go
+// through the async ScoreWriter so the hub never waits on Postgres.
+type PgStore struct {
+	pool   *pgxpool.Pool
+	scores *ScoreWriter
+	audit  *AuditWriter
+	inputs *InputWriter
+}
+
+// NewPgStore wraps a pool in the repository interfaces and starts the
+// score persistence, audit and input log workers.
+func NewPgStore(pool *pgxpool.Pool) *PgStore {
+	return &PgStore{pool: pool, scores: NewScoreWriter(pool), audit: NewAuditWriter(pool), inputs: NewInputWriter(pool)}
+}
+
+// SetDailyScoreCap bounds leaderboard inserts per player per UTC day; runs
+// beating the player's best are always kept. Zero disables the cap. Call it
+// at startup, before any scores flow.
+func (s *PgStore) SetDailyScoreCap(n int) {
+	s.scores.dailyCap = n
+}
+
+// Close drains the score, audit and input log queues; call it on shutdown.
+func (s *PgStore) Close() {
+	s.scores.Close()
+	s.audit.Close()
+	s.inputs.Close()
+}
+
+func (s *PgStore) SaveScore(ctx context.Context, playerID, playerName string, score int, country, roomID string) error {
+	return s.scores.Enqueue(playerID, playerName, score, country, roomID)
+}
+
+func (s *PgStore) RecordCheatFlag(ctx context.Context, playerID, playerName, roomID string, score int, reason, detail string) error {
+	return RecordCheatFlag(ctx, s.pool, playerID, playerName, roomID, score, reason, detail)
+}
+
+func (s *PgStore) RecordScoreEvent(ctx context.Context, playerID, roomID string, score int) error {
+	return s.audit.Enqueue(playerID, roomID, score)
+}
+
+func (s *PgStore) RecordInput(ctx context.Context, roomID, playerID, action string, clientTS int64) error {
+	return s.inputs.Enqueue(roomID, playerID, action, clientTS)
+}
+
+func (s *PgStore) RecordReport(ctx context.Context, reporterID, reporterName, reportedID, reportedName, roomID, reason string) error {
+	return InsertReport(ctx, s.pool, reporterID, reporterName, reportedID, reportedName, roomID, reason)
+}
+
+func (s *PgStore) RecordMatchResult(ctx context.Context, playerID, playerName string, score int, outcome string, vsBot bool) error {
+	return RecordMatchResult(ctx, s.pool, playerID, playerName, score, outcome, vsBot)
+}
+
+func (s *PgStore) BestScore(ctx context.Context, playerID string) (int, error) {
+	return GetBestScore(ctx, s.pool, playerID)
+}
+
+func (s *PgStore) ScoreRank(ctx context.Context, score int) (int, error) {
+	return ScoreRank(ctx, s.pool, score)
+}
+
+func (s *PgStore) GetRating(ctx context.Context, playerID string) (Rating, error) {
+	return GetRating(ctx, s.pool, playerID)
+}
+
+func (s *PgStore) SaveRating(ctx context.Context, playerID, playerName string, rating int) error {
+	return SaveRating(ctx, s.pool, playerID, playerName, rating)
+}
+
+func (s *PgStore) IsBanned(ctx context.Context, target string) (bool, error) {
+	return IsBanned(ctx, s.pool, target)
+}
+
+func (s *PgStore) NameTaken(ctx context.Context, name, playerID string) (bool, error) {
+	return NameTaken(ctx, s.pool, name, playerID)
+}
+
+func (s *PgStore) SaveChallengeScore(ctx context.Context, day, playerID, playerName string, score int) error {
+	return SaveChallengeScore(ctx, s.pool, day, playerID, playerName, score)
+}
+
+func (s *PgStore) TopChallengeScores(ctx context.Context, day string, limit int) ([]ChallengeRow, error) {
+	return TopChallengeScores(ctx, s.pool, day, limit)
+}
+
+func (s *PgStore) AreFriends(ctx context.Context, playerID, friendID string) (bool, error) {
+	return AreFriends(ctx, s.pool, playerID, friendID)
+}
+
+func (s *PgStore) UnlockAchievement(ctx context.Context, playerID, name string) error {
+	return UnlockAchievement(ctx, s.pool, playerID, name)
+}
+
+func (s *PgStore) ListAchievements(ctx context.Context, playerID string) ([]Achievement, error) {
+	return ListAchievements(ctx, s.pool, playerID)
+}
+
+func (s *PgStore) WinStreak(ctx context.Context, playerID string) (int, error) {
+	return WinStreak(ctx, s.pool, playerID)
+}
+
+func (s *PgStore) TopScores(ctx context.Context, limit int) ([]ScoreRow, error) {
+	ctx, span := tracing.Start(ctx, "db.top_scores")
+	defer span.End()
+	rows, err := s.pool.Query(ctx, `
+		SELECT player_id, player_name, score, verified, country, room_id, created_at
+		FROM leaderboard
+		ORDER BY score DESC, created_at ASC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	scores := []ScoreRow{}
+	for rows.Next() {
+		var row ScoreRow
+		if err := rows.Scan(&row.PlayerID, &row.PlayerName, &row.Score, &row.Verified, &row.Country, &row.RoomID, &row.CreatedAt); err != nil {
+			return nil, err
+		}
+		scores = append(scores, row)
+	}
+	return scores, rows.Err()
+}
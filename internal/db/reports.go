@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Report review statuses.
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+// ErrReportNotFound is returned when a report ID doesn't exist. It wraps
+// the query layer's ErrNotFound sentinel.
+var ErrReportNotFound = fmt.Errorf("db: report: %w", ErrNotFound)
+
+// Report is one player-filed cheating report awaiting admin review.
+type Report struct {
+	ID           int64      `json:"id"`
+	ReporterID   string     `json:"reporterId"`
+	ReporterName string     `json:"reporterName"`
+	ReportedID   string     `json:"reportedId"`
+	ReportedName string     `json:"reportedName"`
+	RoomID       string     `json:"roomId"`
+	Reason       string     `json:"reason"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ResolvedAt   *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// InsertReport stores one player report. A duplicate report — the same
+// reporter against the same opponent in the same match — is silently ignored.
+func InsertReport(ctx context.Context, pool *pgxpool.Pool, reporterID, reporterName, reportedID, reportedName, roomID, reason string) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO reports (reporter_id, reporter_name, reported_id, reported_name, room_id, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (reporter_id, reported_id, room_id) DO NOTHING`,
+		reporterID, reporterName, reportedID, reportedName, roomID, reason)
+	if err != nil {
+		return fmt.Errorf("insert report: %w", err)
+	}
+	return nil
+}
+
+// ListReports returns reports oldest first, optionally filtered by status,
+// so the review queue surfaces what has waited longest.
+func ListReports(ctx context.Context, pool *pgxpool.Pool, status string, limit int) ([]Report, error) {
+	query := `
+		SELECT id, reporter_id, reporter_name, reported_id, reported_name,
+		       room_id, reason, status, created_at, resolved_at
+		FROM reports`
+	args := []interface{}{}
+	if status != "" {
+		query += ` WHERE status = $1`
+		args = append(args, status)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at ASC LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+	defer rows.Close()
+	reports := []Report{}
+	for rows.Next() {
+		var rep Report
+		if err := rows.Scan(&rep.ID, &rep.ReporterID, &rep.ReporterName, &rep.ReportedID, &rep.ReportedName,
+			&rep.RoomID, &rep.Reason, &rep.Status, &rep.CreatedAt, &rep.ResolvedAt); err != nil {
+			return nil, fmt.Errorf("list reports: %w", err)
+		}
+		reports = append(reports, rep)
+	}
+	return reports, rows.Err()
+}
+
+// GetReport fetches one report by ID.
+func GetReport(ctx context.Context, pool *pgxpool.Pool, id int64) (Report, error) {
+	var rep Report
+	err := pool.QueryRow(ctx, `
+		SELECT id, reporter_id, reporter_name, reported_id, reported_name,
+		       room_id, reason, status, created_at, resolved_at
+		FROM reports WHERE id = $1`, id).
+		Scan(&rep.ID, &rep.ReporterID, &rep.ReporterName, &rep.ReportedID, &rep.ReportedName,
+			&rep.RoomID, &rep.Reason, &rep.Status, &rep.CreatedAt, &rep.ResolvedAt)
+	if err != nil {
+		return Report{}, fmt.Errorf("get report: %w", err)
+	}
+	return rep, nil
+}
+
+// ResolveReport marks a report resolved. It returns ErrReportNotFound when
+// the ID doesn't exist.
+func ResolveReport(ctx context.Context, pool *pgxpool.Pool, id int64) error {
+	tag, err := pool.Exec(ctx, `
+		UPDATE reports SET status = $1, resolved_at = now()
+		WHERE id = $2`, ReportStatusResolved, id)
+	if err != nil {
+		return fmt.Errorf("resolve report: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReportNotFound
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Audit writer tuning. UPDATE_SCORE arrives a couple of times per second per
+// player, so the queue and batches are sized above the leaderboard writer's.
+const (
+	auditQueueSize     = 4096
+	auditBatchSize     = 200
+	auditFlushInterval = time.Second
+	// auditPruneInterval is how often expired audit rows are deleted.
+	auditPruneInterval = time.Hour
+)
+
+// ScoreEventRetention is how long score_events rows are kept before the
+// pruner deletes them; zero or less keeps them forever. Set from
+// SCORE_AUDIT_RETENTION at startup.
+var ScoreEventRetention = 7 * 24 * time.Hour
+
+// ErrAuditQueueFull is returned when the audit queue has no room left.
+var ErrAuditQueueFull = errors.New("db: score audit queue full")
+
+// ScoreEvent is one accepted score update in the audit trail.
+type ScoreEvent struct {
+	PlayerID   string    `json:"playerId"`
+	RoomID     string    `json:"roomId"`
+	Score      int       `json:"score"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// ListScoreEvents returns one player's audited updates from one room, oldest
+// first, so a review can follow how the run's score moved.
+func ListScoreEvents(ctx context.Context, pool *pgxpool.Pool, playerID, roomID string, limit int) ([]ScoreEvent, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT player_id, room_id, score, recorded_at
+		FROM score_events
+		WHERE player_id = $1 AND room_id = $2
+		ORDER BY recorded_at ASC
+		LIMIT $3`, playerID, roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list score events: %w", err)
+	}
+	defer rows.Close()
+	events := []ScoreEvent{}
+	for rows.Next() {
+		var e ScoreEvent
+		if err := rows.Scan(&e.PlayerID, &e.RoomID, &e.Score, &e.RecordedAt); err != nil {
+			return nil, fmt.Errorf("list score events: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// AuditWriter appends accepted score updates to score_events asynchronously,
+// batching like the ScoreWriter. Unlike the leaderboard writer it does not
+// retry: the trail is evidence for later investigations, not game state, so a
+// batch that fails against a struggling database is logged and dropped rather
+// than allowed to pile up.
+type AuditWriter struct {
+	pool  *pgxpool.Pool
+	queue chan ScoreEvent
+	done  chan struct{}
+}
+
+// NewAuditWriter starts the audit worker and its retention pruner.
+func NewAuditWriter(pool *pgxpool.Pool) *AuditWriter {
+	w := &AuditWriter{
+		pool:  pool,
+		queue: make(chan ScoreEvent, auditQueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	go w.prune()
+	return w
+}
+
+// Enqueue queues one accepted score update. It never blocks: when the queue
+// is full the event is dropped and ErrAuditQueueFull comes back.
+func (w *AuditWriter) Enqueue(playerID, roomID string, score int) error {
+	e := ScoreEvent{PlayerID: playerID, RoomID: roomID, Score: score, RecordedAt: time.Now()}
+	select {
+	case w.queue <- e:
+		return nil
+	default:
+		return ErrAuditQueueFull
+	}
+}
+
+// Close stops accepting events and blocks until the queue has drained.
+func (w *AuditWriter) Close() {
+	close(w.queue)
+	<-w.done
+}
+
+// run collects queued events into batches and flushes them when a batch
+// fills up or the flush interval elapses.
+func (w *AuditWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ScoreEvent, 0, auditBatchSize)
+	for {
+		select {
+		case e, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= auditBatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush writes one batch in a single multi-row INSERT, dropping it on error.
+func (w *AuditWriter) flush(batch []ScoreEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO score_events (player_id, room_id, score, recorded_at) VALUES `)
+	args := make([]interface{}, 0, len(batch)*4)
+	for i, e := range batch {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		base := i * 4
+		sb.WriteString("($" + strconv.Itoa(base+1) + ", $" + strconv.Itoa(base+2) +
+			", $" + strconv.Itoa(base+3) + ", $" + strconv.Itoa(base+4) + ")")
+		args = append(args, e.PlayerID, e.RoomID, e.Score, e.RecordedAt)
+	}
+	if _, err := w.pool.Exec(ctx, sb.String(), args...); err != nil {
+		slog.Warn("score audit batch dropped", "rows", len(batch), "error", err)
+	}
+}
+
+// prune deletes audit rows older than the retention window, once per
+// interval, until the writer is closed.
+func (w *AuditWriter) prune() {
+	ticker := time.NewTicker(auditPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if ScoreEventRetention <= 0 {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			tag, err := w.pool.Exec(ctx, `DELETE FROM score_events WHERE recorded_at < $1`,
+				time.Now().Add(-ScoreEventRetention))
+			cancel()
+			if err != nil {
+				slog.Error("score audit prune failed", "error", err)
+				continue
+			}
+			if tag.RowsAffected() > 0 {
+				slog.Info("score audit pruned", "rows", tag.RowsAffected(), "retention", ScoreEventRetention)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Score writer tuning.
+const (
+	// scoreQueueSize is how many pending scores can back up before new ones
+	// are dead-lettered instead of blocking the hub.
+	scoreQueueSize = 1024
+	// scoreBatchSize caps how many rows go into one INSERT.
+	scoreBatchSize = 100
+	// scoreFlushInterval bounds how long a partial batch waits.
+	scoreFlushInterval = 500 * time.Millisecond
+	// scoreMaxAttempts and scoreRetryBase shape the exponential backoff:
+	// 250ms, 500ms, 1s, 2s, 4s.
+	scoreMaxAttempts = 5
+	scoreRetryBase   = 250 * time.Millisecond
+)
+
+// ErrScoreQueueFull is returned when the persistence queue has no room left.
+var ErrScoreQueueFull = errors.New("db: score queue full")
+
+// pendingScore is one enqueued leaderboard row. CreatedAt is captured at
+// enqueue time so batching doesn't skew timestamps.
+type pendingScore struct {
+	PlayerID   string
+	PlayerName string
+	Score      int
+	Country    string
+	RoomID     string
+	CreatedAt  time.Time
+}
+
+// ScoreWriter persists scores asynchronously: writes go into a buffered
+// queue, a single worker flushes them in batches, and failed batches are
+// retried with exponential backoff before being dead-lettered to the log.
+// This keeps the WS read loop off the Postgres hot path and rides out
+// transient database blips without losing leaderboard entries.
+type ScoreWriter struct {
+	pool  *pgxpool.Pool
+	queue chan pendingScore
+	done  chan struct{}
+	// dailyCap bounds inserts per player per UTC day; see keepScore. Set
+	// once at startup, before any scores flow.
+	dailyCap int
+}
+
+// NewScoreWriter starts the persistence worker.
+func NewScoreWriter(pool *pgxpool.Pool) *ScoreWriter {
+	w := &ScoreWriter{
+		pool:  pool,
+		queue: make(chan pendingScore, scoreQueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Enqueue queues one score for persistence. It never blocks: when the queue
+// is full the row is dead-lettered immediately and ErrScoreQueueFull comes
+// back.
+func (w *ScoreWriter) Enqueue(playerID, playerName string, score int, country, roomID string) error {
+	p := pendingScore{PlayerID: playerID, PlayerName: playerName, Score: score, Country: country, RoomID: roomID, CreatedAt: time.Now()}
+	select {
+	case w.queue <- p:
+		return nil
+	default:
+		deadLetter([]pendingScore{p}, ErrScoreQueueFull)
+		return ErrScoreQueueFull
+	}
+}
+
+// Close stops accepting scores and blocks until the queue has drained.
+func (w *ScoreWriter) Close() {
+	close(w.queue)
+	<-w.done
+}
+
+// run collects queued scores into batches and flushes them when a batch
+// fills up or the flush interval elapses.
+func (w *ScoreWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(scoreFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pendingScore, 0, scoreBatchSize)
+	for {
+		select {
+		case p, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, p)
+			if len(batch) >= scoreBatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush writes one batch, retrying with exponential backoff. A batch that
+// still fails after the last attempt is dead-lettered: every row is logged
+// with its full contents so it can be replayed by hand.
+func (w *ScoreWriter) flush(batch []pendingScore) {
+	if len(batch) == 0 {
+		return
+	}
+	var err error
+	for attempt := 0; attempt < scoreMaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(scoreRetryBase << (attempt - 1))
+		}
+		if err = w.insert(batch); err == nil {
+			return
+		}
+		slog.Warn("score batch insert failed", "rows", len(batch), "attempt", attempt+1, "error", err)
+	}
+	deadLetter(batch, err)
+}
+
+// keepScore is the daily-cap policy: under the cap every run is kept, over
+// it only runs beating the player's current best — so the table can't be
+// bloated with junk rows, but no personal best is ever lost. A cap of zero
+// or less keeps everything.
+func keepScore(todayCount, cap, score, best int) bool {
+	return cap <= 0 || todayCount < cap || score > best
+}
+
+// insert writes the whole batch in a single multi-row INSERT, or one
+// conditional INSERT per row when a daily cap is configured.
+func (w *ScoreWriter) insert(batch []pendingScore) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if w.dailyCap > 0 {
+		return w.insertCapped(ctx, batch)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO leaderboard (player_id, player_name, score, created_at, verified, country, room_id, season_id) VALUES `)
+	args := make([]interface{}, 0, len(batch)*7)
+	for i, p := range batch {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		base := i * 7
+		sb.WriteString("($" + strconv.Itoa(base+1) + ", $" + strconv.Itoa(base+2) +
+			", $" + strconv.Itoa(base+3) + ", $" + strconv.Itoa(base+4) + ", $" + strconv.Itoa(base+5) +
+			", $" + strconv.Itoa(base+6) + ", $" + strconv.Itoa(base+7) + ", " + currentSeasonSubquery + ")")
+		args = append(args, p.PlayerID, p.PlayerName, p.Score, p.CreatedAt, Verified(p.PlayerID), p.Country, p.RoomID)
+	}
+	_, err := w.pool.Exec(ctx, sb.String(), args...)
+	return err
+}
+
+// cappedInsertSQL applies the keepScore policy inside Postgres, where the
+// authoritative per-day counts live.
+const cappedInsertSQL = `
+	INSERT INTO leaderboard (player_id, player_name, score, created_at, verified, country, room_id, season_id)
+	SELECT $1, $2, $3, $4, $5, $6, $7, ` + currentSeasonSubquery + `
+	WHERE (SELECT COUNT(*) FROM leaderboard
+	       WHERE player_id = $1 AND created_at >= date_trunc('day', $4::timestamptz)) < $8
+	   OR $3 > COALESCE((SELECT MAX(score) FROM leaderboard WHERE player_id = $1), -1)`
+
+// insertCapped queues one conditional INSERT per row into a single batched
+// round trip; rows over their player's cap silently insert nothing.
+func (w *ScoreWriter) insertCapped(ctx context.Context, batch []pendingScore) error {
+	b := &pgx.Batch{}
+	for _, p := range batch {
+		b.Queue(cappedInsertSQL, p.PlayerID, p.PlayerName, p.Score, p.CreatedAt, Verified(p.PlayerID), p.Country, p.RoomID, w.dailyCap)
+	}
+	return w.pool.SendBatch(ctx, b).Close()
+}
+
+// deadLetter logs dropped rows in a grep-able shape of last resort.
+func deadLetter(batch []pendingScore, err error) {
+	for _, p := range batch {
+		slog.Error("score dead-lettered",
+			"player_id", p.PlayerID, "player_name", p.PlayerName, "score", p.Score,
+			"country", p.Country, "room_id", p.RoomID, "created_at", p.CreatedAt, "error", err)
+	}
+}
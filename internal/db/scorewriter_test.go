@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKeepScore(t *testing.T) {
+	cases := []struct {
+		name                    string
+		today, cap, score, best int
+		want                    bool
+	}{
+		{"under cap", 1, 2, 5, 100, true},
+		{"at cap, not a best", 2, 2, 5, 100, false},
+		{"at cap, new best", 2, 2, 101, 100, true},
+		{"at cap, ties best", 2, 2, 100, 100, false},
+		{"cap disabled", 50, 0, 1, 100, true},
+		{"first run ever over cap", 2, 2, 0, -1, true},
+	}
+	for _, tc := range cases {
+		if got := keepScore(tc.today, tc.cap, tc.score, tc.best); got != tc.want {
+			t.Errorf("%s: keepScore(%d, %d, %d, %d) = %v, want %v",
+				tc.name, tc.today, tc.cap, tc.score, tc.best, got, tc.want)
+		}
+	}
+}
+
+func TestMemStoreDailyScoreCap(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemStore()
+	store.DailyScoreCap = 2
+
+	for _, score := range []int{10, 5} {
+		if err := store.SaveScore(ctx, "p1", "Dino", score, "", ""); err != nil {
+			t.Fatalf("SaveScore(%d): %v", score, err)
+		}
+	}
+	if len(store.Scores) != 2 {
+		t.Fatalf("got %d rows under the cap, want 2", len(store.Scores))
+	}
+
+	// Over the cap: a middling run is dropped, a new best still lands.
+	if err := store.SaveScore(ctx, "p1", "Dino", 7, "", ""); err != nil {
+		t.Fatalf("SaveScore(7): %v", err)
+	}
+	if len(store.Scores) != 2 {
+		t.Fatalf("got %d rows after capped save, want 2", len(store.Scores))
+	}
+	if err := store.SaveScore(ctx, "p1", "Dino", 12, "", ""); err != nil {
+		t.Fatalf("SaveScore(12): %v", err)
+	}
+	if len(store.Scores) != 3 || store.Scores[2].Score != 12 {
+		t.Fatalf("new best not kept: %+v", store.Scores)
+	}
+
+	// Other players have their own budget.
+	if err := store.SaveScore(ctx, "p2", "Rex", 1, "", ""); err != nil {
+		t.Fatalf("SaveScore p2: %v", err)
+	}
+	if len(store.Scores) != 4 {
+		t.Fatalf("got %d rows after p2 save, want 4", len(store.Scores))
+	}
+}
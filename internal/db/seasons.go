@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// currentSeasonSubquery selects the open season's ID; it is embedded where a
+// query needs "the season we are in right now".
+const currentSeasonSubquery = `(SELECT id FROM seasons WHERE ended_at IS NULL ORDER BY id DESC LIMIT 1)`
+
+// seasonArchiveSize is how many final standings are snapshotted when a
+// season closes.
+const seasonArchiveSize = 100
+
+// Season is one row of the seasons table.
+type Season struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+// CurrentSeason returns the open season.
+func CurrentSeason(ctx context.Context, pool *pgxpool.Pool) (Season, error) {
+	var s Season
+	err := pool.QueryRow(ctx,
+		`SELECT id, name, started_at, ended_at FROM seasons WHERE ended_at IS NULL ORDER BY id DESC LIMIT 1`).
+		Scan(&s.ID, &s.Name, &s.StartedAt, &s.EndedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Season{}, fmt.Errorf("no open season")
+	}
+	if err != nil {
+		return Season{}, fmt.Errorf("current season: %w", err)
+	}
+	return s, nil
+}
+
+// GetSeason returns one season by ID.
+func GetSeason(ctx context.Context, pool *pgxpool.Pool, id int) (Season, bool, error) {
+	var s Season
+	err := pool.QueryRow(ctx,
+		`SELECT id, name, started_at, ended_at FROM seasons WHERE id = $1`, id).
+		Scan(&s.ID, &s.Name, &s.StartedAt, &s.EndedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Season{}, false, nil
+	}
+	if err != nil {
+		return Season{}, false, fmt.Errorf("get season: %w", err)
+	}
+	return s, true, nil
+}
+
+// ListSeasons returns every season, newest first.
+func ListSeasons(ctx context.Context, pool *pgxpool.Pool) ([]Season, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT id, name, started_at, ended_at FROM seasons ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list seasons: %w", err)
+	}
+	defer rows.Close()
+	seasons := []Season{}
+	for rows.Next() {
+		var s Season
+		if err := rows.Scan(&s.ID, &s.Name, &s.StartedAt, &s.EndedAt); err != nil {
+			return nil, fmt.Errorf("scan season: %w", err)
+		}
+		seasons = append(seasons, s)
+	}
+	return seasons, rows.Err()
+}
+
+// CloseSeason ends the open season atomically: the final standings (each
+// player's best score) are snapshotted into season_standings, the season is
+// marked ended, and the next season is opened. It returns the closed and the
+// new season.
+func CloseSeason(ctx context.Context, pool *pgxpool.Pool) (closed, next Season, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return Season{}, Season{}, fmt.Errorf("close season: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		`SELECT id, name, started_at FROM seasons WHERE ended_at IS NULL ORDER BY id DESC LIMIT 1 FOR UPDATE`).
+		Scan(&closed.ID, &closed.Name, &closed.StartedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Season{}, Season{}, fmt.Errorf("no open season to close")
+	}
+	if err != nil {
+		return Season{}, Season{}, fmt.Errorf("close season: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO season_standings (season_id, rank, player_id, player_name, score)
+		SELECT $1, ROW_NUMBER() OVER (ORDER BY score DESC, created_at ASC), player_id, player_name, score
+		FROM (
+			SELECT DISTINCT ON (player_id) player_id, player_name, score, created_at
+			FROM leaderboard WHERE season_id = $1
+			ORDER BY player_id, score DESC, created_at ASC
+		) best
+		ORDER BY score DESC, created_at ASC
+		LIMIT $2`, closed.ID, seasonArchiveSize)
+	if err != nil {
+		return Season{}, Season{}, fmt.Errorf("archive standings: %w", err)
+	}
+
+	now := time.Now()
+	if _, err = tx.Exec(ctx, `UPDATE seasons SET ended_at = $1 WHERE id = $2`, now, closed.ID); err != nil {
+		return Season{}, Season{}, fmt.Errorf("end season: %w", err)
+	}
+	closed.EndedAt = &now
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO seasons (name) VALUES ($1) RETURNING id, name, started_at`,
+		fmt.Sprintf("Season %d", closed.ID+1)).
+		Scan(&next.ID, &next.Name, &next.StartedAt)
+	if err != nil {
+		return Season{}, Season{}, fmt.Errorf("open next season: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Season{}, Season{}, fmt.Errorf("close season: %w", err)
+	}
+	return closed, next, nil
+}
+
+// Standing is one archived row of a closed season's final leaderboard.
+type Standing struct {
+	Rank       int    `json:"rank"`
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`
+}
+
+// SeasonStandings returns a page of a closed season's archived standings and
+// the archive's total size.
+func SeasonStandings(ctx context.Context, pool *pgxpool.Pool, seasonID, limit, offset int) ([]Standing, int, error) {
+	var total int
+	if err := pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM season_standings WHERE season_id = $1`, seasonID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count standings: %w", err)
+	}
+	rows, err := pool.Query(ctx, `
+		SELECT rank, player_id, player_name, score
+		FROM season_standings WHERE season_id = $1
+		ORDER BY rank ASC LIMIT $2 OFFSET $3`, seasonID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query standings: %w", err)
+	}
+	defer rows.Close()
+	standings := []Standing{}
+	for rows.Next() {
+		var s Standing
+		if err := rows.Scan(&s.Rank, &s.PlayerID, &s.PlayerName, &s.Score); err != nil {
+			return nil, 0, fmt.Errorf("scan standing: %w", err)
+		}
+		standings = append(standings, s)
+	}
+	return standings, total, rows.Err()
+}
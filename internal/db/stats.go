@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Match outcomes recorded into player_stats.
+const (
+	OutcomeWin  = "win"
+	OutcomeLoss = "loss"
+	OutcomeDraw = "draw"
+)
+
+// Stats is one row of player_stats. AvgScore is derived from the running
+// total when the row is read.
+type Stats struct {
+	PlayerID   string  `json:"playerId"`
+	PlayerName string  `json:"playerName"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	BestScore  int     `json:"bestScore"`
+	AvgScore   float64 `json:"avgScore"`
+	// BotGames is how many of the games were against fallback bots.
+	BotGames int `json:"botGames"`
+	// WinStreak is the current run of consecutive wins; any loss or draw
+	// resets it.
+	WinStreak int `json:"winStreak"`
+}
+
+// RecordMatchResult folds one finished match into a player's aggregate stats.
+// outcome is one of OutcomeWin, OutcomeLoss or OutcomeDraw; vsBot marks
+// matches played against a fallback bot.
+func RecordMatchResult(ctx context.Context, pool *pgxpool.Pool, playerID, playerName string, score int, outcome string, vsBot bool) error {
+	wins, losses, draws := 0, 0, 0
+	switch outcome {
+	case OutcomeWin:
+		wins = 1
+	case OutcomeLoss:
+		losses = 1
+	case OutcomeDraw:
+		draws = 1
+	default:
+		return fmt.Errorf("record match result: unknown outcome %q", outcome)
+	}
+	botGames := 0
+	if vsBot {
+		botGames = 1
+	}
+	_, err := pool.Exec(ctx, `
+		INSERT INTO player_stats (player_id, player_name, games, wins, losses, draws, best_score, total_score, bot_games, win_streak)
+		VALUES ($1, $2, 1, $3, $4, $5, $6, $6, $7, $3)
+		ON CONFLICT (player_id) DO UPDATE
+		SET player_name = EXCLUDED.player_name,
+		    games = player_stats.games + 1,
+		    wins = player_stats.wins + EXCLUDED.wins,
+		    losses = player_stats.losses + EXCLUDED.losses,
+		    draws = player_stats.draws + EXCLUDED.draws,
+		    best_score = GREATEST(player_stats.best_score, EXCLUDED.best_score),
+		    total_score = player_stats.total_score + EXCLUDED.best_score,
+		    bot_games = player_stats.bot_games + EXCLUDED.bot_games,
+		    win_streak = CASE WHEN EXCLUDED.wins = 1 THEN player_stats.win_streak + 1 ELSE 0 END,
+		    updated_at = now()`,
+		playerID, playerName, wins, losses, draws, score, botGames)
+	if err != nil {
+		return fmt.Errorf("record match result: %w", err)
+	}
+	return nil
+}
+
+// GetBestScore returns a player's stored best score, zero for players
+// without a stats row yet.
+func GetBestScore(ctx context.Context, pool *pgxpool.Pool, playerID string) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var best int
+	err := pool.QueryRow(ctx,
+		`SELECT best_score FROM player_stats WHERE player_id = $1`,
+		playerID).Scan(&best)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get best score: %w", err)
+	}
+	return best, nil
+}
+
+// GetStats returns a player's aggregate stats, or a zero-valued profile for
+// players who have not finished a match yet.
+func GetStats(ctx context.Context, pool *pgxpool.Pool, playerID string) (Stats, error) {
+	var s Stats
+	var total int64
+	err := pool.QueryRow(ctx,
+		`SELECT player_id, player_name, games, wins, losses, draws, best_score, total_score, bot_games, win_streak
+		 FROM player_stats WHERE player_id = $1`,
+		playerID).Scan(&s.PlayerID, &s.PlayerName, &s.Games, &s.Wins, &s.Losses, &s.Draws, &s.BestScore, &total, &s.BotGames, &s.WinStreak)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Stats{PlayerID: playerID}, nil
+	}
+	if err != nil {
+		return Stats{}, fmt.Errorf("get stats: %w", err)
+	}
+	if s.Games > 0 {
+		s.AvgScore = float64(total) / float64(s.Games)
+	}
+	return s, nil
+}
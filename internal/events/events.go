@@ -0,0 +1,135 @@
+// Package events is the in-process pub/sub bus for game lifecycle events.
+// The ws handler publishes as matches are created, scores change, players
+// die, and matches resolve; subsystems that want to react — the live
+// leaderboard stream, outbound webhooks, metrics, replay recorders —
+// subscribe here instead of being called directly from message dispatch, so
+// new consumers never need to edit the handler.
+//
+// Handlers run synchronously on the publisher's goroutine and must not
+// block; anything slow should queue internally, the way the webhook
+// notifier and the async score writer already do.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is implemented by every lifecycle event carried on the bus.
+type Event interface {
+	// Kind is the subscription key for the event, one of the Kind*
+	// constants.
+	Kind() string
+}
+
+// Subscription keys, one per event type.
+const (
+	KindMatchCreated  = "match_created"
+	KindScoreUpdated  = "score_updated"
+	KindPlayerDied    = "player_died"
+	KindMatchFinished = "match_finished"
+)
+
+// Player identifies one participant in an event. It mirrors the wire-level
+// player info without depending on the ws package.
+type Player struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Rating int    `json:"rating,omitempty"`
+	// Team is set in team mode: "A" or "B".
+	Team string `json:"team,omitempty"`
+}
+
+// MatchCreated fires when a room is set up, before the ready/countdown
+// handshake.
+type MatchCreated struct {
+	RoomID  string   `json:"roomId"`
+	Players []Player `json:"players"`
+	Format  string   `json:"format"`
+	Seed    int64    `json:"seed"`
+}
+
+func (MatchCreated) Kind() string { return KindMatchCreated }
+
+// ScoreUpdated fires for every UPDATE_SCORE that survives validation and
+// anti-cheat. It is high-frequency; subscribers should sample or aggregate.
+type ScoreUpdated struct {
+	RoomID   string `json:"roomId"`
+	PlayerID string `json:"playerId"`
+	Score    int    `json:"score"`
+}
+
+func (ScoreUpdated) Kind() string { return KindScoreUpdated }
+
+// PlayerDied fires when a player's run ends and their final score has been
+// persisted — a clean PLAYER_DIED, a timed match expiring, a forfeit, or a
+// reaped room.
+type PlayerDied struct {
+	RoomID     string `json:"roomId"`
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`
+}
+
+func (PlayerDied) Kind() string { return KindPlayerDied }
+
+// MatchFinished summarizes one resolved match, however it ended.
+type MatchFinished struct {
+	RoomID  string         `json:"roomId"`
+	Players []Player       `json:"players"`
+	Scores  map[string]int `json:"scores"`
+	// WinnerID is empty for draws; WinningTeam is only set in team mode.
+	WinnerID    string    `json:"winnerId"`
+	WinningTeam string    `json:"winningTeam,omitempty"`
+	Reason      string    `json:"reason"`
+	DurationMs  int64     `json:"durationMs"`
+	FinishedAt  time.Time `json:"finishedAt"`
+}
+
+func (MatchFinished) Kind() string { return KindMatchFinished }
+
+// Handler consumes one event.
+type Handler func(Event)
+
+// Bus fans events out to subscribers. A nil *Bus is valid and drops every
+// publish, so publishers don't need to guard against an unwired bus.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string][]Handler
+	all  []Handler
+}
+
+// NewBus returns an empty bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[string][]Handler)}
+}
+
+// Subscribe registers fn for every event of the given kind.
+func (b *Bus) Subscribe(kind string, fn Handler) {
+	b.mu.Lock()
+	b.subs[kind] = append(b.subs[kind], fn)
+	b.mu.Unlock()
+}
+
+// SubscribeAll registers fn for every event regardless of kind, e.g. for a
+// replay recorder or a metrics counter.
+func (b *Bus) SubscribeAll(fn Handler) {
+	b.mu.Lock()
+	b.all = append(b.all, fn)
+	b.mu.Unlock()
+}
+
+// Publish delivers e to every matching subscriber, in registration order.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.subs[e.Kind()])+len(b.all))
+	handlers = append(handlers, b.subs[e.Kind()]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
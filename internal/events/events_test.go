@@ -0,0 +1,29 @@
+package events
+
+import "testing"
+
+func TestPublishRoutesByKind(t *testing.T) {
+	bus := NewBus()
+	var died []PlayerDied
+	var all int
+	bus.Subscribe(KindPlayerDied, func(e Event) {
+		died = append(died, e.(PlayerDied))
+	})
+	bus.SubscribeAll(func(Event) { all++ })
+
+	bus.Publish(PlayerDied{RoomID: "room_1", PlayerID: "p1", Score: 12})
+	bus.Publish(MatchFinished{RoomID: "room_1"})
+
+	if len(died) != 1 || died[0].Score != 12 {
+		t.Fatalf("player-died subscriber saw %+v, want one event with score 12", died)
+	}
+	if all != 2 {
+		t.Fatalf("subscribe-all saw %d events, want 2", all)
+	}
+}
+
+func TestNilBusDropsPublishes(t *testing.T) {
+	var bus *Bus
+	// Must not panic.
+	bus.Publish(ScoreUpdated{RoomID: "room_1", PlayerID: "p1", Score: 3})
+}
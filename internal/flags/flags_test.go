@@ -0,0 +1,46 @@
+package flags
+
+import "testing"
+
+func TestLocalStoreDefaultsAndOverrides(t *testing.T) {
+	s := NewStore(nil)
+	if !s.Enabled(nil, Matchmaking) {
+		t.Fatal("unset boolean flag should count as enabled")
+	}
+
+	if err := s.Set(nil, Matchmaking, "false"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Enabled(nil, Matchmaking) {
+		t.Fatal("flag still enabled after being set to false")
+	}
+	if got := s.All(nil); got[Matchmaking] != "false" {
+		t.Fatalf("All = %v, want matchmaking_enabled=false", got)
+	}
+
+	// Clearing restores the default.
+	if err := s.Set(nil, Matchmaking, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Enabled(nil, Matchmaking) {
+		t.Fatal("cleared flag should count as enabled again")
+	}
+}
+
+func TestNilStoreFailsOpen(t *testing.T) {
+	var s *Store
+	if !s.Enabled(nil, LeaderboardWrites) {
+		t.Fatal("nil store should report flags as enabled")
+	}
+	if s.Get(nil, MaintenanceBanner) != "" {
+		t.Fatal("nil store should report flags as unset")
+	}
+}
+
+func TestUnparsableValueFailsOpen(t *testing.T) {
+	s := NewStore(nil)
+	s.Set(nil, Matchmaking, "banana")
+	if !s.Enabled(nil, Matchmaking) {
+		t.Fatal("unparsable flag value should count as enabled")
+	}
+}
@@ -0,0 +1,24 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// ChallengeDay formats t as the UTC day key ("2006-01-02") the daily
+// challenge runs under. The challenge rolls over at UTC midnight everywhere,
+// so players in different time zones compete on the same board.
+func ChallengeDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// DailySeed derives the shared obstacle seed of one challenge day from its
+// day key. Every player who joins that day gets the same layout, and the
+// derivation is deterministic, so separate server instances agree without
+// coordinating.
+func DailySeed(day string) int64 {
+	sum := sha256.Sum256([]byte("daily-challenge:" + day))
+	// Clear the sign bit: match seeds are non-negative everywhere else.
+	return int64(binary.BigEndian.Uint64(sum[:8]) &^ (1 << 63))
+}
@@ -0,0 +1,238 @@
+package game
+
+import (
+	"time"
+)
+
+// Simulation tick rate. 20 ticks per second is plenty for a runner where the
+// only inputs are jump and duck.
+const (
+	TickRate     = 20
+	tickInterval = time.Second / TickRate
+)
+
+// Physics constants, mirrored by the frontend so the authoritative
+// simulation and the local prediction agree.
+const (
+	gravity      = 1600.0 // px/s^2
+	jumpVelocity = 520.0  // px/s
+	// obstacleTravelTime is how long an obstacle takes to scroll from the
+	// right edge to the dino at base speed.
+	obstacleTravelTime = 3 * time.Second
+	// hitWindow is the slice of time around an obstacle's arrival during
+	// which the player must be evading it.
+	hitWindow = 90 * time.Millisecond
+	// airborneThreshold is the height above which a dino clears a cactus.
+	airborneThreshold = 30.0
+)
+
+// InputKind enumerates the actions a player can take.
+type InputKind string
+
+const (
+	InputJump   InputKind = "jump"
+	InputDuck   InputKind = "duck"
+	InputUnduck InputKind = "unduck"
+)
+
+// Input is one action from one player, fed into the room's loop.
+type Input struct {
+	PlayerID string
+	Kind     InputKind
+}
+
+// PlayerSnapshot is the authoritative view of one player at a tick.
+type PlayerSnapshot struct {
+	ID      string  `json:"id"`
+	Y       float64 `json:"y"`
+	Ducking bool    `json:"ducking"`
+	Alive   bool    `json:"alive"`
+	Score   int     `json:"score"`
+}
+
+// Snapshot is the authoritative room state broadcast every tick.
+type Snapshot struct {
+	Tick      int64            `json:"tick"`
+	ElapsedMs int64            `json:"elapsedMs"`
+	Speed     float64          `json:"speed"`
+	Players   []PlayerSnapshot `json:"players"`
+}
+
+// playerState is the mutable per-player simulation state.
+type playerState struct {
+	id      string
+	y       float64
+	vel     float64
+	ducking bool
+	alive   bool
+	score   float64
+}
+
+// Loop runs the authoritative simulation for one room. All state is owned by
+// the Run goroutine; the only way in is the inputs channel.
+type Loop struct {
+	roomID    string
+	timeline  *Timeline
+	players   []*playerState
+	inputs    chan Input
+	stop      chan struct{}
+	broadcast func(Snapshot)
+}
+
+// NewLoop builds a loop for a room. broadcast is invoked from the loop
+// goroutine once per tick with the current snapshot.
+func NewLoop(roomID string, seed int64, playerIDs []string, broadcast func(Snapshot)) *Loop {
+	l := &Loop{
+		roomID:    roomID,
+		timeline:  NewTimeline(seed),
+		inputs:    make(chan Input, 64),
+		stop:      make(chan struct{}),
+		broadcast: broadcast,
+	}
+	for _, id := range playerIDs {
+		l.players = append(l.players, &playerState{id: id, alive: true})
+	}
+	return l
+}
+
+// Input queues a player action; it never blocks the caller.
+func (l *Loop) Input(in Input) {
+	select {
+	case l.inputs <- in:
+	default:
+	}
+}
+
+// Stop terminates the loop goroutine. Safe to call more than once.
+func (l *Loop) Stop() {
+	select {
+	case <-l.stop:
+	default:
+		close(l.stop)
+	}
+}
+
+// Run advances the simulation at the fixed tick rate until every player is
+// dead or the loop is stopped. It must be called in its own goroutine.
+func (l *Loop) Run() {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	var tick int64
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case in := <-l.inputs:
+			l.applyInput(in)
+		case <-ticker.C:
+			tick++
+			elapsed := time.Since(start)
+			l.step(elapsed)
+			l.broadcast(l.snapshot(tick, elapsed))
+			if l.allDead() {
+				return
+			}
+		}
+	}
+}
+
+// applyInput mutates a player's state for one action.
+func (l *Loop) applyInput(in Input) {
+	for _, p := range l.players {
+		if p.id != in.PlayerID || !p.alive {
+			continue
+		}
+		switch in.Kind {
+		case InputJump:
+			if p.y == 0 && !p.ducking {
+				p.vel = jumpVelocity
+			}
+		case InputDuck:
+			if p.y == 0 {
+				p.ducking = true
+			}
+		case InputUnduck:
+			p.ducking = false
+		}
+	}
+}
+
+// step advances physics, scoring, and collisions by one tick.
+func (l *Loop) step(elapsed time.Duration) {
+	speed := speedAt(elapsed)
+	dt := tickInterval.Seconds()
+	for _, p := range l.players {
+		if !p.alive {
+			continue
+		}
+		p.score += BasePointsPerSecond * speed * dt
+
+		p.vel -= gravity * dt
+		p.y += p.vel * dt
+		if p.y <= 0 {
+			p.y = 0
+			p.vel = 0
+		}
+
+		if l.hitsObstacle(elapsed, p) {
+			p.alive = false
+		}
+	}
+}
+
+// hitsObstacle reports whether a player fails to evade an obstacle whose
+// arrival window contains the current elapsed time.
+func (l *Loop) hitsObstacle(elapsed time.Duration, p *playerState) bool {
+	for _, o := range l.timeline.obstacles {
+		arrive := o.SpawnAt + time.Duration(float64(obstacleTravelTime)/speedAt(o.SpawnAt))
+		if arrive > elapsed+hitWindow {
+			break
+		}
+		if elapsed < arrive-hitWindow || elapsed > arrive+hitWindow {
+			continue
+		}
+		switch o.Type {
+		case ObstaclePterodactyl:
+			// Flies at head height: duck under it or jump clear over it.
+			if !p.ducking && p.y < airborneThreshold {
+				return true
+			}
+		default:
+			if p.y < airborneThreshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// snapshot captures the current state for broadcast.
+func (l *Loop) snapshot(tick int64, elapsed time.Duration) Snapshot {
+	s := Snapshot{
+		Tick:      tick,
+		ElapsedMs: elapsed.Milliseconds(),
+		Speed:     speedAt(elapsed),
+	}
+	for _, p := range l.players {
+		s.Players = append(s.Players, PlayerSnapshot{
+			ID:      p.id,
+			Y:       p.y,
+			Ducking: p.ducking,
+			Alive:   p.alive,
+			Score:   int(p.score),
+		})
+	}
+	return s
+}
+
+// allDead reports whether the round is over.
+func (l *Loop) allDead() bool {
+	for _, p := range l.players {
+		if p.alive {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package game
+
+import "sync"
+
+// LoopManager tracks the running simulation loop of every active room.
+type LoopManager struct {
+	mu    sync.Mutex
+	loops map[string]*Loop
+}
+
+// NewLoopManager returns an empty manager.
+func NewLoopManager() *LoopManager {
+	return &LoopManager{loops: make(map[string]*Loop)}
+}
+
+// Start creates and runs the loop for a room.
+func (m *LoopManager) Start(roomID string, seed int64, playerIDs []string, broadcast func(Snapshot)) {
+	l := NewLoop(roomID, seed, playerIDs, broadcast)
+	m.mu.Lock()
+	m.loops[roomID] = l
+	m.mu.Unlock()
+	go func() {
+		l.Run()
+		m.Stop(roomID)
+	}()
+}
+
+// Input forwards a player action to the room's loop, if it is still running.
+func (m *LoopManager) Input(roomID string, in Input) {
+	m.mu.Lock()
+	l, ok := m.loops[roomID]
+	m.mu.Unlock()
+	if ok {
+		l.Input(in)
+	}
+}
+
+// Stop terminates and forgets the loop for a room.
+func (m *LoopManager) Stop(roomID string) {
+	m.mu.Lock()
+	l, ok := m.loops[roomID]
+	delete(m.loops, roomID)
+	m.mu.Unlock()
+	if ok {
+		l.Stop()
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Power-up kinds a run can spawn. Shield and slow-motion only change how the
+// client plays; double score changes what score the server will accept.
+const (
+	PowerUpShield      = "shield"
+	PowerUpSlowMotion  = "slow_motion"
+	PowerUpDoubleScore = "double_score"
+)
+
+// Power-up tuning.
+const (
+	// minPowerUpGap and maxPowerUpGap bound the time between power-up
+	// spawns.
+	minPowerUpGap = 15 * time.Second
+	maxPowerUpGap = 25 * time.Second
+	// PowerUpCollectWindow is how long a spawned power-up stays collectible.
+	PowerUpCollectWindow = 10 * time.Second
+	// PowerUpEffectDuration is how long a collected power-up's effect lasts.
+	PowerUpEffectDuration = 8 * time.Second
+)
+
+// powerUpSeedSalt decorrelates the power-up RNG from the obstacle RNG so
+// both schedules derive from the one match seed without mirroring each other.
+const powerUpSeedSalt = 0x5f3759df
+
+// PowerUp is a single spawn in the deterministic power-up schedule.
+type PowerUp struct {
+	ID   int
+	Kind string
+	// At is the spawn time as an offset from the start of the run.
+	At time.Duration
+}
+
+var powerUpKinds = []string{PowerUpShield, PowerUpSlowMotion, PowerUpDoubleScore}
+
+// PowerUpSchedule reconstructs the power-up spawns for a seed. Like the
+// obstacle timeline, server and clients derive the same schedule, so the
+// server can check that a claimed pickup actually existed.
+func PowerUpSchedule(seed int64) []PowerUp {
+	rng := rand.New(rand.NewSource(seed ^ powerUpSeedSalt))
+	var out []PowerUp
+	at := maxPowerUpGap // grace period before the first spawn
+	for at < maxTimelineHorizon {
+		out = append(out, PowerUp{
+			ID:   len(out),
+			Kind: powerUpKinds[rng.Intn(len(powerUpKinds))],
+			At:   at,
+		})
+		at += minPowerUpGap + time.Duration(rng.Int63n(int64(maxPowerUpGap-minPowerUpGap)))
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package game
+
+import "math"
+
+// DefaultRating is where new players start.
+const DefaultRating = 1200
+
+// eloK is the maximum rating swing from a single match.
+const eloK = 32
+
+// EloExpected returns the expected score (0..1) for a player rated a against
+// a player rated b.
+func EloExpected(a, b int) float64 {
+	return 1.0 / (1.0 + math.Pow(10, float64(b-a)/400.0))
+}
+
+// EloDelta returns the rating change for a player rated a against a player
+// rated b given the actual score: 1 for a win, 0.5 for a draw, 0 for a loss.
+func EloDelta(a, b int, score float64) int {
+	return int(math.Round(eloK * (score - EloExpected(a, b))))
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RoomState is one stage of a room's lifecycle.
+type RoomState string
+
+// Room lifecycle states. A room waits for READYs, counts down, runs, and
+// finishes; multi-round matches loop back to waiting between rounds. Cleaned
+// is the terminal state: the outcome is resolved and every piece of runtime
+// state — simulation loop, validator entry, player fields — has been
+// released, so a room reaching it twice means two finish paths raced.
+const (
+	RoomWaiting   RoomState = "waiting"
+	RoomCountdown RoomState = "countdown"
+	RoomRunning   RoomState = "running"
+	RoomFinished  RoomState = "finished"
+	RoomCleaned   RoomState = "cleaned"
+)
+
+// roomTransitions lists the legal next states from each state.
+var roomTransitions = map[RoomState][]RoomState{
+	RoomWaiting:   {RoomCountdown, RoomFinished},
+	RoomCountdown: {RoomRunning, RoomFinished},
+	// A finished round of a multi-round match returns to waiting for the
+	// next READY handshake.
+	RoomRunning:  {RoomWaiting, RoomFinished},
+	RoomFinished: {RoomCleaned},
+	RoomCleaned:  {},
+}
+
+// Room is the first-class record of one match room: who plays in it, who
+// watches it, the current round's seed, and where it is in its lifecycle.
+// Membership lives here rather than being inferred from client fields, which
+// is what makes larger modes, spectators, and server ticks tractable.
+type Room struct {
+	ID string
+	// Capacity is how many players the room holds; joins beyond it fail.
+	Capacity int
+
+	mu         sync.RWMutex
+	seed       int64
+	state      RoomState
+	players    map[string]bool
+	spectators map[string]bool
+}
+
+// NewRoom returns a waiting room with the given round seed and player
+// capacity.
+func NewRoom(id string, seed int64, capacity int) *Room {
+	return &Room{
+		ID:         id,
+		Capacity:   capacity,
+		seed:       seed,
+		state:      RoomWaiting,
+		players:    make(map[string]bool),
+		spectators: make(map[string]bool),
+	}
+}
+
+// State returns the room's current lifecycle state.
+func (r *Room) State() RoomState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// Transition moves the room to a new state, failing on anything the
+// lifecycle doesn't allow.
+func (r *Room) Transition(to RoomState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, allowed := range roomTransitions[r.state] {
+		if allowed == to {
+			r.state = to
+			return nil
+		}
+	}
+	return fmt.Errorf("room %s: illegal transition %s -> %s", r.ID, r.state, to)
+}
+
+// Seed returns the seed of the current round.
+func (r *Room) Seed() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.seed
+}
+
+// Reseed installs a fresh seed for the next round.
+func (r *Room) Reseed(seed int64) {
+	r.mu.Lock()
+	r.seed = seed
+	r.mu.Unlock()
+}
+
+// AddPlayer seats a player, failing once the room is full or no longer
+// waiting for players.
+func (r *Room) AddPlayer(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state != RoomWaiting {
+		return fmt.Errorf("room %s: cannot join in state %s", r.ID, r.state)
+	}
+	if !r.players[playerID] && len(r.players) >= r.Capacity {
+		return fmt.Errorf("room %s: full (%d players)", r.ID, r.Capacity)
+	}
+	r.players[playerID] = true
+	return nil
+}
+
+// RemovePlayer unseats a player, e.g. after a forfeit.
+func (r *Room) RemovePlayer(playerID string) {
+	r.mu.Lock()
+	delete(r.players, playerID)
+	r.mu.Unlock()
+}
+
+// HasPlayer reports whether the player is seated in this room.
+func (r *Room) HasPlayer(playerID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.players[playerID]
+}
+
+// Players returns the seated player IDs in no particular order.
+func (r *Room) Players() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.players))
+	for id := range r.players {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// AddSpectator registers a watcher; spectators don't count against capacity
+// and may come and go in any state.
+func (r *Room) AddSpectator(id string) {
+	r.mu.Lock()
+	r.spectators[id] = true
+	r.mu.Unlock()
+}
+
+// RemoveSpectator drops a watcher.
+func (r *Room) RemoveSpectator(id string) {
+	r.mu.Lock()
+	delete(r.spectators, id)
+	r.mu.Unlock()
+}
+
+// SpectatorCount reports how many watchers the room has.
+func (r *Room) SpectatorCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.spectators)
+}
@@ -0,0 +1,65 @@
+package game
+
+import "testing"
+
+func TestRoomLifecycle(t *testing.T) {
+	r := NewRoom("room_test", 42, 2)
+	for _, to := range []RoomState{RoomCountdown, RoomRunning, RoomFinished, RoomCleaned} {
+		if err := r.Transition(to); err != nil {
+			t.Fatalf("legal transition to %s: %v", to, err)
+		}
+	}
+	if got := r.State(); got != RoomCleaned {
+		t.Fatalf("state = %s, want %s", got, RoomCleaned)
+	}
+}
+
+func TestRoomRoundLoop(t *testing.T) {
+	r := NewRoom("room_test", 42, 2)
+	// A multi-round match loops running -> waiting between rounds.
+	for _, to := range []RoomState{RoomCountdown, RoomRunning, RoomWaiting, RoomCountdown, RoomRunning} {
+		if err := r.Transition(to); err != nil {
+			t.Fatalf("round loop transition to %s: %v", to, err)
+		}
+	}
+}
+
+func TestRoomRefusesIllegalTransitions(t *testing.T) {
+	cases := []struct {
+		name string
+		path []RoomState
+		to   RoomState
+	}{
+		{"waiting cannot start running", nil, RoomRunning},
+		{"countdown cannot return to waiting", []RoomState{RoomCountdown}, RoomWaiting},
+		{"finished cannot resume", []RoomState{RoomFinished}, RoomRunning},
+		{"cleaned is terminal", []RoomState{RoomFinished, RoomCleaned}, RoomFinished},
+		{"cleaned cannot repeat", []RoomState{RoomFinished, RoomCleaned}, RoomCleaned},
+	}
+	for _, tc := range cases {
+		r := NewRoom("room_test", 42, 2)
+		for _, to := range tc.path {
+			if err := r.Transition(to); err != nil {
+				t.Fatalf("%s: setup transition to %s: %v", tc.name, to, err)
+			}
+		}
+		from := r.State()
+		if err := r.Transition(tc.to); err == nil {
+			t.Errorf("%s: transition %s -> %s allowed", tc.name, from, tc.to)
+		}
+		if got := r.State(); got != from {
+			t.Errorf("%s: failed transition moved state to %s", tc.name, got)
+		}
+	}
+}
+
+func TestRoomRefusesLateJoins(t *testing.T) {
+	r := NewRoom("room_test", 42, 2)
+	if err := r.AddPlayer("p1"); err != nil {
+		t.Fatalf("join while waiting: %v", err)
+	}
+	r.Transition(RoomCountdown)
+	if err := r.AddPlayer("p2"); err == nil {
+		t.Error("join during countdown allowed")
+	}
+}
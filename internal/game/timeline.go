@@ -0,0 +1,138 @@
+// Package game holds the server-side model of a Dino run. Both clients and
+// the server derive the same obstacle layout from the match seed, which lets
+// the server check that reported scores are actually achievable.
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Tuning constants for the simulation. These mirror the values used by the
+// frontend so the server-side reconstruction matches what players see.
+const (
+	// BasePointsPerSecond is the score rate at the start of a run.
+	BasePointsPerSecond = 10.0
+	// SpeedRampPerSecond is how much the game speeds up each second.
+	SpeedRampPerSecond = 0.012
+	// MaxSpeedMultiplier caps the speed ramp.
+	MaxSpeedMultiplier = 2.5
+
+	// minObstacleGap and maxObstacleGap bound the time between obstacle
+	// spawns at base speed; the gap shrinks as the game speeds up.
+	minObstacleGap = 600 * time.Millisecond
+	maxObstacleGap = 1800 * time.Millisecond
+)
+
+// ObstacleType enumerates the obstacle kinds a run can contain.
+type ObstacleType int
+
+const (
+	ObstacleCactusSmall ObstacleType = iota
+	ObstacleCactusLarge
+	ObstaclePterodactyl
+)
+
+// String returns the wire name of an obstacle type.
+func (t ObstacleType) String() string {
+	switch t {
+	case ObstacleCactusLarge:
+		return "cactus_large"
+	case ObstaclePterodactyl:
+		return "pterodactyl"
+	default:
+		return "cactus_small"
+	}
+}
+
+// Obstacle is a single spawn in the deterministic timeline. Gap is the time
+// until the next spawn, zero for the final obstacle of the timeline.
+type Obstacle struct {
+	Type    ObstacleType
+	SpawnAt time.Duration
+	Gap     time.Duration
+}
+
+// Timeline is the deterministic obstacle schedule for one seed.
+type Timeline struct {
+	Seed      int64
+	obstacles []Obstacle
+}
+
+// maxTimelineHorizon bounds how far ahead obstacles are generated. No run is
+// expected to last longer than this.
+const maxTimelineHorizon = 30 * time.Minute
+
+// NewTimeline reconstructs the obstacle schedule for a seed.
+func NewTimeline(seed int64) *Timeline {
+	rng := rand.New(rand.NewSource(seed))
+	t := &Timeline{Seed: seed}
+	at := maxObstacleGap // grace period before the first obstacle
+	for at < maxTimelineHorizon {
+		t.obstacles = append(t.obstacles, Obstacle{
+			Type:    ObstacleType(rng.Intn(3)),
+			SpawnAt: at,
+		})
+		gap := minObstacleGap + time.Duration(rng.Int63n(int64(maxObstacleGap-minObstacleGap)))
+		// Spawns come faster as the game speeds up.
+		at += time.Duration(float64(gap) / speedAt(at))
+	}
+	for i := range t.obstacles[:len(t.obstacles)-1] {
+		t.obstacles[i].Gap = t.obstacles[i+1].SpawnAt - t.obstacles[i].SpawnAt
+	}
+	return t
+}
+
+// Window returns the obstacles spawning in [from, until), e.g. one batch of
+// the schedule streamed to clients.
+func (t *Timeline) Window(from, until time.Duration) []Obstacle {
+	var out []Obstacle
+	for _, o := range t.obstacles {
+		if o.SpawnAt >= until {
+			break
+		}
+		if o.SpawnAt >= from {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// ObstaclesUntil returns every obstacle spawning before the given elapsed time.
+func (t *Timeline) ObstaclesUntil(elapsed time.Duration) []Obstacle {
+	var out []Obstacle
+	for _, o := range t.obstacles {
+		if o.SpawnAt > elapsed {
+			break
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// speedAt returns the speed multiplier after the given elapsed time.
+func speedAt(elapsed time.Duration) float64 {
+	m := 1.0 + SpeedRampPerSecond*elapsed.Seconds()
+	if m > MaxSpeedMultiplier {
+		m = MaxSpeedMultiplier
+	}
+	return m
+}
+
+// MaxScoreAt returns the highest score a player can legitimately have after
+// the given elapsed time: the integral of the score rate over the run.
+func MaxScoreAt(elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	secs := elapsed.Seconds()
+	rampEnd := (MaxSpeedMultiplier - 1.0) / SpeedRampPerSecond
+	var score float64
+	if secs <= rampEnd {
+		score = BasePointsPerSecond * (secs + SpeedRampPerSecond*secs*secs/2)
+	} else {
+		score = BasePointsPerSecond * (rampEnd + SpeedRampPerSecond*rampEnd*rampEnd/2)
+		score += BasePointsPerSecond * MaxSpeedMultiplier * (secs - rampEnd)
+	}
+	return int(score)
+}
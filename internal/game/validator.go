@@ -0,0 +1,163 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// scoreTolerance absorbs clock skew and network latency between the client
+// reporting a score and the server validating it.
+const scoreTolerance = 1500 * time.Millisecond
+
+// Match is the server-side record of one running match.
+type Match struct {
+	RoomID    string
+	Timeline  *Timeline
+	PowerUps  []PowerUp
+	StartedAt time.Time
+	// collected tracks which power-ups each player already picked up.
+	collected map[string]map[int]bool
+	// scoreBonus is the extra score headroom each player earned from
+	// double-score power-ups or match handicaps, on top of what MaxScoreAt
+	// allows.
+	scoreBonus map[string]int
+	// scoreScale multiplies the achievable-score ceiling for private
+	// matches played at a custom speed; 1 for standard matches.
+	scoreScale float64
+}
+
+// Validator tracks active matches and checks reported scores against the
+// obstacle timeline derived from each match's seed.
+type Validator struct {
+	mu      sync.RWMutex
+	matches map[string]*Match
+}
+
+// NewValidator returns an empty validator.
+func NewValidator() *Validator {
+	return &Validator{matches: make(map[string]*Match)}
+}
+
+// RegisterMatch starts tracking a room. Called when the matchmaker creates
+// the room, just before GAME_START is sent.
+func (v *Validator) RegisterMatch(roomID string, seed int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.matches[roomID] = &Match{
+		RoomID:     roomID,
+		Timeline:   NewTimeline(seed),
+		PowerUps:   PowerUpSchedule(seed),
+		StartedAt:  time.Now(),
+		collected:  make(map[string]map[int]bool),
+		scoreBonus: make(map[string]int),
+		scoreScale: 1,
+	}
+}
+
+// ApplyMatchSettings adjusts score validation for a private match: scale
+// multiplies the achievable-score ceiling to follow a custom game speed, and
+// handicaps grant per-player head-start headroom. Call it right after
+// RegisterMatch; zero values leave the defaults untouched.
+func (v *Validator) ApplyMatchSettings(roomID string, scale float64, handicaps map[string]int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	m, ok := v.matches[roomID]
+	if !ok {
+		return
+	}
+	if scale > 0 {
+		m.scoreScale = scale
+	}
+	for playerID, bonus := range handicaps {
+		m.scoreBonus[playerID] += bonus
+	}
+}
+
+// MatchSeed reports the seed of a room's currently registered match. A new
+// round re-registers with a fresh seed, so callers can tell the round they
+// started with is over.
+func (v *Validator) MatchSeed(roomID string) (int64, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	m, ok := v.matches[roomID]
+	if !ok {
+		return 0, false
+	}
+	return m.Timeline.Seed, true
+}
+
+// UnregisterMatch stops tracking a room once the match is resolved.
+func (v *Validator) UnregisterMatch(roomID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.matches, roomID)
+}
+
+// ValidateScore reports whether score is achievable for the given player in
+// roomID given the time elapsed since the match started and any double-score
+// power-ups the player collected. Unknown rooms fail validation: a score for
+// a room the server never created is never legitimate.
+func (v *Validator) ValidateScore(roomID, playerID string, score int) error {
+	v.mu.RLock()
+	m, ok := v.matches[roomID]
+	var bonus int
+	scale := 1.0
+	if ok {
+		bonus = m.scoreBonus[playerID]
+		scale = m.scoreScale
+	}
+	v.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no active match for room %s", roomID)
+	}
+	elapsed := time.Since(m.StartedAt) + scoreTolerance
+	if max := int(float64(MaxScoreAt(elapsed))*scale) + bonus; score > max {
+		return fmt.Errorf("score %d exceeds maximum achievable %d after %s", score, max, elapsed.Truncate(time.Millisecond))
+	}
+	return nil
+}
+
+// CollectPowerUp validates a player's claim to have picked up a power-up:
+// the spawn must exist in the room's schedule, be on screen (spawned but not
+// past its collect window), and not have been collected by that player
+// before. On success it records the pickup, credits double-score headroom,
+// and returns the power-up's kind.
+func (v *Validator) CollectPowerUp(roomID, playerID string, powerUpID int) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	m, ok := v.matches[roomID]
+	if !ok {
+		return "", fmt.Errorf("no active match for room %s", roomID)
+	}
+	var pu *PowerUp
+	for i := range m.PowerUps {
+		if m.PowerUps[i].ID == powerUpID {
+			pu = &m.PowerUps[i]
+			break
+		}
+	}
+	if pu == nil {
+		return "", fmt.Errorf("no power-up %d in room %s", powerUpID, roomID)
+	}
+	elapsed := time.Since(m.StartedAt)
+	if elapsed+scoreTolerance < pu.At {
+		return "", fmt.Errorf("power-up %d has not spawned yet", powerUpID)
+	}
+	if elapsed-scoreTolerance > pu.At+PowerUpCollectWindow {
+		return "", fmt.Errorf("power-up %d is past its collect window", powerUpID)
+	}
+	if m.collected[playerID][powerUpID] {
+		return "", fmt.Errorf("power-up %d already collected", powerUpID)
+	}
+	if m.collected[playerID] == nil {
+		m.collected[playerID] = make(map[int]bool)
+	}
+	m.collected[playerID][powerUpID] = true
+	if pu.Kind == PowerUpDoubleScore {
+		// The doubled rate earns at most one extra normal-rate window's worth
+		// of points on top of the usual ceiling.
+		m.scoreBonus[playerID] += int(BasePointsPerSecond * speedAt(pu.At) * PowerUpEffectDuration.Seconds())
+	}
+	return pu.Kind, nil
+}
@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: proto/dino/v1/dino.proto
+
+package dinov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DinoService_Leaderboard_FullMethodName  = "/dino.v1.DinoService/Leaderboard"
+	DinoService_PlayerStats_FullMethodName  = "/dino.v1.DinoService/PlayerStats"
+	DinoService_MatchHistory_FullMethodName = "/dino.v1.DinoService/MatchHistory"
+)
+
+// DinoServiceClient is the client API for DinoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DinoServiceClient interface {
+	// Leaderboard returns one page of the global leaderboard, best first.
+	Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	// PlayerStats returns a player's aggregate match profile.
+	PlayerStats(ctx context.Context, in *PlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStatsResponse, error)
+	// MatchHistory returns a player's recorded runs, newest first.
+	MatchHistory(ctx context.Context, in *MatchHistoryRequest, opts ...grpc.CallOption) (*MatchHistoryResponse, error)
+}
+
+type dinoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDinoServiceClient(cc grpc.ClientConnInterface) DinoServiceClient {
+	return &dinoServiceClient{cc}
+}
+
+func (c *dinoServiceClient) Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, DinoService_Leaderboard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dinoServiceClient) PlayerStats(ctx context.Context, in *PlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStatsResponse, error) {
+	out := new(PlayerStatsResponse)
+	err := c.cc.Invoke(ctx, DinoService_PlayerStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dinoServiceClient) MatchHistory(ctx context.Context, in *MatchHistoryRequest, opts ...grpc.CallOption) (*MatchHistoryResponse, error) {
+	out := new(MatchHistoryResponse)
+	err := c.cc.Invoke(ctx, DinoService_MatchHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DinoServiceServer is the server API for DinoService service.
+// All implementations must embed UnimplementedDinoServiceServer
+// for forward compatibility
+type DinoServiceServer interface {
+	// Leaderboard returns one page of the global leaderboard, best first.
+	Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	// PlayerStats returns a player's aggregate match profile.
+	PlayerStats(context.Context, *PlayerStatsRequest) (*PlayerStatsResponse, error)
+	// MatchHistory returns a player's recorded runs, newest first.
+	MatchHistory(context.Context, *MatchHistoryRequest) (*MatchHistoryResponse, error)
+	mustEmbedUnimplementedDinoServiceServer()
+}
+
+// UnimplementedDinoServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDinoServiceServer struct {
+}
+
+func (UnimplementedDinoServiceServer) Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leaderboard not implemented")
+}
+func (UnimplementedDinoServiceServer) PlayerStats(context.Context, *PlayerStatsRequest) (*PlayerStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlayerStats not implemented")
+}
+func (UnimplementedDinoServiceServer) MatchHistory(context.Context, *MatchHistoryRequest) (*MatchHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchHistory not implemented")
+}
+func (UnimplementedDinoServiceServer) mustEmbedUnimplementedDinoServiceServer() {}
+
+// UnsafeDinoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DinoServiceServer will
+// result in compilation errors.
+type UnsafeDinoServiceServer interface {
+	mustEmbedUnimplementedDinoServiceServer()
+}
+
+func RegisterDinoServiceServer(s grpc.ServiceRegistrar, srv DinoServiceServer) {
+	s.RegisterService(&DinoService_ServiceDesc, srv)
+}
+
+func _DinoService_Leaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DinoServiceServer).Leaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DinoService_Leaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DinoServiceServer).Leaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DinoService_PlayerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DinoServiceServer).PlayerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DinoService_PlayerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DinoServiceServer).PlayerStats(ctx, req.(*PlayerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DinoService_MatchHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DinoServiceServer).MatchHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DinoService_MatchHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DinoServiceServer).MatchHistory(ctx, req.(*MatchHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DinoService_ServiceDesc is the grpc.ServiceDesc for DinoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DinoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dino.v1.DinoService",
+	HandlerType: (*DinoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Leaderboard",
+			Handler:    _DinoService_Leaderboard_Handler,
+		},
+		{
+			MethodName: "PlayerStats",
+			Handler:    _DinoService_PlayerStats_Handler,
+		},
+		{
+			MethodName: "MatchHistory",
+			Handler:    _DinoService_MatchHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/dino/v1/dino.proto",
+}
@@ -0,0 +1,146 @@
+// Package grpcapi exposes the leaderboard and player data over gRPC for
+// service-to-service consumers, sharing the db layer with the REST API.
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/grpcapi/dinov1"
+)
+
+// Page size bounds, matching the REST API.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Server implements dino.v1.DinoService on top of the shared Postgres pool.
+type Server struct {
+	dinov1.UnimplementedDinoServiceServer
+	pool *pgxpool.Pool
+}
+
+// New returns a gRPC server with the DinoService registered.
+func New(pool *pgxpool.Pool) *grpc.Server {
+	s := grpc.NewServer()
+	dinov1.RegisterDinoServiceServer(s, &Server{pool: pool})
+	return s
+}
+
+// clampLimit applies the shared page size defaults and cap.
+func clampLimit(limit int32) int {
+	if limit < 1 || limit > maxPageSize {
+		return defaultPageSize
+	}
+	return int(limit)
+}
+
+// Leaderboard serves one page of the global all-time board, best first.
+func (s *Server) Leaderboard(ctx context.Context, req *dinov1.LeaderboardRequest) (*dinov1.LeaderboardResponse, error) {
+	page := int(req.GetPage())
+	if page < 1 {
+		page = 1
+	}
+	limit := clampLimit(req.GetLimit())
+	offset := (page - 1) * limit
+
+	var total int
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM leaderboard`).Scan(&total); err != nil {
+		slog.Error("grpc leaderboard count failed", "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT player_id, player_name, score, created_at
+		FROM leaderboard
+		ORDER BY score DESC, id ASC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		slog.Error("grpc leaderboard query failed", "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer rows.Close()
+
+	resp := &dinov1.LeaderboardResponse{Total: int32(total), Page: int32(page), Limit: int32(limit)}
+	rank := offset
+	for rows.Next() {
+		var row db.ScoreRow
+		if err := rows.Scan(&row.PlayerID, &row.PlayerName, &row.Score, &row.CreatedAt); err != nil {
+			slog.Error("grpc leaderboard scan failed", "error", err)
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+		rank++
+		resp.Entries = append(resp.Entries, &dinov1.LeaderboardEntry{
+			Rank:       int32(rank),
+			PlayerId:   row.PlayerID,
+			PlayerName: row.PlayerName,
+			Score:      int32(row.Score),
+			CreatedAt:  timestamppb.New(row.CreatedAt),
+		})
+	}
+	return resp, rows.Err()
+}
+
+// PlayerStats serves a player's aggregate profile; unknown players get a
+// zero-valued one, matching the REST endpoint.
+func (s *Server) PlayerStats(ctx context.Context, req *dinov1.PlayerStatsRequest) (*dinov1.PlayerStatsResponse, error) {
+	if req.GetPlayerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "player_id is required")
+	}
+	stats, err := db.GetStats(ctx, s.pool, req.GetPlayerId())
+	if err != nil {
+		slog.Error("grpc player stats failed", "player_id", req.GetPlayerId(), "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &dinov1.PlayerStatsResponse{
+		PlayerId:   stats.PlayerID,
+		PlayerName: stats.PlayerName,
+		Games:      int32(stats.Games),
+		Wins:       int32(stats.Wins),
+		Losses:     int32(stats.Losses),
+		Draws:      int32(stats.Draws),
+		BestScore:  int32(stats.BestScore),
+		AvgScore:   stats.AvgScore,
+		BotGames:   int32(stats.BotGames),
+	}, nil
+}
+
+// MatchHistory serves a player's recorded runs, newest first.
+func (s *Server) MatchHistory(ctx context.Context, req *dinov1.MatchHistoryRequest) (*dinov1.MatchHistoryResponse, error) {
+	if req.GetPlayerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "player_id is required")
+	}
+	limit := clampLimit(req.GetLimit())
+	rows, err := s.pool.Query(ctx, `
+		SELECT score, created_at
+		FROM leaderboard
+		WHERE player_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`, req.GetPlayerId(), limit)
+	if err != nil {
+		slog.Error("grpc match history failed", "player_id", req.GetPlayerId(), "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	defer rows.Close()
+
+	resp := &dinov1.MatchHistoryResponse{}
+	for rows.Next() {
+		var row db.ScoreRow
+		if err := rows.Scan(&row.Score, &row.CreatedAt); err != nil {
+			slog.Error("grpc match history scan failed", "error", err)
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+		resp.Runs = append(resp.Runs, &dinov1.Run{
+			Score:     int32(row.Score),
+			CreatedAt: timestamppb.New(row.CreatedAt),
+		})
+	}
+	return resp, rows.Err()
+}
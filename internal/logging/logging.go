@@ -0,0 +1,35 @@
+// Package logging configures the process-wide slog logger.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Setup installs the default logger. format "json" switches to JSON output
+// for production; level picks debug, info, warn, or error (default info).
+func Setup(level, format string) {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+func parseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,62 @@
+// Package tracing wires OpenTelemetry into the server. Spans are exported
+// over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT is set; without it the
+// global no-op provider stays installed and Start costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName identifies this process in exported traces.
+const serviceName = "dino-server"
+
+// tracer is the shared tracer every instrumented package goes through.
+var tracer = otel.Tracer("github.com/ghazlabs/challenge-entry-level-1")
+
+// Setup installs the OTLP trace pipeline if an endpoint is configured and
+// returns a shutdown function that flushes buffered spans. With no endpoint
+// it is a no-op and returns a no-op shutdown.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: build resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	slog.Info("tracing enabled", "endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// Start opens a span. It resolves the tracer through the global provider, so
+// spans recorded before Setup (or without it) are no-ops.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, opts...)
+}
@@ -0,0 +1,123 @@
+// Package webhook delivers signed match-result notifications to external
+// services such as Discord bots or tournament sites. Delivery is best-effort
+// from a background worker so a slow receiver never blocks the game server.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Delivery tuning.
+const (
+	// queueSize bounds how many undelivered events may back up before new
+	// ones are dropped.
+	queueSize = 256
+	// maxAttempts and retryBase govern per-URL retries: attempt n waits
+	// retryBase << (n-1) before trying again.
+	maxAttempts = 3
+	retryBase   = time.Second
+	// requestTimeout caps one delivery attempt end to end.
+	requestTimeout = 10 * time.Second
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed
+// with the shared webhook secret. See docs/webhooks.md for verification.
+const SignatureHeader = "X-Dino-Signature"
+
+// Notifier posts JSON events to a fixed list of URLs.
+type Notifier struct {
+	urls   []string
+	secret []byte
+	client *http.Client
+	queue  chan []byte
+}
+
+// NewNotifier starts a notifier delivering to the given URLs, signing every
+// body with the given secret.
+func NewNotifier(urls []string, secret string) *Notifier {
+	n := &Notifier{
+		urls:   urls,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: requestTimeout},
+		queue:  make(chan []byte, queueSize),
+	}
+	go n.run()
+	return n
+}
+
+// Publish queues one event for delivery to every configured URL. It never
+// blocks; if the queue is full the event is dropped with a log line.
+func (n *Notifier) Publish(event interface{}) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("webhook encode failed", "error", err)
+		return
+	}
+	select {
+	case n.queue <- body:
+	default:
+		slog.Warn("webhook queue full, dropping event")
+	}
+}
+
+// run drains the queue, delivering each event to every URL in turn.
+func (n *Notifier) run() {
+	for body := range n.queue {
+		for _, url := range n.urls {
+			n.deliver(url, body)
+		}
+	}
+}
+
+// deliver posts one event to one URL, retrying transient failures with
+// exponential backoff before giving up.
+func (n *Notifier) deliver(url string, body []byte) {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := n.post(url, body)
+		if err == nil {
+			return
+		}
+		slog.Warn("webhook delivery failed", "url", url, "attempt", attempt, "error", err)
+		if attempt < maxAttempts {
+			time.Sleep(retryBase << (attempt - 1))
+		}
+	}
+	slog.Error("webhook delivery abandoned", "url", url, "attempts", maxAttempts)
+}
+
+// post makes a single signed delivery attempt.
+func (n *Notifier) post(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body under secret. Receivers recompute
+// it over the raw request body and compare with the SignatureHeader value.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
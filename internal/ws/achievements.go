@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+)
+
+// loadSkin resolves the client's cosmetic skin from their unlocked
+// achievements. Failures just leave the default dino; cosmetics are never
+// worth refusing a match over.
+func (h *Handler) loadSkin(ctx context.Context, c *Client) {
+	unlocked, err := h.Store.ListAchievements(ctx, c.ID)
+	if err != nil {
+		slog.Error("load achievements failed", "client_id", c.ID, "error", err)
+		return
+	}
+	c.Skin = db.SkinFor(unlocked)
+}
+
+// awardAchievements checks a just-recorded result against the milestone
+// thresholds and unlocks anything newly earned. Bot matches don't count:
+// milestones are for beating people.
+func (h *Handler) awardAchievements(ctx context.Context, c *Client, outcome string, vsBot bool) {
+	if c.IsBot || vsBot {
+		return
+	}
+	if c.currentScore() >= db.AchievementScoreTarget {
+		h.unlockAchievement(ctx, c, db.AchievementScore1K)
+	}
+	if outcome != db.OutcomeWin {
+		return
+	}
+	h.unlockAchievement(ctx, c, db.AchievementFirstWin)
+	streak, err := h.Store.WinStreak(ctx, c.ID)
+	if err != nil {
+		slog.Error("load win streak failed", "client_id", c.ID, "error", err)
+		return
+	}
+	if streak >= db.AchievementStreakTarget {
+		h.unlockAchievement(ctx, c, db.AchievementStreak10)
+	}
+}
+
+// unlockAchievement records one milestone, logging rather than failing the
+// match teardown on error.
+func (h *Handler) unlockAchievement(ctx context.Context, c *Client, name string) {
+	if err := h.Store.UnlockAchievement(ctx, c.ID, name); err != nil {
+		slog.Error("unlock achievement failed", "client_id", c.ID, "achievement", name, "error", err)
+	}
+}
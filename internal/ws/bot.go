@@ -0,0 +1,129 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Bot fallback tuning. When nobody compatible is online, a queued duel
+// player is matched against a server-controlled bot instead of waiting
+// forever.
+var (
+	// BotFallbackAfter is how long a player queues before a bot steps in.
+	// Zero disables the fallback.
+	BotFallbackAfter = 20 * time.Second
+	// botScoreInterval is how often the bot reports a score update.
+	botScoreInterval = 500 * time.Millisecond
+)
+
+// Bot difficulty tiers selectable in practice mode. The queue fallback
+// always uses BotMedium.
+const (
+	BotEasy   = "easy"
+	BotMedium = "medium"
+	BotHard   = "hard"
+)
+
+// botTier shapes one difficulty level: what fraction of the legitimate
+// maximum pace the bot scores at, and how long it survives.
+type botTier struct {
+	minSkill, maxSkill float64
+	minLife, maxLife   int // survival window bounds in seconds
+}
+
+var botTiers = map[string]botTier{
+	BotEasy:   {minSkill: 0.40, maxSkill: 0.60, minLife: 8, maxLife: 20},
+	BotMedium: {minSkill: 0.70, maxSkill: 0.95, minLife: 15, maxLife: 50},
+	BotHard:   {minSkill: 0.90, maxSkill: 1.00, minLife: 45, maxLife: 90},
+}
+
+// botNames gives bots a recognizable but friendly identity.
+var botNames = []string{"RoboRex", "ServoSaur", "TinnyTops", "ClankyClaws", "ByteBron"}
+
+// newBotClient builds the in-process client a bot plays through. It has no
+// connection; its outbound frames are consumed by the bot driver instead of
+// a WritePump. Unknown tiers fall back to medium.
+func newBotClient(rating int, tier string) *Client {
+	if _, ok := botTiers[tier]; !ok {
+		tier = BotMedium
+	}
+	bot := NewClient("bot_"+randomHex(6), nil, jsonCodec{})
+	bot.Name = botNames[rand.Intn(len(botNames))]
+	bot.IsBot = true
+	bot.botTier = tier
+	// Close to the human's rating so the match reads as fair.
+	bot.Rating = rating + rand.Intn(101) - 50
+	return bot
+}
+
+// runBot drives a bot through the normal client protocol by decoding the
+// frames queued on its send channel: it readies up, plays each round, and
+// tears itself down when the match ends. Run it in its own goroutine.
+func (h *Handler) runBot(bot *Client) {
+	playing := false
+	for frame := range bot.Send {
+		msg, err := bot.codec.Decode(frame)
+		if err != nil {
+			continue
+		}
+		switch msg.Type {
+		case MsgGameStart:
+			h.Matchmaker.MarkReady(bot)
+		case MsgRoundOver:
+			playing = false
+			h.Matchmaker.MarkReady(bot)
+		case MsgCountdown:
+			if playing {
+				continue
+			}
+			playing = true
+			var p CountdownPayload
+			if err := json.Unmarshal(msg.Payload, &p); err != nil {
+				continue
+			}
+			go h.botPlay(bot, time.UnixMilli(p.StartAt))
+		case MsgGameOver, MsgMatchCancelled:
+			h.Hub.Unregister(bot)
+			bot.closeSend()
+			slog.Info("bot retired", "client_id", bot.ID)
+			return
+		}
+	}
+}
+
+// botPlay runs one round for a bot: its score follows the legitimate
+// maximum pace scaled by a random skill factor, and it dies after a random
+// survival window like a fallible human would. Both ranges come from the
+// bot's difficulty tier.
+func (h *Handler) botPlay(bot *Client, startAt time.Time) {
+	tier, ok := botTiers[bot.botTier]
+	if !ok {
+		tier = botTiers[BotMedium]
+	}
+	skill := tier.minSkill + rand.Float64()*(tier.maxSkill-tier.minSkill)
+	lifespan := time.Duration(tier.minLife+rand.Intn(tier.maxLife-tier.minLife)) * time.Second
+
+	time.Sleep(time.Until(startAt))
+	score := 0
+	for elapsed := time.Duration(0); elapsed < lifespan; elapsed += botScoreInterval {
+		time.Sleep(botScoreInterval)
+		if !bot.alive() || bot.RoomID == "" {
+			return
+		}
+		score = int(float64(game.MaxScoreAt(elapsed+botScoreInterval)) * skill)
+		bot.adoptScore(score)
+		h.notifyOpponent(bot, MsgOpponentUpdate, OpponentUpdatePayload{PlayerID: bot.ID, Score: score})
+	}
+
+	if !bot.alive() || bot.RoomID == "" {
+		return
+	}
+	bot.markDead()
+	h.notifyOpponent(bot, MsgOpponentDied, OpponentUpdatePayload{PlayerID: bot.ID, Score: score})
+	h.maybeFinishMatch(context.Background(), bot.RoomID)
+}
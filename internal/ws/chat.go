@@ -0,0 +1,50 @@
+package ws
+
+import (
+	"strings"
+	"time"
+)
+
+// chatCooldown is the minimum interval between chat/emote messages from one
+// client.
+const chatCooldown = time.Second
+
+// maxChatLength bounds a single chat message.
+const maxChatLength = 120
+
+// emotes the frontend knows how to render.
+var allowedEmotes = map[string]bool{
+	"wave":  true,
+	"laugh": true,
+	"cry":   true,
+	"gg":    true,
+	"angry": true,
+	"heart": true,
+}
+
+// profanity is a deliberately small starter list; masked words keep chat
+// family-friendly without blocking the message entirely.
+var profanity = []string{
+	"anjing", "bangsat", "kontol", "memek", "goblok", "tolol",
+	"fuck", "shit", "bitch", "asshole", "cunt",
+}
+
+// sanitizeChat trims, truncates, and masks profanity in a chat message. An
+// empty result means the message should be dropped.
+func sanitizeChat(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) > maxChatLength {
+		text = text[:maxChatLength]
+	}
+	words := strings.Fields(text)
+	for i, w := range words {
+		stripped := strings.ToLower(strings.Trim(w, ".,!?;:\"'"))
+		for _, bad := range profanity {
+			if stripped == bad {
+				words[i] = strings.Repeat("*", len(w))
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
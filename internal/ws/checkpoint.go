@@ -0,0 +1,197 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Checkpoint tuning.
+const (
+	// checkpointInterval is how often live rooms are snapshotted to Redis.
+	checkpointInterval = 10 * time.Second
+	// checkpointTTL bounds how long a checkpoint outlives its last write, so
+	// an instance that dies for good doesn't leak keys. It comfortably covers
+	// a restart plus the players' reconnect attempts.
+	checkpointTTL = 5 * time.Minute
+)
+
+// roomCheckpoint is the restart-survival snapshot of one running match. It is
+// written periodically while the match runs and consumed once by whichever
+// process recovers it after a restart.
+type roomCheckpoint struct {
+	RoomID  string             `json:"roomId"`
+	Players []checkpointPlayer `json:"players"`
+	// Elapsed is how long the match had been running at snapshot time, in
+	// milliseconds.
+	Elapsed int64 `json:"elapsed"`
+}
+
+// checkpointPlayer is one participant inside a checkpoint. Token is the
+// player's reconnect token, which is how a returning connection is matched
+// back to its interrupted room.
+type checkpointPlayer struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+	Alive bool   `json:"alive"`
+	Bot   bool   `json:"bot,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// roomCheckpointKey names the Redis key holding one room's checkpoint.
+func roomCheckpointKey(roomID string) string {
+	return "room:" + roomID + ":checkpoint"
+}
+
+// SaveCheckpoint writes one room's snapshot, replacing the previous one.
+func (b *RoomBus) SaveCheckpoint(cp roomCheckpoint) {
+	if b == nil {
+		return
+	}
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		slog.Error("marshal checkpoint failed", "room_id", cp.RoomID, "error", err)
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := b.rdb.Set(ctx, roomCheckpointKey(cp.RoomID), raw, checkpointTTL).Err(); err != nil {
+		slog.Error("write checkpoint failed", "room_id", cp.RoomID, "error", err)
+	}
+}
+
+// DropCheckpoint removes a room's checkpoint once its match resolved
+// normally, so a later restart doesn't resurrect a finished game.
+func (b *RoomBus) DropCheckpoint(roomID string) {
+	if b == nil || roomID == "" {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := b.rdb.Del(ctx, roomCheckpointKey(roomID)).Err(); err != nil {
+		slog.Error("drop checkpoint failed", "room_id", roomID, "error", err)
+	}
+}
+
+// LoadCheckpoints drains every stored checkpoint: each one is returned once
+// and deleted, so two instances booting together don't both recover the same
+// rooms.
+func (b *RoomBus) LoadCheckpoints(ctx context.Context) []roomCheckpoint {
+	if b == nil {
+		return nil
+	}
+	var cps []roomCheckpoint
+	var cursor uint64
+	for {
+		keys, next, err := b.rdb.Scan(ctx, cursor, "room:*:checkpoint", 100).Result()
+		if err != nil {
+			slog.Error("scan checkpoints failed", "error", err)
+			return cps
+		}
+		for _, key := range keys {
+			raw, err := b.rdb.GetDel(ctx, key).Bytes()
+			if err != nil {
+				if err != redis.Nil {
+					slog.Error("read checkpoint failed", "key", key, "error", err)
+				}
+				continue
+			}
+			var cp roomCheckpoint
+			if err := json.Unmarshal(raw, &cp); err != nil {
+				slog.Warn("bad checkpoint dropped", "key", key, "error", err)
+				continue
+			}
+			cps = append(cps, cp)
+		}
+		if cursor = next; cursor == 0 {
+			return cps
+		}
+	}
+}
+
+// CheckpointRooms snapshots every running match. Practice rooms are skipped:
+// they hold nothing worth persisting across a restart.
+func (m *Matchmaker) CheckpointRooms() []roomCheckpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cps []roomCheckpoint
+	for roomID, meta := range m.matches {
+		if meta.practice || meta.startedAt.IsZero() {
+			continue
+		}
+		cp := roomCheckpoint{RoomID: roomID, Elapsed: time.Since(meta.startedAt).Milliseconds()}
+		for _, c := range meta.players {
+			cp.Players = append(cp.Players, checkpointPlayer{
+				ID: c.ID, Name: c.Name, Score: c.currentScore(), Alive: c.alive(),
+				Bot: c.IsBot, Token: c.SessionToken,
+			})
+		}
+		cps = append(cps, cp)
+	}
+	return cps
+}
+
+// checkpointRooms periodically mirrors running matches into Redis so a
+// restart loses at most one interval's worth of score progress.
+func (h *Handler) checkpointRooms() {
+	for range time.Tick(checkpointInterval) {
+		if h.bus == nil {
+			continue
+		}
+		for _, cp := range h.Matchmaker.CheckpointRooms() {
+			h.bus.SaveCheckpoint(cp)
+		}
+	}
+}
+
+// RecoverCheckpoints consumes the checkpoints a previous process left behind:
+// every human's last known score is persisted, and their reconnect token is
+// remembered so their next connection gets a GAME_OVER explaining the
+// restart instead of a silent dead session. Call it once at startup, after
+// the room bus is wired.
+func (h *Handler) RecoverCheckpoints(ctx context.Context) {
+	if h.bus == nil {
+		return
+	}
+	for _, cp := range h.bus.LoadCheckpoints(ctx) {
+		scores := make(map[string]int, len(cp.Players))
+		for _, p := range cp.Players {
+			scores[p.ID] = p.Score
+		}
+		result := GameOverPayload{Scores: scores, Reason: GameOverReasonServerRestart}
+		for _, p := range cp.Players {
+			if p.Bot {
+				continue
+			}
+			if p.Score > 0 {
+				// Checkpoints don't carry connection metadata, so restored
+				// scores have no country.
+				if err := h.Store.SaveScore(ctx, p.ID, p.Name, p.Score, "", cp.RoomID); err != nil {
+					slog.Error("persist checkpointed score failed", "player_id", p.ID, "room_id", cp.RoomID, "error", err)
+				}
+			}
+			if p.Token != "" {
+				h.restartMu.Lock()
+				h.restartResults[p.Token] = result
+				h.restartMu.Unlock()
+			}
+		}
+		slog.Info("recovered interrupted match", "room_id", cp.RoomID, "players", len(cp.Players), "elapsed_ms", cp.Elapsed)
+	}
+}
+
+// takeRestartResult hands out (once) the GAME_OVER owed to a reconnect token
+// whose match was cut short by a restart.
+func (h *Handler) takeRestartResult(token string) (GameOverPayload, bool) {
+	h.restartMu.Lock()
+	defer h.restartMu.Unlock()
+	result, ok := h.restartResults[token]
+	if ok {
+		delete(h.restartResults, token)
+	}
+	return result, ok
+}
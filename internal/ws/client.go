@@ -0,0 +1,416 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// sendBufferSize is how many outbound messages may queue up before the
+// server starts dropping frames for a slow client.
+var sendBufferSize = 32
+
+// maxSendDrops is how many consecutive frames may be dropped on a full send
+// buffer before the client is considered unable to keep up and is
+// force-closed. Any successfully queued frame resets the count.
+var maxSendDrops = 32
+
+// Keepalive tuning. Connections that miss a pong for PongWait are considered
+// dead and reaped, so crashed clients release their rooms.
+var (
+	// WriteWait is the deadline for a single outbound write.
+	WriteWait = 10 * time.Second
+	// PongWait is how long to wait for a pong before dropping the peer.
+	PongWait = 60 * time.Second
+	// PingPeriod is how often pings go out; must be below PongWait.
+	PingPeriod = 54 * time.Second
+	// MaxMessageSize bounds inbound frames; game messages are tiny.
+	MaxMessageSize int64 = 4096
+)
+
+// Client represents one connected player.
+type Client struct {
+	ID   string
+	Name string
+
+	Conn *websocket.Conn
+	Send chan []byte
+	// codec is the negotiated wire format, JSON unless the client opted
+	// into msgpack at handshake time.
+	codec Codec
+	// Proto is the wire protocol version negotiated via subprotocols;
+	// zero means v1 (no negotiation on the handshake).
+	Proto int
+
+	mu sync.Mutex
+	// ctx is this connection's context, cancelled when its read loop exits
+	// so a dead client's in-flight DB and Redis work is abandoned. Nil (and
+	// treated as background) for bots and parked sessions.
+	ctx     context.Context
+	RoomID  string
+	Score   int
+	IsAlive bool
+	InQueue bool
+	// Rating is the player's Elo rating, loaded when they join the queue.
+	Rating int
+	// Skin is the cosmetic dino skin earned through achievements, loaded
+	// alongside the rating; empty renders the default dino.
+	Skin string
+	// Muted is set when this client asked not to receive opponent chat.
+	Muted bool
+	// IsBot marks server-controlled fallback opponents; they have no
+	// connection and their frames are consumed by the bot driver.
+	IsBot bool
+	// botTier is the bot's difficulty level; empty plays as BotMedium.
+	botTier string
+	// Team is the player's side in team mode ("A" or "B"), else empty.
+	Team string
+	// Spectating is the room this client is watching as a spectator, empty
+	// for players. Maintained by the hub's spectator index.
+	Spectating string
+	// lastChat is when this client last sent a chat or emote, for rate
+	// limiting.
+	lastChat time.Time
+	// lastSeq is the highest client-stamped sequence number processed so
+	// far; it survives reconnects so resent messages stay deduplicated.
+	lastSeq int64
+	// boostUntil is how long a collected double-score power-up keeps the
+	// relaxed anti-cheat delta in effect.
+	boostUntil time.Time
+	// lastPos and posPending rate-limit and coalesce ghost position relays.
+	lastPos    time.Time
+	posPending *PositionUpdatePayload
+	// drops counts consecutive frames dropped on a full send buffer; at
+	// maxSendDrops the connection is force-closed.
+	drops int
+	// sendClosed marks the send channel as closed. SendJSON checks it under
+	// the lock, so teardown never closes the channel out from under a
+	// concurrent sender.
+	sendClosed bool
+	// pingSent is when the last keepalive ping went out; latencyMs is the
+	// smoothed round-trip time measured from the matching pongs.
+	pingSent  time.Time
+	latencyMs int64
+	// scoredAt is when the current score was reached and diedAt when the
+	// run ended; the configurable tie-breakers compare them when a match's
+	// top score is shared.
+	scoredAt time.Time
+	diedAt   time.Time
+	// lastActive is when the last inbound frame arrived, also stamped at
+	// match start; the idle watchdog compares it against idleForfeitAfter.
+	lastActive time.Time
+
+	// SessionToken is the reconnect token issued for the current match,
+	// empty outside of one.
+	SessionToken string
+
+	// Connection metadata captured at upgrade time, refreshed on every
+	// reconnect. IP is the remote address (the X-Forwarded-For client when
+	// proxy headers are trusted), UserAgent the handshake's User-Agent, and
+	// Country the proxy-supplied GeoIP country code or empty. Used for
+	// abuse tracing and regional stats; none of it reaches other players.
+	IP        string
+	UserAgent string
+	Country   string
+}
+
+// NewClient wraps a freshly upgraded connection.
+func NewClient(id string, conn *websocket.Conn, codec Codec) *Client {
+	return &Client{
+		ID:      id,
+		Conn:    conn,
+		Send:    make(chan []byte, sendBufferSize),
+		codec:   codec,
+		IsAlive: true,
+	}
+}
+
+// bindContext attaches the current connection's context; ServeWs rebinds on
+// every (re)connect.
+func (c *Client) bindContext(ctx context.Context) {
+	c.mu.Lock()
+	c.ctx = ctx
+	c.mu.Unlock()
+}
+
+// Context returns the current connection's context, or the background
+// context for clients without one (bots, parked sessions).
+func (c *Client) Context() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Close tears down the underlying connection, which unblocks the read loop
+// and triggers the normal disconnect path.
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Conn != nil {
+		c.Conn.Close()
+	}
+}
+
+// CloseWith sends a close frame with the given code and reason before
+// tearing the connection down, so the client can show why it was dropped and
+// decide whether to reconnect. WriteControl is safe to call concurrently
+// with the write pump, so no coordination with in-flight frames is needed.
+// No-op for clients without a connection (bots, parked sessions).
+func (c *Client) CloseWith(code int, reason string) {
+	c.mu.Lock()
+	conn := c.Conn
+	c.mu.Unlock()
+	if conn == nil {
+		return
+	}
+	deadline := time.Now().Add(WriteWait)
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+	conn.Close()
+}
+
+// Attach swaps in a new connection after a reconnect. The old send channel
+// is closed so the previous WritePump exits; callers must start a new one.
+func (c *Client) Attach(conn *websocket.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.sendClosed {
+		close(c.Send)
+	}
+	c.Conn = conn
+	c.Send = make(chan []byte, sendBufferSize)
+	c.sendClosed = false
+}
+
+// closeSend closes the send channel so the write pump exits, flagging it
+// dead under the client lock first. Concurrent SendJSON calls — a room
+// broadcast that snapshotted this client just before it unregistered — see
+// the flag and drop the frame instead of panicking on a closed channel.
+func (c *Client) closeSend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendClosed {
+		return
+	}
+	c.sendClosed = true
+	close(c.Send)
+}
+
+// SendJSON marshals a message of the given type and queues it for delivery.
+// Marshalling happens before the client lock is taken, so a slow encode on
+// one message never serializes the broadcast path; the lock only covers the
+// non-blocking enqueue, where it guards against Attach swapping the channel.
+// Frames are dropped if the client's send buffer is full so one slow reader
+// cannot block the rest of the server. A client that keeps dropping frames is
+// seeing a different game than its opponent by now, so after maxSendDrops in
+// a row the connection is force-closed; the normal disconnect path then
+// notifies the opponent and parks the session.
+func (c *Client) SendJSON(msgType string, payload interface{}) {
+	frame, err := c.codec.Encode(msgType, payload)
+	if err != nil {
+		slog.Error("encode message failed", "client_id", c.ID, "msg_type", msgType, "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendClosed {
+		return
+	}
+	select {
+	case c.Send <- frame:
+		c.drops = 0
+	default:
+		c.drops++
+		slog.Warn("send buffer full, dropping message", "client_id", c.ID, "msg_type", msgType, "consecutive_drops", c.drops)
+		if c.drops == maxSendDrops && c.Conn != nil {
+			slog.Warn("disconnecting client that cannot keep up", "client_id", c.ID, "dropped", c.drops)
+			c.Conn.Close()
+		}
+	}
+}
+
+// Match-state accessors. Score, IsAlive and the tie-breaker timestamps are
+// driven by the connection's read loop during play, but the timer-driven
+// finish paths — the timed-match clock, the idle watchdog, the stale-room
+// reaper — and the bot driver read and write them from their own goroutines,
+// so every access that can cross goroutines goes through c.mu.
+
+// currentScore reads the score under the client lock.
+func (c *Client) currentScore() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Score
+}
+
+// adoptScore records an accepted score update, stamping scoredAt whenever the
+// score moves up for the first-to-score tie-breaker.
+func (c *Client) adoptScore(score int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if score > c.Score {
+		c.scoredAt = time.Now()
+	}
+	c.Score = score
+}
+
+// alive reports whether the client's run is still going.
+func (c *Client) alive() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.IsAlive
+}
+
+// markDead ends the client's run, stamping diedAt for the survival
+// tie-breaker. Calling it on an already-dead client changes nothing, so a
+// timer-driven finish never moves a recorded death.
+func (c *Client) markDead() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.IsAlive {
+		return
+	}
+	c.IsAlive = false
+	c.diedAt = time.Now()
+}
+
+// resetRun rearms the client for a fresh match or round, starting from the
+// given score (a handicap head start, usually zero).
+func (c *Client) resetRun(score int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Score = score
+	c.IsAlive = true
+	c.scoredAt = time.Time{}
+	c.diedAt = time.Time{}
+}
+
+// resetMatchState clears the per-match fields once the client's room is
+// resolved; alive-state stays false until the next match rearms it.
+func (c *Client) resetMatchState() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Score = 0
+	c.boostUntil = time.Time{}
+	c.scoredAt = time.Time{}
+	c.diedAt = time.Time{}
+}
+
+// matchTimes returns the tie-breaker timestamps under the lock.
+func (c *Client) matchTimes() (scoredAt, diedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scoredAt, c.diedAt
+}
+
+// startBoost arms the double-score power-up window.
+func (c *Client) startBoost(d time.Duration) {
+	c.mu.Lock()
+	c.boostUntil = time.Now().Add(d)
+	c.mu.Unlock()
+}
+
+// boosted reports whether a double-score power-up is still in effect.
+func (c *Client) boosted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().Before(c.boostUntil)
+}
+
+// markActive stamps the client as having just sent something; dispatch
+// calls it for every inbound frame.
+func (c *Client) markActive() {
+	c.mu.Lock()
+	c.lastActive = time.Now()
+	c.mu.Unlock()
+}
+
+// idleFor returns how long the client has gone without sending anything,
+// or zero for clients that were never stamped.
+func (c *Client) idleFor() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastActive.IsZero() {
+		return 0
+	}
+	return time.Since(c.lastActive)
+}
+
+// markPing notes when a keepalive ping went out so the matching pong can be
+// timed.
+func (c *Client) markPing() {
+	c.mu.Lock()
+	c.pingSent = time.Now()
+	c.mu.Unlock()
+}
+
+// markPong folds the just-completed ping/pong cycle into the client's
+// measured round-trip time. Smoothed like TCP's SRTT so one delayed pong
+// doesn't whipsaw the reading.
+func (c *Client) markPong() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pingSent.IsZero() {
+		return
+	}
+	rtt := time.Since(c.pingSent).Milliseconds()
+	c.pingSent = time.Time{}
+	if c.latencyMs == 0 {
+		c.latencyMs = rtt
+		return
+	}
+	c.latencyMs = (c.latencyMs*7 + rtt) / 8
+}
+
+// Latency returns the smoothed round-trip time in milliseconds, zero until
+// the first ping/pong cycle completes (and always for bots).
+func (c *Client) Latency() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int(c.latencyMs)
+}
+
+// WritePump drains the send channel onto the wire. It runs in its own
+// goroutine per connection and exits when the channel is closed. The first
+// ping goes out immediately so a latency reading exists before the first
+// match rather than a full PingPeriod later.
+func (c *Client) WritePump() {
+	c.mu.Lock()
+	conn, send := c.Conn, c.Send
+	c.mu.Unlock()
+	ticker := time.NewTicker(PingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+	conn.SetWriteDeadline(time.Now().Add(WriteWait))
+	c.markPing()
+	if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+		return
+	}
+	for {
+		select {
+		case msg, ok := <-send:
+			conn.SetWriteDeadline(time.Now().Add(WriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteMessage(c.codec.FrameType(), msg); err != nil {
+				slog.Debug("write failed", "client_id", c.ID, "error", err)
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(WriteWait))
+			c.markPing()
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestJSONEncodeMatchesNaive pins the hand-assembled envelope to what a
+// plain double-marshal produces.
+func TestJSONEncodeMatchesNaive(t *testing.T) {
+	payload := OpponentUpdatePayload{PlayerID: "p1", Score: 1234}
+	frame, err := jsonCodec{}.Encode(MsgOpponentUpdate, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := json.Marshal(payload)
+	want, _ := json.Marshal(Message{Type: MsgOpponentUpdate, Payload: raw})
+	if string(frame) != string(want) {
+		t.Fatalf("frame = %s, want %s", frame, want)
+	}
+
+	// nil payloads omit the field entirely
+	frame, err = jsonCodec{}.Encode(MsgQueueCancelled, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg Message
+	if err := json.Unmarshal(frame, &msg); err != nil {
+		t.Fatalf("unmarshal %s: %v", frame, err)
+	}
+	if msg.Type != MsgQueueCancelled || msg.Payload != nil {
+		t.Fatalf("decoded %+v, want bare %s envelope", msg, MsgQueueCancelled)
+	}
+}
+
+func TestJSONEncodeRoundTrip(t *testing.T) {
+	frame, err := jsonCodec{}.Encode(MsgGameOver, GameOverPayload{
+		WinnerID: "p2",
+		Scores:   map[string]int{"p1": 10, "p2": 20},
+		Reason:   GameOverReasonFinished,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := jsonCodec{}.Decode(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p GameOverPayload
+	if err := json.Unmarshal(msg.Payload, &p); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"p1": 10, "p2": 20}
+	if p.WinnerID != "p2" || !reflect.DeepEqual(p.Scores, want) {
+		t.Fatalf("round trip payload = %+v", p)
+	}
+}
+
+// BenchmarkSendJSONOpponentUpdate exercises the hot broadcast path: one
+// score update fanned out to a room.
+func BenchmarkSendJSONOpponentUpdate(b *testing.B) {
+	c := NewClient("p1", nil, jsonCodec{})
+	payload := OpponentUpdatePayload{PlayerID: "p2", Score: 4321}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.SendJSON(MsgOpponentUpdate, payload)
+		// Drain so the buffer never fills and the drop path stays cold.
+		<-c.Send
+	}
+}
+
+func BenchmarkJSONEncode(b *testing.B) {
+	payload := OpponentUpdatePayload{PlayerID: "p2", Score: 4321}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := (jsonCodec{}).Encode(MsgOpponentUpdate, payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package ws
+
+import "github.com/ghazlabs/challenge-entry-level-1/internal/config"
+
+// Apply installs the websocket tunables from the loaded configuration onto
+// the package knobs. Call it once at startup before serving connections.
+func Apply(cfg *config.Config) {
+	sendBufferSize = cfg.SendBufferSize
+	MaxMessageSize = cfg.MaxMessageSize
+	WriteWait = cfg.WriteWait
+	PongWait = cfg.PongWait
+	PingPeriod = cfg.PongWait * 9 / 10
+	InboundRate = cfg.InboundRate
+	InboundBurst = cfg.InboundBurst
+	upgrader.EnableCompression = cfg.WSCompression
+	compressionLevel = cfg.WSCompressionLevel
+	maxScoreJump = cfg.MaxScoreJump
+	tieBreaker = cfg.TieBreaker
+	reconnectGrace = cfg.ReconnectGrace
+	BotFallbackAfter = cfg.BotFallbackAfter
+	allowedOrigins = cfg.AllowedOrigins
+	maxConnsPerIP = cfg.MaxConnsPerIP
+	trustProxyHeaders = cfg.TrustProxyHeaders
+	idleForfeitAfter = cfg.IdleForfeitAfter
+	staleRoomAfter = cfg.StaleRoomAfter
+}
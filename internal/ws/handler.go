@@ -0,0 +1,1090 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/events"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/flags"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+)
+
+// maxScoreJump is the largest score increase accepted between two
+// consecutive UPDATE_SCORE messages.
+var maxScoreJump = 50
+
+// maxConnsPerIP caps concurrent connections from one remote IP; 0 disables
+// the cap.
+var maxConnsPerIP = 8
+
+// trustProxyHeaders switches remoteIP and remoteCountry to the fronting
+// proxy's headers; configured via TRUST_PROXY_HEADERS.
+var trustProxyHeaders = false
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     originAllowed,
+	// permessage-deflate is offered to clients that ask for it; the
+	// high-frequency OPPONENT_UPDATE/POSITION traffic compresses well on
+	// slow mobile links. Toggled via WS_COMPRESSION.
+	EnableCompression: true,
+}
+
+// compressionLevel is the flate level applied to compressed connections;
+// BestSpeed by default since frames are small and frequent.
+var compressionLevel = 1
+
+// messageTimeout bounds the handling of one inbound message, including any
+// DB and Redis work it triggers.
+var messageTimeout = 5 * time.Second
+
+// Handler owns the WebSocket endpoint and message dispatch.
+type Handler struct {
+	Hub        *Hub
+	Matchmaker *Matchmaker
+	Validator  *game.Validator
+	Sessions   *SessionStore
+	Loops      *game.LoopManager
+	Store      db.Store
+	// events carries lifecycle events (match created, score updated, player
+	// died, match finished) to subscribers like the leaderboard stream and
+	// webhooks; nil drops every publish.
+	events *events.Bus
+	// bus relays in-room traffic across instances; nil for single-node
+	// deployments.
+	bus *RoomBus
+	// flags holds the runtime feature switches; nil leaves everything
+	// enabled.
+	flags *flags.Store
+
+	// handlers routes message types to their handlers; filled by
+	// registerHandlers and extendable via Register before serving starts.
+	handlers map[string]handlerFunc
+
+	// invites tracks pending direct challenges by invitee ID.
+	invitesMu sync.Mutex
+	invites   map[string]invite
+
+	// restartResults holds, by reconnect token, the GAME_OVER owed to players
+	// whose match a restart cut short; filled by RecoverCheckpoints.
+	restartMu      sync.Mutex
+	restartResults map[string]GameOverPayload
+}
+
+// invite is one pending direct challenge: who sent it and the custom match
+// settings they chose.
+type invite struct {
+	fromID   string
+	settings *MatchSettings
+}
+
+// NewHandler wires up the WebSocket handler and registers it as the host
+// that drives the matchmaker's fallback bots.
+func NewHandler(hub *Hub, mm *Matchmaker, validator *game.Validator, sessions *SessionStore, loops *game.LoopManager, store db.Store) *Handler {
+	h := &Handler{Hub: hub, Matchmaker: mm, Validator: validator, Sessions: sessions, Loops: loops, Store: store,
+		invites: make(map[string]invite), restartResults: make(map[string]GameOverPayload),
+		handlers: make(map[string]handlerFunc)}
+	h.registerHandlers()
+	mm.botHost = h
+	go h.reapStaleRooms()
+	go h.idleLoop()
+	go h.checkpointRooms()
+	go h.latencyLoop()
+	return h
+}
+
+// SetRoomBus enables the Redis-backed cross-instance room relay.
+func (h *Handler) SetRoomBus(bus *RoomBus) {
+	h.bus = bus
+}
+
+// SetEventBus wires the lifecycle event bus the handler publishes to.
+func (h *Handler) SetEventBus(bus *events.Bus) {
+	h.events = bus
+}
+
+// SetFlagStore wires the runtime feature-flag store.
+func (h *Handler) SetFlagStore(store *flags.Store) {
+	h.flags = store
+}
+
+// ServeWs upgrades the connection and runs the client's read loop until the
+// connection drops.
+func (h *Handler) ServeWs(w http.ResponseWriter, r *http.Request) {
+	ip := remoteIP(r)
+	if !h.Hub.AcquireIP(ip, maxConnsPerIP) {
+		slog.Warn("connection limit reached for IP", "ip", ip)
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "too many connections", http.StatusTooManyRequests)
+		return
+	}
+	defer h.Hub.ReleaseIP(ip)
+	if banned, err := h.Store.IsBanned(r.Context(), ip); err != nil {
+		slog.Error("ban check failed", "ip", ip, "error", err)
+	} else if banned {
+		http.Error(w, "banned", http.StatusForbidden)
+		return
+	}
+
+	proto, protoName := negotiateProtocol(r)
+	var respHeader http.Header
+	if protoName != "" {
+		// Echo the negotiated subprotocol so the client's handshake accepts.
+		respHeader = http.Header{"Sec-WebSocket-Protocol": {protoName}}
+	}
+	conn, err := upgrader.Upgrade(w, r, respHeader)
+	if err != nil {
+		slog.Error("websocket upgrade failed", "error", err)
+		return
+	}
+	if upgrader.EnableCompression {
+		// Only takes effect on connections that negotiated the extension.
+		conn.SetCompressionLevel(compressionLevel)
+	}
+	var client *Client
+	var restartResult *GameOverPayload
+	if token := r.URL.Query().Get("token"); token != "" {
+		if resumed, ok := h.Sessions.Resume(token); ok {
+			client = resumed
+			client.Attach(conn)
+			// Resume consumed the presented token, so issue a replacement
+			// for the next drop; it goes out in SESSION_RESUMED below.
+			client.SessionToken = h.Sessions.Issue(client)
+			slog.Info("client resumed session", "client_id", client.ID, "room_id", client.RoomID)
+			h.notifyOpponent(client, MsgOpponentReconnected, PlayerInfo{ID: client.ID, Name: client.Name})
+		} else if result, ok := h.takeRestartResult(token); ok {
+			// The match this token belonged to died with the previous
+			// process; its checkpointed outcome is delivered below.
+			restartResult = &result
+			slog.Info("client returned after server restart")
+		} else {
+			// A dead token means the session is gone; closing with a distinct
+			// code stops the client from retrying the same token forever —
+			// connecting fresh, without it, is the way back in.
+			slog.Warn("reconnect with invalid or expired token")
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(CloseAuthFailed, "invalid or expired session token"),
+				time.Now().Add(WriteWait))
+			conn.Close()
+			return
+		}
+	}
+	if client == nil {
+		client = NewClient("guest_"+randomHex(6), conn, codecFor(r.URL.Query().Get("proto")))
+		h.Hub.Register(client)
+	} else if client.SessionToken != "" {
+		client.SendJSON(MsgSessionResumed, SessionResumedPayload{ReconnectToken: client.SessionToken})
+	}
+	// A resumed session keeps its state but speaks whatever version this
+	// connection negotiated, and its metadata reflects this connection —
+	// a phone falling back from wifi to cellular reconnects from a new IP.
+	client.Proto = proto
+	client.IP = ip
+	client.UserAgent = r.UserAgent()
+	client.Country = remoteCountry(r)
+	// Everything this connection triggers downstream — DB writes, Redis
+	// calls — hangs off connCtx, so a disconnect cancels in-flight work
+	// instead of letting it run for a client that is gone.
+	connCtx, cancelConn := context.WithCancel(context.Background())
+	defer cancelConn()
+	client.bindContext(connCtx)
+	go client.WritePump()
+	slog.Info("client connected", "client_id", client.ID)
+	if restartResult != nil {
+		client.SendJSON(MsgGameOver, *restartResult)
+	}
+
+	defer func() {
+		h.stopSpectating(client)
+		if client.InQueue {
+			h.Matchmaker.Remove(client)
+		}
+		if client.RoomID != "" && client.SessionToken != "" {
+			// Keep the match state alive for the reconnect grace window
+			// instead of tearing it down immediately.
+			h.notifyOpponent(client, MsgOpponentDisconnected, PlayerInfo{ID: client.ID, Name: client.Name})
+			h.Sessions.Park(client.SessionToken, h.expireSession)
+			slog.Info("client disconnected mid-match, session parked", "client_id", client.ID, "room_id", client.RoomID)
+			return
+		}
+		h.Hub.Unregister(client)
+		client.closeSend()
+		slog.Info("client disconnected", "client_id", client.ID)
+	}()
+
+	conn.SetReadLimit(MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(PongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(PongWait))
+		client.markPong()
+		return nil
+	})
+
+	// A panic anywhere in this connection's read path must not take down
+	// every other match on the server; the deferred teardown above still
+	// runs and cleans up this client alone.
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic in connection read loop", "client_id", client.ID, "room_id", client.RoomID, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+
+	bucket := newTokenBucket(InboundRate, InboundBurst)
+	strikes := 0
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.Debug("read failed", "client_id", client.ID, "error", err)
+			}
+			return
+		}
+		if !bucket.allow() {
+			strikes++
+			if strikes >= maxRateStrikes {
+				slog.Warn("disconnecting client for sustained message flooding", "client_id", client.ID, "strikes", strikes)
+				client.CloseWith(CloseRateLimited, "message rate limit exceeded")
+				return
+			}
+			// Warn sparingly so the warnings don't become a flood themselves.
+			if strikes == 1 || strikes%50 == 0 {
+				h.sendError(client, ErrCodeRateLimited, "message rate limit exceeded")
+			}
+			continue
+		}
+		h.handleMessage(client, raw)
+	}
+}
+
+// remoteIP extracts the peer's IP address from the request. Behind a trusted
+// proxy the socket peer is the proxy itself, so the original client comes
+// from the first X-Forwarded-For hop instead.
+func remoteIP(r *http.Request) string {
+	if trustProxyHeaders {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// remoteCountry reads the client's GeoIP country from the fronting proxy
+// (CF-IPCountry as set by e.g. Cloudflare), or "" when none is trusted or
+// present. The server does no GeoIP lookups of its own.
+func remoteCountry(r *http.Request) string {
+	if !trustProxyHeaders {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(r.Header.Get("CF-IPCountry")))
+}
+
+// handleMessage dispatches one inbound frame. A panic while handling one
+// message is contained to the offending connection: it is logged with a
+// stack trace and the connection is closed, so a malformed message or a
+// state bug can't crash the whole server.
+func (h *Handler) handleMessage(c *Client, raw []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic handling message", "client_id", c.ID, "room_id", c.RoomID, "panic", r, "stack", string(debug.Stack()))
+			c.Close()
+		}
+	}()
+	msg, err := c.codec.Decode(raw)
+	if err != nil {
+		slog.Warn("bad frame", "client_id", c.ID, "error", err)
+		return
+	}
+	c.markActive()
+	if c.RoomID != "" {
+		h.Hub.Touch(c.RoomID)
+	}
+	// Seq-stamped messages get at-most-once, in-order processing: a stale
+	// or duplicate number is rejected with the last processed seq echoed,
+	// and anything consumed is ACKed once handling finishes.
+	if msg.Seq != 0 {
+		if msg.Seq <= c.lastSeq {
+			h.sendError(c, ErrCodeStaleSeq, "stale or duplicate seq")
+			return
+		}
+		c.lastSeq = msg.Seq
+		defer c.SendJSON(MsgAck, AckPayload{Seq: msg.Seq})
+	}
+	ctx, cancel := context.WithTimeout(c.Context(), messageTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "ws.message", trace.WithAttributes(
+		attribute.String("message.type", msg.Type),
+		attribute.String("client.id", c.ID),
+	))
+	defer span.End()
+
+	handle, ok := h.handlers[msg.Type]
+	if !ok {
+		slog.Warn("unknown message type", "client_id", c.ID, "msg_type", msg.Type)
+		h.sendError(c, ErrCodeUnknownType, "unknown message type "+msg.Type)
+		return
+	}
+	handle(ctx, c, msg)
+}
+
+// handleJoinQueue runs the JOIN_QUEUE gauntlet — state, maintenance flag,
+// name ownership, identity adoption, ban check — and hands the client to
+// the matchmaker.
+func (h *Handler) handleJoinQueue(ctx context.Context, c *Client, p JoinQueuePayload, _ int64) {
+	if c.InQueue {
+		h.sendError(c, ErrCodeAlreadyInQueue, "already waiting for a match")
+		return
+	}
+	if c.RoomID != "" {
+		c.SendJSON(MsgError, ErrorPayload{
+			Code:    ErrCodeAlreadyInMatch,
+			Message: "already in a match",
+			LastSeq: c.lastSeq,
+			RoomID:  c.RoomID,
+		})
+		return
+	}
+	// Matchmaking can be switched off for maintenance; running matches
+	// keep going, only new ones are refused.
+	if !h.flags.Enabled(ctx, flags.Matchmaking) {
+		h.sendError(c, ErrCodeMaintenance, "matchmaking is paused for maintenance, please try again soon")
+		return
+	}
+	// Registered identities own their name: neither a guest nor another
+	// account may queue under it. A player's own registered name passes,
+	// since NameTaken exempts their ID.
+	if taken, err := h.Store.NameTaken(ctx, p.Name, p.PlayerID); err != nil {
+		slog.Error("name uniqueness check failed", "player_id", p.PlayerID, "error", err)
+	} else if taken {
+		h.sendError(c, ErrCodeNameTaken, "name belongs to a registered player")
+		return
+	}
+	c.Name = p.Name
+	if p.PlayerID != "" && p.PlayerID != c.ID {
+		// One live connection per player identity: a second socket
+		// adopting the same ID would hijack the first one's hub entry.
+		if _, online := h.Hub.GetClient(p.PlayerID); online {
+			h.sendError(c, ErrCodeConnectionLimit, "player already connected elsewhere")
+			return
+		}
+		if banned, err := h.Store.IsBanned(ctx, p.PlayerID); err != nil {
+			slog.Error("ban check failed", "player_id", p.PlayerID, "error", err)
+		} else if banned {
+			h.sendError(c, ErrCodeBanned, "account banned")
+			return
+		}
+		// Adopt the client's persistent identity so ratings and stats
+		// follow the player across connections.
+		h.Hub.Unregister(c)
+		c.ID = p.PlayerID
+		h.Hub.Register(c)
+	}
+	rating, err := h.Store.GetRating(ctx, c.ID)
+	if err != nil {
+		slog.Error("load rating failed", "client_id", c.ID, "error", err)
+		rating.Rating = game.DefaultRating
+	}
+	c.Rating = rating.Rating
+	h.loadSkin(ctx, c)
+	// Queueing ends any spectating session; a player can't do both.
+	h.stopSpectating(c)
+	if !h.Matchmaker.Enqueue(c, p.Mode, p.Format) {
+		// Two racing JOIN_QUEUEs can both pass the checks above; only
+		// the matchmaker's transition under its lock is authoritative.
+		h.sendError(c, ErrCodeAlreadyInQueue, "already waiting for a match")
+	}
+}
+
+// handlePractice starts an unranked bot match for an idle client.
+func (h *Handler) handlePractice(_ context.Context, c *Client, p PracticePayload, _ int64) {
+	if c.InQueue || c.RoomID != "" {
+		return
+	}
+	c.Name = p.Name
+	if c.Rating == 0 {
+		c.Rating = game.DefaultRating
+	}
+	h.stopSpectating(c)
+	h.Matchmaker.CreatePracticeMatch(c, p.Difficulty)
+}
+
+// handleJoinChallenge starts a daily-challenge attempt for an idle client: a
+// bot match on today's shared seed whose final score ranks on the challenge
+// board.
+func (h *Handler) handleJoinChallenge(ctx context.Context, c *Client, p JoinChallengePayload, _ int64) {
+	if c.InQueue || c.RoomID != "" {
+		return
+	}
+	c.Name = p.Name
+	if p.PlayerID != "" && p.PlayerID != c.ID {
+		if _, online := h.Hub.GetClient(p.PlayerID); online {
+			h.sendError(c, ErrCodeConnectionLimit, "player already connected elsewhere")
+			return
+		}
+		if banned, err := h.Store.IsBanned(ctx, p.PlayerID); err != nil {
+			slog.Error("ban check failed", "player_id", p.PlayerID, "error", err)
+		} else if banned {
+			h.sendError(c, ErrCodeBanned, "account banned")
+			return
+		}
+		// Challenge bests persist per player ID, so adopt the client's
+		// stable identity the same way JOIN_QUEUE does.
+		h.Hub.Unregister(c)
+		c.ID = p.PlayerID
+		h.Hub.Register(c)
+	}
+	if c.Rating == 0 {
+		c.Rating = game.DefaultRating
+	}
+	h.stopSpectating(c)
+	h.Matchmaker.CreateChallengeMatch(c, game.ChallengeDay(time.Now()))
+}
+
+// handlePlayerInput feeds one action into the room's server-side simulation
+// and appends it to the replay log.
+func (h *Handler) handlePlayerInput(_ context.Context, c *Client, p PlayerInputPayload, _ int64) {
+	h.Loops.Input(c.RoomID, game.Input{PlayerID: c.ID, Kind: game.InputKind(p.Action)})
+	if err := h.Store.RecordInput(context.Background(), c.RoomID, c.ID, p.Action, p.T); err != nil {
+		slog.Warn("input log append failed", "client_id", c.ID, "room_id", c.RoomID, "error", err)
+	}
+}
+
+// handleChat relays a sanitized chat line to the sender's room.
+func (h *Handler) handleChat(_ context.Context, c *Client, p ChatPayload, _ int64) {
+	text := sanitizeChat(p.Text)
+	if text == "" {
+		return
+	}
+	h.notifyChat(c, MsgChat, ChatPayload{PlayerID: c.ID, Name: c.Name, Text: text})
+}
+
+// handleUpdateScore runs the UPDATE_SCORE path with a child span per stage
+// so latency can be attributed to anti-cheat or fan-out; payload validation
+// and the room/alive gates already ran in the registration pipeline.
+func (h *Handler) handleUpdateScore(ctx context.Context, c *Client, p UpdateScorePayload, seq int64) {
+	ctx, span := tracing.Start(ctx, "ws.update_score")
+	defer span.End()
+
+	if !h.verifyScoreSig(c, p.Score, seq, p.Sig) {
+		h.sendError(c, ErrCodeBadSignature, "score signature invalid")
+		return
+	}
+
+	var err error
+	_, antiCheat := tracing.Start(ctx, "ws.anti_cheat")
+	cur := c.currentScore()
+	jumpOK := p.Score >= cur && p.Score-cur <= allowedScoreJump(c)
+	if !jumpOK {
+		err = errScoreJump
+	} else {
+		err = h.Validator.ValidateScore(c.RoomID, c.ID, p.Score)
+	}
+	antiCheat.End()
+	if err != nil {
+		slog.Warn("anti-cheat rejected score", "client_id", c.ID, "room_id", c.RoomID, "from", cur, "to", p.Score, "error", err)
+		h.flagCheat(ctx, c, p.Score, err)
+		h.sendError(c, ErrCodeScoreRejected, "score update rejected")
+		return
+	}
+	c.adoptScore(p.Score)
+	h.bus.SetPlayerState(c.RoomID, c.ID, p.Score, c.alive())
+	h.events.Publish(events.ScoreUpdated{RoomID: c.RoomID, PlayerID: c.ID, Score: p.Score})
+	if err := h.Store.RecordScoreEvent(ctx, c.ID, c.RoomID, p.Score); err != nil {
+		slog.Warn("score audit append failed", "client_id", c.ID, "room_id", c.RoomID, "error", err)
+	}
+
+	_, notify := tracing.Start(ctx, "ws.notify")
+	if c.Team != "" {
+		h.notifyTeamUpdate(c)
+	} else {
+		h.notifyOpponent(c, MsgOpponentUpdate, OpponentUpdatePayload{PlayerID: c.ID, Score: p.Score})
+	}
+	notify.End()
+}
+
+// persistScore records one player's final score, unless leaderboard writes
+// are switched off for maintenance. A successful save is announced on the
+// event bus.
+func (h *Handler) persistScore(ctx context.Context, roomID string, c *Client) {
+	score := c.currentScore()
+	// Daily-challenge attempts rank on their own per-day board, one best
+	// score per player, and never touch the ranked leaderboard.
+	if day, ok := h.Matchmaker.ChallengeDay(roomID); ok {
+		if c.IsBot {
+			return
+		}
+		if err := h.Store.SaveChallengeScore(ctx, day, c.ID, c.Name, score); err != nil {
+			slog.Error("persist challenge score failed", "client_id", c.ID, "day", day, "error", err)
+		}
+		return
+	}
+	// Practice runs never reach the ranked leaderboard.
+	if h.Matchmaker.Practice(roomID) {
+		return
+	}
+	if !h.flags.Enabled(ctx, flags.LeaderboardWrites) {
+		slog.Info("leaderboard writes disabled, dropping score", "client_id", c.ID, "room_id", roomID, "score", score)
+		return
+	}
+	if err := h.Store.SaveScore(ctx, c.ID, c.Name, score, c.Country, roomID); err != nil {
+		slog.Error("persist score failed", "client_id", c.ID, "room_id", roomID, "error", err)
+	} else {
+		h.events.Publish(events.PlayerDied{RoomID: roomID, PlayerID: c.ID, PlayerName: c.Name, Score: score})
+	}
+}
+
+// flagCheat records a rejected score in the cheat_flags table so moderators
+// can review repeat offenders. Flag persistence is best-effort; the
+// rejection itself already happened.
+func (h *Handler) flagCheat(ctx context.Context, c *Client, score int, cause error) {
+	reason := db.CheatReasonScoreEnvelope
+	if errors.Is(cause, errScoreJump) {
+		reason = db.CheatReasonScoreJump
+	}
+	if err := h.Store.RecordCheatFlag(ctx, c.ID, c.Name, c.RoomID, score, reason, cause.Error()); err != nil {
+		slog.Error("record cheat flag failed", "client_id", c.ID, "error", err)
+	}
+}
+
+// handleReport files a cheating report against another participant of the
+// sender's room. The room ID stored with it ties the report to that match's
+// score audit trail for admin review.
+func (h *Handler) handleReport(ctx context.Context, c *Client, p ReportPlayerPayload) {
+	if c.RoomID == "" {
+		h.sendError(c, ErrCodeNotInRoom, "not in a match")
+		return
+	}
+	if p.PlayerID == c.ID {
+		h.sendError(c, ErrCodeInvalidPayload, "cannot report yourself")
+		return
+	}
+	target, ok := h.Hub.GetClient(p.PlayerID)
+	if !ok || target.RoomID != c.RoomID || target.IsBot {
+		h.sendError(c, ErrCodeInvalidPayload, "reported player is not in your match")
+		return
+	}
+	if err := h.Store.RecordReport(ctx, c.ID, c.Name, target.ID, target.Name, c.RoomID, p.Reason); err != nil {
+		slog.Error("record report failed", "reporter_id", c.ID, "reported_id", target.ID, "error", err)
+		return
+	}
+	slog.Info("player report filed", "reporter_id", c.ID, "reported_id", target.ID, "room_id", c.RoomID)
+}
+
+// notifyTeamUpdate broadcasts a team-mode score change to the whole room
+// with both teams' running totals.
+func (h *Handler) notifyTeamUpdate(c *Client) {
+	clients := h.Hub.GetClientsInRoom(c.RoomID)
+	totals := make(map[string]int, 2)
+	score := 0
+	for _, other := range clients {
+		s := other.currentScore()
+		totals[other.Team] += s
+		if other.ID == c.ID {
+			score = s
+		}
+	}
+	payload := TeamUpdatePayload{PlayerID: c.ID, Score: score, Teams: totals}
+	for _, other := range clients {
+		if other.ID != c.ID {
+			other.SendJSON(MsgTeamUpdate, payload)
+		}
+	}
+	h.bus.Publish(c.RoomID, c.ID, MsgTeamUpdate, payload)
+}
+
+// handlePlayerDied runs the PLAYER_DIED path: adopt the final score, notify
+// the room, persist, and maybe resolve the match.
+func (h *Handler) handlePlayerDied(ctx context.Context, c *Client, p PlayerDiedPayload, seq int64) {
+	ctx, span := tracing.Start(ctx, "ws.player_died")
+	defer span.End()
+
+	// A final score that was never part of a verified room does not reach
+	// SaveScore: without a valid signature the submission is dropped here.
+	if !h.verifyScoreSig(c, p.Score, seq, p.Sig) {
+		h.sendError(c, ErrCodeBadSignature, "score signature invalid")
+		return
+	}
+	final := c.currentScore()
+	if p.Score >= final && p.Score-final <= allowedScoreJump(c) {
+		if err := h.Validator.ValidateScore(c.RoomID, c.ID, p.Score); err != nil {
+			// The last known good score stands in for the implausible one.
+			h.flagCheat(ctx, c, p.Score, err)
+		} else {
+			c.adoptScore(p.Score)
+			final = p.Score
+		}
+	} else {
+		h.flagCheat(ctx, c, p.Score, errScoreJump)
+	}
+	c.markDead()
+	h.bus.SetPlayerState(c.RoomID, c.ID, final, false)
+
+	_, notify := tracing.Start(ctx, "ws.notify")
+	h.notifyOpponent(c, MsgOpponentDied, OpponentUpdatePayload{PlayerID: c.ID, Score: final})
+	notify.End()
+
+	persistCtx, persist := tracing.Start(ctx, "ws.persist")
+	h.persistScore(persistCtx, c.RoomID, c)
+	persist.End()
+
+	h.maybeFinishMatch(ctx, c.RoomID)
+}
+
+// challengeFriend pushes an INVITE to an online friend who isn't already
+// queued or playing. settings carries the challenger's custom match
+// parameters, applied if the invite is accepted.
+func (h *Handler) challengeFriend(ctx context.Context, c *Client, friendID string, settings *MatchSettings) {
+	if c.RoomID != "" || c.InQueue {
+		h.sendError(c, ErrCodeChallengeFailed, "finish your current game first")
+		return
+	}
+	friends, err := h.Store.AreFriends(ctx, c.ID, friendID)
+	if err != nil {
+		slog.Error("friendship check failed", "client_id", c.ID, "friend_id", friendID, "error", err)
+		h.sendError(c, ErrCodeChallengeFailed, "challenge failed")
+		return
+	}
+	if !friends {
+		h.sendError(c, ErrCodeChallengeFailed, "not in your friend list")
+		return
+	}
+	friend, ok := h.Hub.GetClient(friendID)
+	if !ok || friend.RoomID != "" || friend.InQueue {
+		h.sendError(c, ErrCodeChallengeFailed, "friend is not available")
+		return
+	}
+
+	h.invitesMu.Lock()
+	h.invites[friendID] = invite{fromID: c.ID, settings: settings}
+	h.invitesMu.Unlock()
+	friend.SendJSON(MsgInvite, InvitePayload{FromID: c.ID, FromName: c.Name})
+	slog.Info("friend challenged", "client_id", c.ID, "friend_id", friendID)
+}
+
+// acceptInvite resolves a pending challenge by rooming the pair directly,
+// bypassing the public queue.
+func (h *Handler) acceptInvite(c *Client, fromID string) {
+	h.invitesMu.Lock()
+	inv, ok := h.invites[c.ID]
+	if ok && inv.fromID == fromID {
+		delete(h.invites, c.ID)
+	}
+	h.invitesMu.Unlock()
+	if !ok || inv.fromID != fromID {
+		h.sendError(c, ErrCodeChallengeFailed, "no pending invite from that player")
+		return
+	}
+	inviter, found := h.Hub.GetClient(fromID)
+	if !found || inviter.RoomID != "" || c.RoomID != "" {
+		h.sendError(c, ErrCodeChallengeFailed, "challenger is no longer available")
+		return
+	}
+	slog.Info("friend challenge accepted", "client_id", c.ID, "inviter_id", fromID)
+	h.Matchmaker.CreateDirectMatch(inviter, c, FormatSingle, inv.settings)
+}
+
+// notifyOpponent sends a message to every other client in c's room, local
+// ones through the hub's broadcast workers — off this read loop — and remote
+// ones through the room bus.
+func (h *Handler) notifyOpponent(c *Client, msgType string, payload interface{}) {
+	h.Hub.BroadcastToRoom(c.RoomID, c.ID, msgType, payload)
+	h.bus.Publish(c.RoomID, c.ID, msgType, payload)
+}
+
+// sendError reports a rejected message back to the client with a
+// machine-readable code and the last processed seq for resyncing.
+func (h *Handler) sendError(c *Client, code, message string) {
+	c.SendJSON(MsgError, ErrorPayload{Code: code, Message: message, LastSeq: c.lastSeq})
+}
+
+// allowChat enforces the per-client chat cooldown.
+func (h *Handler) allowChat(c *Client) bool {
+	now := time.Now()
+	if now.Sub(c.lastChat) < chatCooldown {
+		h.sendError(c, ErrCodeRateLimited, "chat rate limit exceeded")
+		return false
+	}
+	c.lastChat = now
+	return true
+}
+
+// notifyChat relays a chat or emote to roommates who haven't muted chat.
+func (h *Handler) notifyChat(c *Client, msgType string, payload interface{}) {
+	for _, other := range h.Hub.GetClientsInRoom(c.RoomID) {
+		if other.ID != c.ID && !other.Muted {
+			other.SendJSON(msgType, payload)
+		}
+	}
+	h.bus.Publish(c.RoomID, c.ID, msgType, payload)
+}
+
+// eventPlayers converts a room's clients to event participants.
+func eventPlayers(clients []*Client) []events.Player {
+	players := make([]events.Player, 0, len(clients))
+	for _, c := range clients {
+		players = append(players, events.Player{ID: c.ID, Name: c.Name, Rating: c.Rating, Team: c.Team})
+	}
+	return players
+}
+
+// notifyMatchEnd publishes a finished match on the event bus and drops the
+// room's restart checkpoint, which is now stale. Must run
+// before cleanupClient resets the players' match state.
+func (h *Handler) notifyMatchEnd(roomID string, clients []*Client, scores map[string]int, winnerID, winningTeam, reason string, duration time.Duration) {
+	h.events.Publish(events.MatchFinished{
+		RoomID:      roomID,
+		Players:     eventPlayers(clients),
+		Scores:      scores,
+		WinnerID:    winnerID,
+		WinningTeam: winningTeam,
+		Reason:      reason,
+		DurationMs:  duration.Milliseconds(),
+		FinishedAt:  time.Now(),
+	})
+	h.bus.DropCheckpoint(roomID)
+}
+
+// maybeFinishMatch resolves the room once every player in it is dead.
+func (h *Handler) maybeFinishMatch(ctx context.Context, roomID string) {
+	clients := h.Hub.GetClientsInRoom(roomID)
+	for _, c := range clients {
+		if c.alive() {
+			return
+		}
+	}
+	if len(clients) > 0 && clients[0].Team != "" {
+		h.finishTeamMatch(ctx, roomID, clients)
+		return
+	}
+
+	scores := make(map[string]int, len(clients))
+	for _, c := range clients {
+		scores[c.ID] = c.currentScore()
+	}
+	winnerID, isDraw := decideWinner(clients)
+	// A tied match goes to sudden death where the format allows it, rather
+	// than settling for a draw.
+	if isDraw && h.Matchmaker.StartOvertime(roomID) {
+		return
+	}
+
+	// Multi-round formats keep playing until someone has enough round wins;
+	// AdvanceRound schedules the next round itself in that case.
+	done, _ := h.Matchmaker.AdvanceRound(roomID, winnerID)
+	if !done {
+		return
+	}
+	duration := h.Matchmaker.MatchDuration(roomID)
+	practice := h.Matchmaker.Practice(roomID)
+	room, ok := h.Matchmaker.ClearMatch(roomID)
+	if !ok {
+		return
+	}
+
+	vsBot := false
+	for _, c := range clients {
+		if c.IsBot {
+			vsBot = true
+		}
+	}
+	// Bot matches are unrated.
+	var ratingDeltas map[string]int
+	if len(clients) == 2 && !vsBot {
+		ratingDeltas = h.updateRatings(ctx, clients[0], clients[1], winnerID)
+	}
+
+	h.notifyMatchEnd(roomID, clients, scores, winnerID, "", GameOverReasonFinished, duration)
+	for _, c := range clients {
+		c.SendJSON(MsgGameOver, GameOverPayload{
+			WinnerID: winnerID, Scores: scores, Reason: GameOverReasonFinished,
+			Summary: h.matchSummary(ctx, c, scores, duration, ratingDeltas, practice),
+		})
+		if !practice {
+			h.recordStats(ctx, c, winnerID, vsBot)
+		}
+		h.cleanupClient(c)
+	}
+	h.cleanupRoom(roomID, room)
+	slog.Info("match finished", "room_id", roomID, "winner_id", winnerID, "scores", scores, "duration", duration)
+}
+
+// finishTimedMatch resolves a timed room whose clock ran out: the highest
+// score wins, an exact tie is a draw. Racing with the normal death path is
+// settled by whoever clears the match bookkeeping first.
+func (h *Handler) finishTimedMatch(roomID string) {
+	ctx := context.Background()
+	duration := h.Matchmaker.MatchDuration(roomID)
+	practice := h.Matchmaker.Practice(roomID)
+	room, ok := h.Matchmaker.ClearMatch(roomID)
+	if !ok {
+		return
+	}
+	clients := h.Hub.GetClientsInRoom(roomID)
+	if len(clients) == 0 {
+		return
+	}
+
+	scores := make(map[string]int, len(clients))
+	for _, c := range clients {
+		scores[c.ID] = c.currentScore()
+	}
+	// An exact tie the configured tie-breaker cannot split is a draw; a
+	// timed room never goes to overtime.
+	winnerID, _ := decideWinner(clients)
+
+	vsBot := false
+	for _, c := range clients {
+		if c.IsBot {
+			vsBot = true
+		}
+	}
+	var ratingDeltas map[string]int
+	if len(clients) == 2 && !vsBot {
+		ratingDeltas = h.updateRatings(ctx, clients[0], clients[1], winnerID)
+	}
+
+	h.notifyMatchEnd(roomID, clients, scores, winnerID, "", GameOverReasonTimeUp, duration)
+	for _, c := range clients {
+		// Players who were still running haven't had their final score
+		// persisted by a PLAYER_DIED yet.
+		if c.alive() && !c.IsBot {
+			h.persistScore(ctx, roomID, c)
+		}
+		c.markDead()
+		c.SendJSON(MsgGameOver, GameOverPayload{
+			WinnerID: winnerID, Scores: scores, Reason: GameOverReasonTimeUp,
+			Summary: h.matchSummary(ctx, c, scores, duration, ratingDeltas, practice),
+		})
+		h.recordStats(ctx, c, winnerID, vsBot)
+		h.cleanupClient(c)
+	}
+	h.cleanupRoom(roomID, room)
+	slog.Info("timed match expired", "room_id", roomID, "winner_id", winnerID, "scores", scores, "duration", duration)
+}
+
+// updateRatings applies the Elo formula to both sides of a finished duel
+// and persists the new ratings, returning each player's delta keyed by ID
+// for the GAME_OVER summary.
+func (h *Handler) updateRatings(ctx context.Context, a, b *Client, winnerID string) map[string]int {
+	scoreA := 0.5
+	switch winnerID {
+	case a.ID:
+		scoreA = 1
+	case b.ID:
+		scoreA = 0
+	}
+	deltaA := game.EloDelta(a.Rating, b.Rating, scoreA)
+	deltaB := game.EloDelta(b.Rating, a.Rating, 1-scoreA)
+	a.Rating += deltaA
+	b.Rating += deltaB
+	if err := h.Store.SaveRating(ctx, a.ID, a.Name, a.Rating); err != nil {
+		slog.Error("save rating failed", "client_id", a.ID, "error", err)
+	}
+	if err := h.Store.SaveRating(ctx, b.ID, b.Name, b.Rating); err != nil {
+		slog.Error("save rating failed", "client_id", b.ID, "error", err)
+	}
+	return map[string]int{a.ID: deltaA, b.ID: deltaB}
+}
+
+// matchSummary builds one recipient's server-computed GAME_OVER result
+// block. It must run before recordStats folds the score into the player's
+// profile, or the personal-best comparison would be against itself.
+func (h *Handler) matchSummary(ctx context.Context, c *Client, scores map[string]int, duration time.Duration, ratingDeltas map[string]int, practice bool) *MatchSummary {
+	s := &MatchSummary{DurationMs: duration.Milliseconds(), RatingDelta: ratingDeltas[c.ID]}
+	if secs := duration.Seconds(); secs > 0 {
+		best := 0
+		for _, sc := range scores {
+			if sc > best {
+				best = sc
+			}
+		}
+		s.MaxScoreRate = float64(best) / secs
+	}
+	if c.IsBot || practice {
+		return s
+	}
+	score := c.currentScore()
+	if prevBest, err := h.Store.BestScore(ctx, c.ID); err == nil {
+		s.PersonalBest = score > prevBest
+	} else {
+		slog.Error("best score lookup failed", "client_id", c.ID, "error", err)
+	}
+	if rank, err := h.Store.ScoreRank(ctx, score); err == nil {
+		s.Rank = rank
+	} else {
+		slog.Error("score rank lookup failed", "client_id", c.ID, "error", err)
+	}
+	return s
+}
+
+// recordStats folds one resolved match into a client's persistent profile.
+// Bots keep no profile of their own; matches against one are tagged so they
+// are distinguishable in a player's record.
+func (h *Handler) recordStats(ctx context.Context, c *Client, winnerID string, vsBot bool) {
+	outcome := db.OutcomeLoss
+	switch winnerID {
+	case c.ID:
+		outcome = db.OutcomeWin
+	case "":
+		outcome = db.OutcomeDraw
+	}
+	h.recordOutcome(ctx, c, outcome, vsBot)
+}
+
+// recordOutcome persists one player's already-decided result.
+func (h *Handler) recordOutcome(ctx context.Context, c *Client, outcome string, vsBot bool) {
+	if c.IsBot {
+		return
+	}
+	if err := h.Store.RecordMatchResult(ctx, c.ID, c.Name, c.currentScore(), outcome, vsBot); err != nil {
+		slog.Error("record stats failed", "client_id", c.ID, "error", err)
+		return
+	}
+	h.awardAchievements(ctx, c, outcome, vsBot)
+}
+
+// finishTeamMatch resolves a 2v2 room once all four players are dead: the
+// team with the higher combined score wins, equal totals draw. Team games
+// are unrated.
+func (h *Handler) finishTeamMatch(ctx context.Context, roomID string, clients []*Client) {
+	duration := h.Matchmaker.MatchDuration(roomID)
+	room, ok := h.Matchmaker.ClearMatch(roomID)
+	if !ok {
+		return
+	}
+
+	scores := make(map[string]int, len(clients))
+	totals := make(map[string]int, 2)
+	for _, c := range clients {
+		s := c.currentScore()
+		scores[c.ID] = s
+		totals[c.Team] += s
+	}
+	winningTeam := ""
+	switch {
+	case totals[TeamA] > totals[TeamB]:
+		winningTeam = TeamA
+	case totals[TeamB] > totals[TeamA]:
+		winningTeam = TeamB
+	}
+
+	h.notifyMatchEnd(roomID, clients, scores, "", winningTeam, GameOverReasonFinished, duration)
+	for _, c := range clients {
+		c.SendJSON(MsgGameOver, GameOverPayload{
+			Scores: scores, Reason: GameOverReasonFinished, WinningTeam: winningTeam,
+			Summary: h.matchSummary(ctx, c, scores, duration, nil, false),
+		})
+		outcome := db.OutcomeDraw
+		switch {
+		case winningTeam == "":
+		case c.Team == winningTeam:
+			outcome = db.OutcomeWin
+		default:
+			outcome = db.OutcomeLoss
+		}
+		h.recordOutcome(ctx, c, outcome, false)
+		h.cleanupClient(c)
+	}
+	h.cleanupRoom(roomID, room)
+	slog.Info("team match finished", "room_id", roomID, "winning_team", winningTeam, "totals", totals, "duration", duration)
+}
+
+// expireSession resolves a match as a forfeit after a disconnected player's
+// reconnect grace window elapsed: the surviving opponent wins, their score is
+// persisted, and the room is cleaned up.
+func (h *Handler) expireSession(c *Client) {
+	roomID := c.RoomID
+	slog.Info("client never reconnected, forfeiting match", "client_id", c.ID, "room_id", roomID)
+	h.Hub.SetRoom(c, "")
+	c.SessionToken = ""
+	h.Hub.Unregister(c)
+	c.closeSend()
+	if roomID == "" {
+		return
+	}
+
+	scores := map[string]int{c.ID: c.currentScore()}
+	survivors := h.Hub.GetClientsInRoom(roomID)
+	winnerID := ""
+	vsBot := false
+	for _, s := range survivors {
+		scores[s.ID] = s.currentScore()
+		winnerID = s.ID
+		if s.IsBot {
+			vsBot = true
+		}
+	}
+	ctx := context.Background()
+	duration := h.Matchmaker.MatchDuration(roomID)
+	practice := h.Matchmaker.Practice(roomID)
+	room, ok := h.Matchmaker.ClearMatch(roomID)
+	if !ok {
+		// Another finish path already resolved the room; the leaver's state
+		// was cleaned up with it and there is no match left to forfeit.
+		return
+	}
+	if !practice {
+		h.recordStats(ctx, c, winnerID, vsBot)
+	}
+	h.notifyMatchEnd(roomID, append(survivors, c), scores, winnerID, "", GameOverReasonOpponentLeft, duration)
+	for _, s := range survivors {
+		s.SendJSON(MsgGameOver, GameOverPayload{
+			WinnerID: winnerID, Scores: scores, Reason: GameOverReasonOpponentLeft,
+			Summary: h.matchSummary(ctx, s, scores, duration, nil, practice),
+		})
+		if !s.IsBot {
+			h.persistScore(ctx, roomID, s)
+		}
+		if !practice {
+			h.recordStats(ctx, s, winnerID, vsBot)
+		}
+		h.cleanupClient(s)
+	}
+	h.cleanupRoom(roomID, room)
+}
+
+// cleanupRoom releases a resolved room's runtime state and closes out its
+// lifecycle. A failed transition means a second finish path got here after
+// the room was already cleaned; the state machine turns that from silent
+// double-teardown into a logged bug.
+func (h *Handler) cleanupRoom(roomID string, room *game.Room) {
+	h.Loops.Stop(roomID)
+	h.Validator.UnregisterMatch(roomID)
+	if room == nil {
+		return
+	}
+	if err := room.Transition(game.RoomCleaned); err != nil {
+		slog.Warn("room cleaned out of order", "room_id", roomID, "error", err)
+	}
+}
+
+// cleanupClient resets a client's match state once its room is resolved.
+func (h *Handler) cleanupClient(c *Client) {
+	h.bus.LeaveRoom(c.RoomID, c.ID)
+	h.Sessions.Drop(c.SessionToken)
+	h.Hub.SetRoom(c, "")
+	c.resetMatchState()
+	c.Team = ""
+	c.SessionToken = ""
+}
@@ -0,0 +1,1024 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/db"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// newTestHandler wires a handler against the in-memory store so message
+// dispatch can be exercised without Postgres or real sockets.
+func newTestHandler() (*Handler, *db.MemStore) {
+	store := db.NewMemStore()
+	hub := NewHub()
+	validator := game.NewValidator()
+	sessions := NewSessionStore()
+	loops := game.NewLoopManager()
+	mm := NewMatchmaker(hub, validator, sessions, loops)
+	return NewHandler(hub, mm, validator, sessions, loops, store), store
+}
+
+// newTestClient returns a connectionless client, like a bot: frames queue on
+// Send where the test can inspect them.
+func newTestClient(id string) *Client {
+	return NewClient(id, nil, jsonCodec{})
+}
+
+// frame builds one inbound message as raw bytes.
+func frame(t *testing.T, msgType string, payload interface{}) []byte {
+	t.Helper()
+	raw, err := jsonCodec{}.Encode(msgType, payload)
+	if err != nil {
+		t.Fatalf("encode %s: %v", msgType, err)
+	}
+	return raw
+}
+
+// drainTypes empties a client's send buffer and returns the message types.
+func drainTypes(c *Client) []string {
+	var types []string
+	for {
+		select {
+		case raw := <-c.Send:
+			var msg Message
+			json.Unmarshal(raw, &msg)
+			types = append(types, msg.Type)
+		default:
+			return types
+		}
+	}
+}
+
+// signedScore builds a signed score payload for a room set up in a test.
+func signedScore(t *testing.T, h *Handler, roomID string, score int) (int, string) {
+	t.Helper()
+	key, ok := h.Matchmaker.ScoreKey(roomID)
+	if !ok {
+		t.Fatalf("no score key for %s", roomID)
+	}
+	return score, SignScore(key, roomID, score, 0)
+}
+
+func TestUpdateScoreRejectsJumps(t *testing.T) {
+	h, _ := newTestHandler()
+	c := newTestClient("p1")
+	h.Hub.Register(c)
+	roomID := "room_test"
+	h.Hub.SetRoom(c, roomID)
+	c.IsAlive = true
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{c})
+
+	score, sig := signedScore(t, h, roomID, maxScoreJump+1)
+	h.handleMessage(c, frame(t, MsgUpdateScore, UpdateScorePayload{Score: score, Sig: sig}))
+
+	if c.Score != 0 {
+		t.Fatalf("score = %d, want 0 after rejected jump", c.Score)
+	}
+	types := drainTypes(c)
+	if len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+}
+
+func TestPlayerDiedFinishesMatchAndPersists(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Validator.RegisterMatch(roomID, 42)
+	a.Score, b.Score = 10, 4
+
+	scoreA, sigA := signedScore(t, h, roomID, a.Score)
+	h.handleMessage(a, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreA, Sig: sigA}))
+	if a.IsAlive {
+		t.Fatal("first player still alive after PLAYER_DIED")
+	}
+	scoreB, sigB := signedScore(t, h, roomID, b.Score)
+	h.handleMessage(b, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreB, Sig: sigB}))
+
+	if len(store.Scores) != 2 {
+		t.Fatalf("persisted %d scores, want 2", len(store.Scores))
+	}
+	if got := store.Scores[0].RoomID; got != roomID {
+		t.Fatalf("persisted room = %q, want %q", got, roomID)
+	}
+	if got := store.StatsByPlayer["p1"].Wins; got != 1 {
+		t.Fatalf("winner wins = %d, want 1", got)
+	}
+	if got := store.StatsByPlayer["p2"].Losses; got != 1 {
+		t.Fatalf("loser losses = %d, want 1", got)
+	}
+	var sawGameOver bool
+	for _, typ := range drainTypes(b) {
+		if typ == MsgGameOver {
+			sawGameOver = true
+		}
+	}
+	if !sawGameOver {
+		t.Fatal("loser never received GAME_OVER")
+	}
+	if a.RoomID != "" || b.RoomID != "" {
+		t.Fatalf("rooms not cleared: %q %q", a.RoomID, b.RoomID)
+	}
+}
+
+func TestTiedSingleMatchGoesToOvertime(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Validator.RegisterMatch(roomID, 42)
+	a.Score, b.Score = 4, 4
+
+	score, sig := signedScore(t, h, roomID, 4)
+	h.handleMessage(a, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: score, Sig: sig}))
+	h.handleMessage(b, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: score, Sig: sig}))
+
+	// The tie must not end the match: both players are reset for a
+	// sudden-death round instead of receiving GAME_OVER.
+	if !a.IsAlive || !b.IsAlive {
+		t.Fatal("players not revived for the overtime round")
+	}
+	if a.Score != 0 || b.Score != 0 {
+		t.Fatalf("scores not reset for overtime: %d %d", a.Score, b.Score)
+	}
+	for _, typ := range drainTypes(a) {
+		if typ == MsgGameOver {
+			t.Fatal("tied match ended as a draw instead of going to overtime")
+		}
+	}
+
+	// The overtime round's winner takes the match.
+	h.Validator.RegisterMatch(roomID, 43)
+	scoreA, sigA := signedScore(t, h, roomID, 3)
+	h.handleMessage(a, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreA, Sig: sigA}))
+	scoreB, sigB := signedScore(t, h, roomID, 5)
+	h.handleMessage(b, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreB, Sig: sigB}))
+
+	if got := store.StatsByPlayer["p2"].Wins; got != 1 {
+		t.Fatalf("overtime winner wins = %d, want 1", got)
+	}
+	var sawGameOver bool
+	for _, typ := range drainTypes(a) {
+		if typ == MsgGameOver {
+			sawGameOver = true
+		}
+	}
+	if !sawGameOver {
+		t.Fatal("overtime round never resolved the match")
+	}
+}
+
+func TestScoreWithBadSignatureIsRejected(t *testing.T) {
+	h, store := newTestHandler()
+	c := newTestClient("p1")
+	h.Hub.Register(c)
+	roomID := "room_test"
+	h.Hub.SetRoom(c, roomID)
+	c.IsAlive = true
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{c})
+
+	h.handleMessage(c, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: 5, Sig: "bogus"}))
+
+	if !c.IsAlive {
+		t.Fatal("unsigned PLAYER_DIED was processed")
+	}
+	if len(store.Scores) != 0 {
+		t.Fatalf("persisted %d scores from an unverified submission", len(store.Scores))
+	}
+	types := drainTypes(c)
+	if len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+}
+
+func TestJoinQueueRejectsBannedPlayer(t *testing.T) {
+	h, store := newTestHandler()
+	store.Banned["cheater"] = true
+	c := newTestClient("guest_ab12cd")
+	h.Hub.Register(c)
+
+	h.handleMessage(c, frame(t, MsgJoinQueue, JoinQueuePayload{Name: "Mallory", PlayerID: "cheater"}))
+
+	if c.InQueue {
+		t.Fatal("banned player entered the queue")
+	}
+	types := drainTypes(c)
+	if len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+}
+
+func TestJoinQueueRejectsReservedName(t *testing.T) {
+	h, store := newTestHandler()
+	store.Ratings["owner"] = db.Rating{PlayerID: "owner", PlayerName: "Champ", Rating: 1200}
+
+	// A guest may not queue under a registered player's name.
+	guest := newTestClient("guest_ab12cd")
+	h.Hub.Register(guest)
+	h.handleMessage(guest, frame(t, MsgJoinQueue, JoinQueuePayload{Name: "Champ"}))
+	if guest.InQueue {
+		t.Fatal("guest queued under a registered player's name")
+	}
+	if types := drainTypes(guest); len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+
+	// The name's owner still can.
+	owner := newTestClient("owner")
+	h.Hub.Register(owner)
+	h.handleMessage(owner, frame(t, MsgJoinQueue, JoinQueuePayload{Name: "Champ", PlayerID: "owner"}))
+	if !owner.InQueue {
+		t.Fatal("owner was refused their own registered name")
+	}
+}
+
+func TestPracticeStartsUnrankedBotMatch(t *testing.T) {
+	h, store := newTestHandler()
+	c := newTestClient("guest_ab12cd")
+	h.Hub.Register(c)
+
+	h.handleMessage(c, frame(t, MsgPractice, PracticePayload{Name: "Dana", Difficulty: BotHard}))
+
+	if c.RoomID == "" {
+		t.Fatal("practice request did not create a room")
+	}
+	if c.InQueue {
+		t.Fatal("practice request put the player in the public queue")
+	}
+	if !h.Matchmaker.Practice(c.RoomID) {
+		t.Fatal("practice room not marked as practice")
+	}
+	var bot *Client
+	for _, other := range h.Hub.GetClientsInRoom(c.RoomID) {
+		if other.ID != c.ID {
+			bot = other
+		}
+	}
+	if bot == nil || !bot.IsBot {
+		t.Fatal("practice opponent is not a bot")
+	}
+	if bot.botTier != BotHard {
+		t.Fatalf("bot tier = %q, want %q", bot.botTier, BotHard)
+	}
+
+	// A practice death stays off the ranked leaderboard.
+	h.Validator.RegisterMatch(c.RoomID, 42)
+	c.IsAlive = true
+	score, sig := signedScore(t, h, c.RoomID, 7)
+	c.Score = score
+	h.handleMessage(c, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: score, Sig: sig}))
+	if len(store.Scores) != 0 {
+		t.Fatalf("practice run persisted %d scores, want 0", len(store.Scores))
+	}
+}
+
+func TestClearMatchArchivesVerification(t *testing.T) {
+	h, _ := newTestHandler()
+	c := newTestClient("p1")
+	roomID := "room_test"
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{c})
+	h.Matchmaker.markStarted(roomID)
+	h.Matchmaker.ClearMatch(roomID)
+
+	v, ok := h.Matchmaker.Verification(roomID)
+	if !ok {
+		t.Fatal("no verification record after ClearMatch")
+	}
+	if len(v.Seeds) != 1 || v.Seeds[0] != 42 {
+		t.Fatalf("archived seeds = %v, want [42]", v.Seeds)
+	}
+	if v.Format != FormatSingle {
+		t.Fatalf("archived format = %q, want %q", v.Format, FormatSingle)
+	}
+	if _, ok := h.Matchmaker.Verification("room_other"); ok {
+		t.Fatal("verification returned a record for an unknown room")
+	}
+}
+
+func TestReportPlayerLandsInReviewQueue(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+
+	h.handleMessage(a, frame(t, MsgReportPlayer, ReportPlayerPayload{PlayerID: "p2", Reason: "impossible score"}))
+	// A repeat against the same opponent in the same match is a no-op.
+	h.handleMessage(a, frame(t, MsgReportPlayer, ReportPlayerPayload{PlayerID: "p2", Reason: "still impossible"}))
+
+	if len(store.Reports) != 1 {
+		t.Fatalf("stored %d reports, want 1", len(store.Reports))
+	}
+	rep := store.Reports[0]
+	if rep.ReporterID != "p1" || rep.ReportedID != "p2" || rep.RoomID != roomID {
+		t.Fatalf("report = %+v, want p1 reporting p2 in %s", rep, roomID)
+	}
+	if rep.Status != db.ReportStatusOpen {
+		t.Fatalf("report status = %q, want %q", rep.Status, db.ReportStatusOpen)
+	}
+
+	// Reporting yourself or someone outside the room is rejected.
+	h.handleMessage(a, frame(t, MsgReportPlayer, ReportPlayerPayload{PlayerID: "p1", Reason: "self"}))
+	h.handleMessage(a, frame(t, MsgReportPlayer, ReportPlayerPayload{PlayerID: "p3", Reason: "stranger"}))
+	if len(store.Reports) != 1 {
+		t.Fatalf("stored %d reports after invalid targets, want 1", len(store.Reports))
+	}
+}
+
+func TestSeqStampedMessagesAreDedupedAndAcked(t *testing.T) {
+	h, _ := newTestHandler()
+	c := newTestClient("p1")
+	h.Hub.Register(c)
+
+	mute := func(seq int64, muted bool) []byte {
+		return []byte(fmt.Sprintf(`{"type":"MUTE","seq":%d,"payload":{"muted":%t}}`, seq, muted))
+	}
+	h.handleMessage(c, mute(1, true))
+	if !c.Muted {
+		t.Fatal("first seq-stamped MUTE was not processed")
+	}
+	if types := drainTypes(c); len(types) != 1 || types[0] != MsgAck {
+		t.Fatalf("messages = %v, want a single ACK", types)
+	}
+
+	// A resend of the same seq must be rejected without re-applying it.
+	h.handleMessage(c, mute(1, false))
+	if !c.Muted {
+		t.Fatal("duplicate seq was processed again")
+	}
+	if types := drainTypes(c); len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+
+	h.handleMessage(c, mute(2, false))
+	if c.Muted {
+		t.Fatal("next seq was not processed")
+	}
+}
+
+func TestMemStoreTopScores(t *testing.T) {
+	store := db.NewMemStore()
+	for i, score := range []int{5, 40, 12} {
+		store.SaveScore(nil, fmt.Sprintf("p%d", i), "player", score, "", "")
+	}
+	top, err := store.TopScores(nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Score != 40 || top[1].Score != 12 {
+		t.Fatalf("top scores = %+v, want 40 then 12", top)
+	}
+}
+
+func TestAchievementsUnlockOnResults(t *testing.T) {
+	h, store := newTestHandler()
+	c := newTestClient("p1")
+	c.Name = "Alice"
+	c.Score = 1200
+
+	ctx := context.Background()
+	h.recordOutcome(ctx, c, db.OutcomeWin, false)
+
+	names := func(id string) map[string]bool {
+		got := map[string]bool{}
+		for _, a := range store.Unlocked[id] {
+			got[a.Name] = true
+		}
+		return got
+	}
+	got := names("p1")
+	if !got[db.AchievementFirstWin] || !got[db.AchievementScore1K] {
+		t.Fatalf("after first win got %v, want first win and score milestones", got)
+	}
+	if got[db.AchievementStreak10] {
+		t.Fatal("streak milestone unlocked after a single win")
+	}
+
+	// Nine more wins complete the streak.
+	for i := 0; i < 9; i++ {
+		h.recordOutcome(ctx, c, db.OutcomeWin, false)
+	}
+	if !names("p1")[db.AchievementStreak10] {
+		t.Fatalf("after ten straight wins got %v, want streak milestone", names("p1"))
+	}
+
+	// Bot matches never award milestones.
+	b := newTestClient("p2")
+	b.Score = 2000
+	h.recordOutcome(ctx, b, db.OutcomeWin, true)
+	if len(store.Unlocked["p2"]) != 0 {
+		t.Fatalf("bot match unlocked %v", store.Unlocked["p2"])
+	}
+}
+
+func TestEnqueueRefusesDuplicates(t *testing.T) {
+	h, _ := newTestHandler()
+	a := newTestClient("p1")
+	h.Hub.Register(a)
+
+	if !h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle) {
+		t.Fatal("first enqueue refused")
+	}
+	if h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle) {
+		t.Fatal("duplicate enqueue of a waiting client accepted")
+	}
+	if got := h.Matchmaker.QueueLength(); got != 1 {
+		t.Fatalf("queue length = %d, want 1", got)
+	}
+
+	// A fresh connection under the same identity evicts the stale entry a
+	// dropped socket left behind instead of being locked out.
+	h.Hub.Unregister(a)
+	b := newTestClient("p1")
+	h.Hub.Register(b)
+	if !h.Matchmaker.Enqueue(b, ModeDuel, FormatSingle) {
+		t.Fatal("returning player locked out by a stale queue entry")
+	}
+	if got := h.Matchmaker.QueueLength(); got != 1 {
+		t.Fatalf("queue length = %d after rejoin, want 1", got)
+	}
+}
+
+func TestMatchingSkipsVanishedWaiter(t *testing.T) {
+	h, _ := newTestHandler()
+	a := newTestClient("p1")
+	h.Hub.Register(a)
+	h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle)
+	// The waiter vanishes without a clean LEAVE_QUEUE, as a dead socket
+	// does when the disconnect races the pairing sweep.
+	h.Hub.Unregister(a)
+
+	b := newTestClient("p2")
+	h.Hub.Register(b)
+	h.Matchmaker.Enqueue(b, ModeDuel, FormatSingle)
+
+	if b.RoomID != "" {
+		t.Fatalf("matched against a vanished waiter in room %q", b.RoomID)
+	}
+	if !b.InQueue {
+		t.Fatal("live player fell out of the queue")
+	}
+	if a.InQueue {
+		t.Fatal("vanished waiter still marked queued after pruning")
+	}
+	if got := h.Matchmaker.QueueLength(); got != 1 {
+		t.Fatalf("queue length = %d, want only the live player", got)
+	}
+}
+
+func TestMatchingHoldsOutOnLatencyGap(t *testing.T) {
+	h, _ := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.latencyMs, b.latencyMs = 20, 400
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+	}
+
+	h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle)
+	h.Matchmaker.Enqueue(b, ModeDuel, FormatSingle)
+	if a.RoomID != "" || b.RoomID != "" {
+		t.Fatal("players with a 380ms latency gap were paired immediately")
+	}
+
+	// A close-latency candidate matches right away.
+	c := newTestClient("p3")
+	c.latencyMs = 35
+	h.Hub.Register(c)
+	h.Matchmaker.Enqueue(c, ModeDuel, FormatSingle)
+	if a.RoomID == "" || a.RoomID != c.RoomID {
+		t.Fatalf("close-latency pair not matched: a=%q c=%q", a.RoomID, c.RoomID)
+	}
+	if !b.InQueue {
+		t.Fatal("laggy player fell out of the queue")
+	}
+}
+
+func TestGameOverCarriesSummary(t *testing.T) {
+	h, store := newTestHandler()
+	store.StatsByPlayer["p1"] = db.Stats{PlayerID: "p1", BestScore: 3}
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Matchmaker.markStarted(roomID)
+	h.Validator.RegisterMatch(roomID, 42)
+	a.Score, b.Score = 10, 4
+
+	scoreA, sigA := signedScore(t, h, roomID, a.Score)
+	h.handleMessage(a, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreA, Sig: sigA}))
+	scoreB, sigB := signedScore(t, h, roomID, b.Score)
+	h.handleMessage(b, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreB, Sig: sigB}))
+
+	var summary *MatchSummary
+	for {
+		var raw []byte
+		select {
+		case raw = <-a.Send:
+		default:
+			t.Fatal("winner never received GAME_OVER")
+		}
+		var msg Message
+		json.Unmarshal(raw, &msg)
+		if msg.Type != MsgGameOver {
+			continue
+		}
+		var p GameOverPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			t.Fatalf("decode GAME_OVER: %v", err)
+		}
+		summary = p.Summary
+		break
+	}
+	if summary == nil {
+		t.Fatal("GAME_OVER carried no summary")
+	}
+	if !summary.PersonalBest {
+		t.Errorf("PersonalBest = false for a 10 beating a stored best of 3")
+	}
+	if summary.RatingDelta == 0 {
+		t.Error("winner's rating delta missing from the summary")
+	}
+	if summary.Rank != 1 {
+		t.Errorf("Rank = %d, want 1 for the highest score", summary.Rank)
+	}
+	if summary.DurationMs < 0 {
+		t.Errorf("DurationMs = %d", summary.DurationMs)
+	}
+}
+
+func TestSimultaneousDeathsResolveOnce(t *testing.T) {
+	h, _ := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Validator.RegisterMatch(roomID, 42)
+	room, _ := h.Matchmaker.Room(roomID)
+	a.Score, b.Score = 10, 4
+	a.IsAlive, b.IsAlive = false, false
+
+	// Both deaths arrive on their own read loops at the same instant; only
+	// one resolution may win.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.maybeFinishMatch(context.Background(), roomID)
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range []*Client{a, b} {
+		overs := 0
+		for _, typ := range drainTypes(c) {
+			if typ == MsgGameOver {
+				overs++
+			}
+		}
+		if overs != 1 {
+			t.Fatalf("client %s got %d GAME_OVERs, want exactly 1", c.ID, overs)
+		}
+	}
+	if got := room.State(); got != game.RoomCleaned {
+		t.Fatalf("room state = %s, want %s", got, game.RoomCleaned)
+	}
+}
+
+func TestLeaveDuringCountdownForfeits(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	room, _ := h.Matchmaker.Room(roomID)
+	if err := room.Transition(game.RoomCountdown); err != nil {
+		t.Fatalf("enter countdown: %v", err)
+	}
+
+	// The leaver's reconnect grace expires mid-countdown.
+	h.expireSession(a)
+
+	var sawGameOver bool
+	for _, typ := range drainTypes(b) {
+		if typ == MsgGameOver {
+			sawGameOver = true
+		}
+	}
+	if !sawGameOver {
+		t.Fatal("survivor never received GAME_OVER after countdown forfeit")
+	}
+	if store.StatsByPlayer["p2"].Wins != 1 {
+		t.Fatalf("survivor wins = %d, want 1", store.StatsByPlayer["p2"].Wins)
+	}
+	if b.RoomID != "" {
+		t.Fatalf("survivor still roomed in %q", b.RoomID)
+	}
+	if got := room.State(); got != game.RoomCleaned {
+		t.Fatalf("room state = %s, want %s", got, game.RoomCleaned)
+	}
+}
+
+func TestDisconnectAfterDeathDoesNotRefinish(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Validator.RegisterMatch(roomID, 42)
+	a.Score, b.Score = 10, 4
+
+	scoreA, sigA := signedScore(t, h, roomID, a.Score)
+	h.handleMessage(a, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreA, Sig: sigA}))
+	scoreB, sigB := signedScore(t, h, roomID, b.Score)
+	h.handleMessage(b, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: scoreB, Sig: sigB}))
+	drainTypes(b)
+
+	// The loser's browser closes after the match already resolved, with the
+	// grace expiry still holding the stale room ID from before the finish.
+	h.Hub.SetRoom(a, roomID)
+	h.expireSession(a)
+
+	if store.StatsByPlayer["p1"].Games != 1 || store.StatsByPlayer["p2"].Games != 1 {
+		t.Fatalf("games = %d/%d, want 1/1 after late disconnect",
+			store.StatsByPlayer["p1"].Games, store.StatsByPlayer["p2"].Games)
+	}
+	for _, typ := range drainTypes(b) {
+		if typ == MsgGameOver {
+			t.Fatal("late disconnect produced a second GAME_OVER")
+		}
+	}
+}
+
+func TestPlayerInputLogged(t *testing.T) {
+	h, store := newTestHandler()
+	c := newTestClient("p1")
+	h.Hub.Register(c)
+	h.Hub.SetRoom(c, "room_test")
+	c.IsAlive = true
+
+	h.handleMessage(c, frame(t, MsgPlayerInput, PlayerInputPayload{Action: "jump", T: 1500}))
+	h.handleMessage(c, frame(t, MsgPlayerInput, PlayerInputPayload{Action: "duck", T: 2100}))
+	c.IsAlive = false
+	h.handleMessage(c, frame(t, MsgPlayerInput, PlayerInputPayload{Action: "jump", T: 2600}))
+
+	if len(store.Inputs) != 2 {
+		t.Fatalf("logged %d inputs, want 2 (dead players' inputs dropped)", len(store.Inputs))
+	}
+	first := store.Inputs[0]
+	if first.RoomID != "room_test" || first.PlayerID != "p1" || first.Action != "jump" || first.ClientTS != 1500 {
+		t.Fatalf("first input = %+v, want jump at t=1500 in room_test", first)
+	}
+	if store.Inputs[1].Action != "duck" || store.Inputs[1].ClientTS != 2100 {
+		t.Fatalf("second input = %+v, want duck at t=2100", store.Inputs[1])
+	}
+}
+
+func TestMatchingAvoidsRecentOpponents(t *testing.T) {
+	h, _ := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	h.Matchmaker.mu.Lock()
+	h.Matchmaker.recordOpponents([]*Client{a, b})
+	h.Matchmaker.mu.Unlock()
+
+	// The pair is held apart, so the newcomer splits them up.
+	c := newTestClient("p3")
+	for _, cl := range []*Client{a, b, c} {
+		h.Hub.Register(cl)
+	}
+	h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle)
+	h.Matchmaker.Enqueue(b, ModeDuel, FormatSingle)
+	if a.RoomID != "" || b.RoomID != "" {
+		t.Fatal("recent opponents were rematched inside the hold window")
+	}
+	h.Matchmaker.Enqueue(c, ModeDuel, FormatSingle)
+	if a.RoomID == "" || a.RoomID != c.RoomID {
+		t.Fatalf("oldest waiter not paired with the newcomer: a=%q c=%q", a.RoomID, c.RoomID)
+	}
+	if !b.InQueue {
+		t.Fatal("held-out recent opponent fell out of the queue")
+	}
+}
+
+func TestRecentOpponentsRematchWhenAlone(t *testing.T) {
+	h, _ := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	h.Matchmaker.mu.Lock()
+	h.Matchmaker.recordOpponents([]*Client{a, b})
+	h.Matchmaker.mu.Unlock()
+
+	for _, cl := range []*Client{a, b} {
+		h.Hub.Register(cl)
+	}
+	h.Matchmaker.Enqueue(a, ModeDuel, FormatSingle)
+	h.Matchmaker.Enqueue(b, ModeDuel, FormatSingle)
+	if a.RoomID != "" || b.RoomID != "" {
+		t.Fatal("recent opponents were rematched inside the hold window")
+	}
+
+	// Once the hold elapses with nobody else around, playing again beats
+	// not playing.
+	key := queueKey(ModeDuel, FormatSingle)
+	h.Matchmaker.mu.Lock()
+	for i := range h.Matchmaker.queues[key] {
+		h.Matchmaker.queues[key][i].since = time.Now().Add(-rematchHoldFor)
+	}
+	h.Matchmaker.matchDuels(key)
+	h.Matchmaker.mu.Unlock()
+	if a.RoomID == "" || a.RoomID != b.RoomID {
+		t.Fatalf("lone recent opponents were not rematched after the hold: a=%q b=%q", a.RoomID, b.RoomID)
+	}
+}
+
+func TestJoinQueueReportsExistingState(t *testing.T) {
+	h, _ := newTestHandler()
+	c := newTestClient("p1")
+	h.Hub.Register(c)
+	c.InQueue = true
+
+	h.handleMessage(c, frame(t, MsgJoinQueue, JoinQueuePayload{Name: "Alice"}))
+	ep := lastError(t, c)
+	if ep.Code != ErrCodeAlreadyInQueue {
+		t.Fatalf("code = %q, want %q", ep.Code, ErrCodeAlreadyInQueue)
+	}
+
+	// A refreshed frontend that re-joins mid-match gets pointed back at its
+	// running room.
+	c.InQueue = false
+	h.Hub.SetRoom(c, "room_test")
+	h.handleMessage(c, frame(t, MsgJoinQueue, JoinQueuePayload{Name: "Alice"}))
+	ep = lastError(t, c)
+	if ep.Code != ErrCodeAlreadyInMatch {
+		t.Fatalf("code = %q, want %q", ep.Code, ErrCodeAlreadyInMatch)
+	}
+	if ep.RoomID != "room_test" {
+		t.Fatalf("roomId = %q, want room_test", ep.RoomID)
+	}
+}
+
+// lastError drains a client's send buffer and returns the final ERROR
+// payload, failing the test if none was sent.
+func lastError(t *testing.T, c *Client) ErrorPayload {
+	t.Helper()
+	var ep ErrorPayload
+	found := false
+	for {
+		select {
+		case raw := <-c.Send:
+			var msg Message
+			json.Unmarshal(raw, &msg)
+			if msg.Type == MsgError {
+				json.Unmarshal(msg.Payload, &ep)
+				found = true
+			}
+		default:
+			if !found {
+				t.Fatal("no ERROR message sent")
+			}
+			return ep
+		}
+	}
+}
+
+// Forwarding headers must only be honored when the deployment says its proxy
+// sets them; otherwise they are client-controlled and would bypass IP bans.
+func TestRemoteIPProxyHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "/ws", nil)
+	r.RemoteAddr = "10.0.0.1:443"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	r.Header.Set("CF-IPCountry", "de")
+
+	if got := remoteIP(r); got != "10.0.0.1" {
+		t.Errorf("untrusted remoteIP = %q, want the socket peer 10.0.0.1", got)
+	}
+	if got := remoteCountry(r); got != "" {
+		t.Errorf("untrusted remoteCountry = %q, want empty", got)
+	}
+
+	trustProxyHeaders = true
+	defer func() { trustProxyHeaders = false }()
+	if got := remoteIP(r); got != "203.0.113.7" {
+		t.Errorf("trusted remoteIP = %q, want the first forwarded hop 203.0.113.7", got)
+	}
+	if got := remoteCountry(r); got != "DE" {
+		t.Errorf("trusted remoteCountry = %q, want DE", got)
+	}
+}
+
+// The registry is the protocol's extension point: a registered type routes
+// through dispatch with middleware applied, without touching handleMessage.
+func TestRegisterExtendsProtocol(t *testing.T) {
+	h, _ := newTestHandler()
+	var handled bool
+	h.Register("PING", func(_ context.Context, c *Client, _ Message) {
+		handled = true
+	}, h.inRoom)
+
+	c := newTestClient("p1")
+	h.handleMessage(c, frame(t, "PING", nil))
+	if handled {
+		t.Fatal("inRoom middleware did not gate the handler")
+	}
+	if types := drainTypes(c); len(types) != 1 || types[0] != MsgError {
+		t.Fatalf("messages = %v, want a single ERROR", types)
+	}
+
+	c.RoomID = "room_test"
+	h.handleMessage(c, frame(t, "PING", nil))
+	if !handled {
+		t.Fatal("registered handler never ran")
+	}
+}
+
+// An AFK player forfeits: the opponent gets the win and their score
+// persisted instead of being locked into a match that never resolves.
+func TestIdleWatchdogForfeitsSilentPlayer(t *testing.T) {
+	h, store := newTestHandler()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	a.Name, b.Name = "Alice", "Bert"
+	roomID := "room_test"
+	for _, c := range []*Client{a, b} {
+		h.Hub.Register(c)
+		h.Hub.SetRoom(c, roomID)
+		c.IsAlive = true
+	}
+	h.Matchmaker.matches[roomID] = newMatchMeta(roomID, 42, FormatSingle, []*Client{a, b})
+	h.Matchmaker.matches[roomID].startedAt = time.Now().Add(-time.Minute)
+	a.Score = 12
+
+	a.markActive()
+	b.mu.Lock()
+	b.lastActive = time.Now().Add(-2 * idleForfeitAfter)
+	b.mu.Unlock()
+	h.scanIdle()
+
+	if b.IsAlive {
+		t.Fatal("idle player still alive after the watchdog pass")
+	}
+	var over GameOverPayload
+	found := false
+	for {
+		var raw []byte
+		select {
+		case raw = <-a.Send:
+		default:
+			raw = nil
+		}
+		if raw == nil {
+			break
+		}
+		var msg Message
+		json.Unmarshal(raw, &msg)
+		if msg.Type == MsgGameOver {
+			json.Unmarshal(msg.Payload, &over)
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("active player never received GAME_OVER")
+	}
+	if over.WinnerID != "p1" || over.Reason != GameOverReasonIdleForfeit {
+		t.Fatalf("GAME_OVER = winner %q reason %q, want p1/idle_forfeit", over.WinnerID, over.Reason)
+	}
+	if len(store.Scores) != 1 || store.Scores[0].PlayerID != "p1" {
+		t.Fatalf("persisted scores = %+v, want only the active player's", store.Scores)
+	}
+	if got := store.StatsByPlayer["p2"].Losses; got != 1 {
+		t.Fatalf("idle player losses = %d, want 1", got)
+	}
+	if a.RoomID != "" || b.RoomID != "" {
+		t.Fatalf("rooms not cleared: %q %q", a.RoomID, b.RoomID)
+	}
+}
+
+func TestJoinChallengeRunsDailySeed(t *testing.T) {
+	h, store := newTestHandler()
+	c := newTestClient("guest_ch01")
+	h.Hub.Register(c)
+
+	h.handleMessage(c, frame(t, MsgJoinChallenge, JoinChallengePayload{Name: "Dana", PlayerID: "p9"}))
+
+	if c.RoomID == "" {
+		t.Fatal("challenge request did not create a room")
+	}
+	if c.ID != "p9" {
+		t.Fatalf("client ID = %q, want adopted identity p9", c.ID)
+	}
+	day, ok := h.Matchmaker.ChallengeDay(c.RoomID)
+	if !ok || day != game.ChallengeDay(time.Now()) {
+		t.Fatalf("challenge day = %q/%v, want today's", day, ok)
+	}
+	var start GameStartPayload
+	found := false
+	for len(c.Send) > 0 {
+		var msg Message
+		json.Unmarshal(<-c.Send, &msg)
+		if msg.Type == MsgGameStart {
+			json.Unmarshal(msg.Payload, &start)
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("challenge player never received GAME_START")
+	}
+	if start.Seed != game.DailySeed(day) {
+		t.Fatalf("seed = %d, want the day's shared seed %d", start.Seed, game.DailySeed(day))
+	}
+
+	// A challenge death ranks on the day's board, not the ranked leaderboard.
+	h.Validator.RegisterMatch(c.RoomID, start.Seed)
+	c.IsAlive = true
+	score, sig := signedScore(t, h, c.RoomID, 9)
+	c.Score = score
+	h.handleMessage(c, frame(t, MsgPlayerDied, PlayerDiedPayload{Score: score, Sig: sig}))
+	if len(store.Scores) != 0 {
+		t.Fatalf("challenge run persisted %d ranked scores, want 0", len(store.Scores))
+	}
+	best := store.ChallengeBests[day]["p9"]
+	if best.Score != score || best.PlayerName != "Dana" {
+		t.Fatalf("challenge best = %+v, want score %d by Dana", best, score)
+	}
+}
+
+func TestInvalidResumeTokenClosesWithAuthFailed(t *testing.T) {
+	h, _ := newTestHandler()
+	srv := httptest.NewServer(http.HandlerFunc(h.ServeWs))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(srv.URL, "http")+"?token=bogus", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("read error = %v, want a close frame", err)
+	}
+	if closeErr.Code != CloseAuthFailed {
+		t.Fatalf("close code = %d, want %d", closeErr.Code, CloseAuthFailed)
+	}
+	if closeErr.Text != "invalid or expired session token" {
+		t.Fatalf("close reason = %q", closeErr.Text)
+	}
+}
+
+func TestDailySeedStableAcrossDayAndDiffersBetweenDays(t *testing.T) {
+	if game.DailySeed("2026-08-27") != game.DailySeed("2026-08-27") {
+		t.Fatal("same day produced different seeds")
+	}
+	if game.DailySeed("2026-08-27") == game.DailySeed("2026-08-28") {
+		t.Fatal("consecutive days produced the same seed")
+	}
+	if game.DailySeed("2026-08-27") < 0 {
+		t.Fatal("daily seed is negative")
+	}
+}
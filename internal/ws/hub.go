@@ -0,0 +1,374 @@
+package ws
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// hubShardCount is how many shards the client map is split into. Power of
+// two so the modulo compiles to a mask.
+const hubShardCount = 32
+
+// Room broadcast worker pool tuning. Jobs are sharded to workers by room ID,
+// so one room's messages always flow through the same worker and stay in
+// order.
+const (
+	// broadcastWorkers is the number of fan-out goroutines.
+	broadcastWorkers = 8
+	// broadcastQueueSize bounds each worker's job queue.
+	broadcastQueueSize = 256
+)
+
+// roomBroadcast is one queued fan-out: a message for everyone in a room,
+// minus the client it came from.
+type roomBroadcast struct {
+	roomID  string
+	except  string
+	msgType string
+	payload interface{}
+}
+
+// clientShard is one slice of the sharded client map.
+type clientShard struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// Hub keeps track of every connected client and an index of which clients
+// are in which room. The client map is sharded by client ID hash so the
+// register/lookup hot path of thousands of concurrent connections doesn't
+// serialize on a single lock; the room, spectator and per-IP indexes see far
+// less traffic and stay under one mutex.
+type Hub struct {
+	shards [hubShardCount]clientShard
+	mu     sync.RWMutex
+	rooms  map[string]map[string]*Client
+	// connsByIP counts open connections per remote IP so one host can't
+	// hog the server with hundreds of sockets.
+	connsByIP map[string]int
+	// roomActive remembers when each room last saw a client message, so
+	// the reaper can spot rooms everyone silently vanished from.
+	roomActive map[string]time.Time
+	// spectators indexes watchers by the room they are watching. Spectators
+	// are kept out of the room index so match resolution never sees them.
+	spectators map[string]map[string]*Client
+	// broadcasts are the per-worker queues behind BroadcastToRoom.
+	broadcasts [broadcastWorkers]chan roomBroadcast
+}
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	h := &Hub{
+		rooms:      make(map[string]map[string]*Client),
+		connsByIP:  make(map[string]int),
+		roomActive: make(map[string]time.Time),
+		spectators: make(map[string]map[string]*Client),
+	}
+	for i := range h.shards {
+		h.shards[i].clients = make(map[string]*Client)
+	}
+	for i := range h.broadcasts {
+		h.broadcasts[i] = make(chan roomBroadcast, broadcastQueueSize)
+		go h.broadcastWorker(h.broadcasts[i])
+	}
+	return h
+}
+
+// shard returns the client shard responsible for id.
+func (h *Hub) shard(id string) *clientShard {
+	f := fnv.New32a()
+	f.Write([]byte(id))
+	return &h.shards[f.Sum32()%hubShardCount]
+}
+
+// AcquireIP counts one more connection from ip, refusing when the limit is
+// already reached. A limit of zero or less disables the cap. Callers that
+// get true must pair it with ReleaseIP when the connection closes.
+func (h *Hub) AcquireIP(ip string, limit int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if limit > 0 && h.connsByIP[ip] >= limit {
+		return false
+	}
+	h.connsByIP[ip]++
+	return true
+}
+
+// ReleaseIP undoes one AcquireIP.
+func (h *Hub) ReleaseIP(ip string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.connsByIP[ip] <= 1 {
+		delete(h.connsByIP, ip)
+		return
+	}
+	h.connsByIP[ip]--
+}
+
+// Register adds a client to the hub.
+func (h *Hub) Register(c *Client) {
+	s := h.shard(c.ID)
+	s.mu.Lock()
+	s.clients[c.ID] = c
+	s.mu.Unlock()
+}
+
+// Unregister removes a client from the hub and the room index.
+func (h *Hub) Unregister(c *Client) {
+	s := h.shard(c.ID)
+	s.mu.Lock()
+	delete(s.clients, c.ID)
+	s.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeFromRoom(c)
+	h.removeSpectator(c)
+}
+
+// AddSpectator registers a client as a watcher of roomID, moving it out of
+// any room it previously spectated.
+func (h *Hub) AddSpectator(roomID string, c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeSpectator(c)
+	c.Spectating = roomID
+	watchers, ok := h.spectators[roomID]
+	if !ok {
+		watchers = make(map[string]*Client)
+		h.spectators[roomID] = watchers
+	}
+	watchers[c.ID] = c
+}
+
+// RemoveSpectator drops a client from whatever room it was watching.
+func (h *Hub) RemoveSpectator(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeSpectator(c)
+}
+
+// removeSpectator drops a client from the spectator index. Caller must hold
+// h.mu.
+func (h *Hub) removeSpectator(c *Client) {
+	if c.Spectating == "" {
+		return
+	}
+	if watchers, ok := h.spectators[c.Spectating]; ok {
+		delete(watchers, c.ID)
+		if len(watchers) == 0 {
+			delete(h.spectators, c.Spectating)
+		}
+	}
+	c.Spectating = ""
+}
+
+// SpectatorsInRoom returns every client currently watching roomID.
+func (h *Hub) SpectatorsInRoom(roomID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	watchers := h.spectators[roomID]
+	clients := make([]*Client, 0, len(watchers))
+	for _, c := range watchers {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// SpectatorCount returns how many clients are watching roomID.
+func (h *Hub) SpectatorCount(roomID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.spectators[roomID])
+}
+
+// SetRoom moves a client into a room (or out of rooms entirely with ""),
+// keeping the room index in sync. All room assignment must go through here.
+func (h *Hub) SetRoom(c *Client, roomID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeFromRoom(c)
+	c.RoomID = roomID
+	if roomID == "" {
+		return
+	}
+	room, ok := h.rooms[roomID]
+	if !ok {
+		room = make(map[string]*Client)
+		h.rooms[roomID] = room
+	}
+	room[c.ID] = c
+	h.roomActive[roomID] = time.Now()
+}
+
+// Touch records activity for a room; the reaper leaves touched rooms alone.
+func (h *Hub) Touch(roomID string) {
+	h.mu.Lock()
+	h.roomActive[roomID] = time.Now()
+	h.mu.Unlock()
+}
+
+// StaleRooms returns the rooms that saw no activity for at least age.
+func (h *Hub) StaleRooms(age time.Duration) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	cutoff := time.Now().Add(-age)
+	var stale []string
+	for roomID, last := range h.roomActive {
+		if last.Before(cutoff) {
+			stale = append(stale, roomID)
+		}
+	}
+	return stale
+}
+
+// removeFromRoom drops a client from the room index. Caller must hold h.mu.
+func (h *Hub) removeFromRoom(c *Client) {
+	if c.RoomID == "" {
+		return
+	}
+	if room, ok := h.rooms[c.RoomID]; ok {
+		delete(room, c.ID)
+		if len(room) == 0 {
+			delete(h.rooms, c.RoomID)
+			delete(h.roomActive, c.RoomID)
+		}
+	}
+}
+
+// GetClient looks a client up by ID.
+func (h *Hub) GetClient(id string) (*Client, bool) {
+	s := h.shard(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.clients[id]
+	return c, ok
+}
+
+// GetClientsInRoom returns every client currently assigned to roomID. It
+// reads the room index, so the cost scales with the room size rather than
+// the total number of connections.
+func (h *Hub) GetClientsInRoom(roomID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	room := h.rooms[roomID]
+	clients := make([]*Client, 0, len(room))
+	for _, c := range room {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// RoomIDs returns the ID of every room with at least one connected client.
+func (h *Hub) RoomIDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	ids := make([]string, 0, len(h.rooms))
+	for roomID := range h.rooms {
+		ids = append(ids, roomID)
+	}
+	return ids
+}
+
+// RoomCount returns the number of rooms with at least one connected client.
+func (h *Hub) RoomCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms)
+}
+
+// Clients returns a snapshot of every connected client, assembled shard by
+// shard without ever holding more than one lock.
+func (h *Hub) Clients() []*Client {
+	clients := make([]*Client, 0, h.Count())
+	for i := range h.shards {
+		s := &h.shards[i]
+		s.mu.RLock()
+		for _, c := range s.clients {
+			clients = append(clients, c)
+		}
+		s.mu.RUnlock()
+	}
+	return clients
+}
+
+// Broadcast sends one message to every connected client, e.g. an operator
+// notice ahead of a maintenance window.
+func (h *Hub) Broadcast(msgType string, payload interface{}) {
+	for _, c := range h.Clients() {
+		c.SendJSON(msgType, payload)
+	}
+}
+
+// CloseAll sends every connected client a close frame with the given code
+// and reason, e.g. CloseServerShutdown before a graceful restart, so clients
+// can tell an intentional disconnect from a network drop.
+func (h *Hub) CloseAll(code int, reason string) {
+	for _, c := range h.Clients() {
+		c.CloseWith(code, reason)
+	}
+}
+
+// BroadcastToRoom queues one message for every client in roomID except the
+// one named by except (empty sends to all). Fan-out — the room index read
+// plus one encode per recipient — runs on a worker goroutine, so a sender's
+// read loop never pays for its opponents' traffic. When the room's worker
+// queue is full the message is delivered inline instead of dropped.
+func (h *Hub) BroadcastToRoom(roomID, except, msgType string, payload interface{}) {
+	if roomID == "" {
+		return
+	}
+	job := roomBroadcast{roomID: roomID, except: except, msgType: msgType, payload: payload}
+	f := fnv.New32a()
+	f.Write([]byte(roomID))
+	select {
+	case h.broadcasts[f.Sum32()%broadcastWorkers] <- job:
+	default:
+		h.deliver(job)
+	}
+}
+
+// broadcastWorker drains one queue of room fan-outs.
+func (h *Hub) broadcastWorker(jobs chan roomBroadcast) {
+	for job := range jobs {
+		h.deliver(job)
+	}
+}
+
+// deliver fans one broadcast out to its room: the players, then whoever is
+// watching. The workers delivering these jobs are shared by every room, so a
+// panic on one client's send path — however it slips past the channel close
+// discipline — is contained here instead of taking down all matches on the
+// instance.
+func (h *Hub) deliver(job roomBroadcast) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic delivering room broadcast", "room_id", job.roomID, "msg_type", job.msgType, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	for _, c := range h.GetClientsInRoom(job.roomID) {
+		if c.ID != job.except {
+			c.SendJSON(job.msgType, job.payload)
+		}
+	}
+	for _, c := range h.SpectatorsInRoom(job.roomID) {
+		if c.ID != job.except {
+			c.SendJSON(job.msgType, job.payload)
+		}
+	}
+}
+
+// Count returns the number of connected clients.
+func (h *Hub) Count() int {
+	n := 0
+	for i := range h.shards {
+		s := &h.shards[i]
+		s.mu.RLock()
+		n += len(s.clients)
+		s.mu.RUnlock()
+	}
+	return n
+}
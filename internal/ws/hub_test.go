@@ -0,0 +1,191 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBroadcastToRoomSkipsSender(t *testing.T) {
+	h := NewHub()
+	a, b := newTestClient("p1"), newTestClient("p2")
+	h.Register(a)
+	h.Register(b)
+	h.SetRoom(a, "room_test")
+	h.SetRoom(b, "room_test")
+
+	h.BroadcastToRoom("room_test", "p1", MsgNotice, NoticePayload{Message: "hi"})
+
+	select {
+	case raw := <-b.Send:
+		var msg Message
+		json.Unmarshal(raw, &msg)
+		if msg.Type != MsgNotice {
+			t.Fatalf("opponent received %q, want %q", msg.Type, MsgNotice)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("opponent never received the broadcast")
+	}
+	select {
+	case <-a.Send:
+		t.Fatal("sender received its own broadcast")
+	default:
+	}
+}
+
+func TestHubRoomIndex(t *testing.T) {
+	h := NewHub()
+	a := &Client{ID: "a"}
+	b := &Client{ID: "b"}
+	c := &Client{ID: "c"}
+	for _, cl := range []*Client{a, b, c} {
+		h.Register(cl)
+	}
+
+	h.SetRoom(a, "room_1")
+	h.SetRoom(b, "room_1")
+	h.SetRoom(c, "room_2")
+
+	if got := len(h.GetClientsInRoom("room_1")); got != 2 {
+		t.Fatalf("room_1 has %d clients, want 2", got)
+	}
+	if got := h.RoomCount(); got != 2 {
+		t.Fatalf("RoomCount() = %d, want 2", got)
+	}
+
+	// Moving a client out removes it from the index.
+	h.SetRoom(b, "")
+	if got := len(h.GetClientsInRoom("room_1")); got != 1 {
+		t.Fatalf("room_1 has %d clients after removal, want 1", got)
+	}
+
+	// Unregistering the last member drops the room entirely.
+	h.Unregister(a)
+	if got := h.RoomCount(); got != 1 {
+		t.Fatalf("RoomCount() = %d after unregister, want 1", got)
+	}
+	if c.RoomID != "room_2" {
+		t.Fatalf("unrelated client lost its room: %q", c.RoomID)
+	}
+}
+
+// populateHub fills a hub with n clients paired into rooms of two.
+func populateHub(h *Hub, n int) {
+	for i := 0; i < n; i++ {
+		c := &Client{ID: fmt.Sprintf("client_%d", i)}
+		h.Register(c)
+		h.SetRoom(c, fmt.Sprintf("room_%d", i/2))
+	}
+}
+
+// BenchmarkGetClientsInRoom measures a room lookup with 10k connected
+// clients using the room index.
+func TestHubIPLimit(t *testing.T) {
+	h := NewHub()
+	for i := 0; i < 3; i++ {
+		if !h.AcquireIP("10.0.0.1", 3) {
+			t.Fatalf("connection %d refused under the limit", i+1)
+		}
+	}
+	if h.AcquireIP("10.0.0.1", 3) {
+		t.Fatal("fourth connection allowed past the limit")
+	}
+	if !h.AcquireIP("10.0.0.2", 3) {
+		t.Fatal("other IP refused")
+	}
+	h.ReleaseIP("10.0.0.1")
+	if !h.AcquireIP("10.0.0.1", 3) {
+		t.Fatal("connection refused after a release freed a slot")
+	}
+	if !h.AcquireIP("10.0.0.1", 0) {
+		t.Fatal("limit 0 should disable the cap")
+	}
+}
+
+func BenchmarkGetClientsInRoom(b *testing.B) {
+	h := NewHub()
+	populateHub(h, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if got := h.GetClientsInRoom("room_2500"); len(got) != 2 {
+			b.Fatalf("got %d clients", len(got))
+		}
+	}
+}
+
+// BenchmarkGetClientsInRoomScan is the pre-index behaviour — a full scan of
+// the client map — kept as a baseline for comparison.
+func BenchmarkGetClientsInRoomScan(b *testing.B) {
+	h := NewHub()
+	populateHub(h, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var clients []*Client
+		for _, c := range h.Clients() {
+			if c.RoomID == "room_2500" {
+				clients = append(clients, c)
+			}
+		}
+		if len(clients) != 2 {
+			b.Fatalf("got %d clients", len(clients))
+		}
+	}
+}
+
+// BenchmarkGetClientParallel measures concurrent client lookups with 10k
+// connections, the hot path the sharded map is for.
+func BenchmarkGetClientParallel(b *testing.B) {
+	h := NewHub()
+	populateHub(h, 10000)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			id := fmt.Sprintf("client_%d", i%10000)
+			if _, ok := h.GetClient(id); !ok {
+				b.Fatalf("client %s missing", id)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkRegisterUnregisterParallel measures connection churn against a hub
+// already holding 10k clients.
+func BenchmarkRegisterUnregisterParallel(b *testing.B) {
+	h := NewHub()
+	populateHub(h, 10000)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c := &Client{ID: fmt.Sprintf("churn_%p_%d", &i, i)}
+			h.Register(c)
+			h.Unregister(c)
+			i++
+		}
+	})
+}
+
+func TestBroadcastReachesSpectators(t *testing.T) {
+	h := NewHub()
+	a, s := newTestClient("p1"), newTestClient("watcher")
+	h.Register(a)
+	h.Register(s)
+	h.SetRoom(a, "room_test")
+	h.AddSpectator("room_test", s)
+
+	h.BroadcastToRoom("room_test", "p1", MsgOpponentUpdate, OpponentUpdatePayload{PlayerID: "p1", Score: 7})
+
+	select {
+	case raw := <-s.Send:
+		var msg Message
+		json.Unmarshal(raw, &msg)
+		if msg.Type != MsgOpponentUpdate {
+			t.Fatalf("spectator received %q, want %q", msg.Type, MsgOpponentUpdate)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("spectator never received the room broadcast")
+	}
+}
@@ -0,0 +1,98 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Idle watchdog tuning. An AFK player would otherwise lock their opponent
+// into a match that can never resolve until the room-level reaper fires.
+var (
+	// idleForfeitAfter is how long a mid-match player may go without
+	// sending anything before their match is forfeited; 0 disables the
+	// watchdog. During an actual run inputs and score updates flow every
+	// few seconds, so a healthy client never comes close.
+	idleForfeitAfter = 60 * time.Second
+	// idleCheckInterval is how often the watchdog scans for idle players.
+	idleCheckInterval = 5 * time.Second
+)
+
+// idleLoop periodically forfeits matches against players who went silent.
+func (h *Handler) idleLoop() {
+	for range time.Tick(idleCheckInterval) {
+		if idleForfeitAfter <= 0 {
+			continue
+		}
+		h.scanIdle()
+	}
+}
+
+// scanIdle runs one watchdog pass. Only rooms where play actually began
+// count: the READY/countdown phase has its own cancellation path, and a
+// dead player waiting out the round is allowed to sit quietly.
+func (h *Handler) scanIdle() {
+	for _, c := range h.Hub.Clients() {
+		if c.IsBot || c.RoomID == "" || !c.alive() {
+			continue
+		}
+		if h.Matchmaker.MatchDuration(c.RoomID) == 0 {
+			continue
+		}
+		if idle := c.idleFor(); idle > idleForfeitAfter {
+			slog.Warn("forfeiting idle player", "client_id", c.ID, "room_id", c.RoomID, "idle", idle.Round(time.Second))
+			h.forfeitIdle(c)
+		}
+	}
+}
+
+// forfeitIdle resolves a match against a connected player who stopped
+// sending anything mid-run. The idle player loses and is told so; everyone
+// else wins, keeps their score, and has it persisted — the same shape as a
+// disconnect forfeit, except the loser's connection stays up.
+func (h *Handler) forfeitIdle(c *Client) {
+	roomID := c.RoomID
+	if roomID == "" {
+		return
+	}
+	scores := map[string]int{c.ID: c.currentScore()}
+	winnerID := ""
+	vsBot := false
+	var survivors []*Client
+	for _, s := range h.Hub.GetClientsInRoom(roomID) {
+		if s == c {
+			continue
+		}
+		survivors = append(survivors, s)
+		scores[s.ID] = s.currentScore()
+		winnerID = s.ID
+		if s.IsBot {
+			vsBot = true
+		}
+	}
+	ctx := context.Background()
+	duration := h.Matchmaker.MatchDuration(roomID)
+	practice := h.Matchmaker.Practice(roomID)
+	room, ok := h.Matchmaker.ClearMatch(roomID)
+	if !ok {
+		// Another finish path resolved the room between the idle scan and
+		// this forfeit; nothing left to do.
+		return
+	}
+	c.markDead()
+	h.notifyMatchEnd(roomID, append(survivors, c), scores, winnerID, "", GameOverReasonIdleForfeit, duration)
+	for _, s := range append(survivors, c) {
+		s.SendJSON(MsgGameOver, GameOverPayload{
+			WinnerID: winnerID, Scores: scores, Reason: GameOverReasonIdleForfeit,
+			Summary: h.matchSummary(ctx, s, scores, duration, nil, practice),
+		})
+		if s != c && !s.IsBot {
+			h.persistScore(ctx, roomID, s)
+		}
+		if !practice {
+			h.recordStats(ctx, s, winnerID, vsBot)
+		}
+		h.cleanupClient(s)
+	}
+	h.cleanupRoom(roomID, room)
+}
@@ -0,0 +1,55 @@
+package ws
+
+import "time"
+
+// latencyUpdateInterval is how often per-room latency readings are pushed to
+// the players in each room.
+const latencyUpdateInterval = 15 * time.Second
+
+// Latency-aware pairing: duel candidates whose round-trip times are worlds
+// apart play a visibly unfair game, so the allowed gap starts tight and
+// widens the longer both have waited — a laggy player still gets a match,
+// just not while better-matched alternatives exist.
+const (
+	baseLatencyGap      = 100
+	latencyGapPerSecond = 100
+)
+
+// latencyWindow returns how large a round-trip gap (in milliseconds) an
+// entry is currently willing to accept.
+func latencyWindow(e queueEntry) int {
+	return baseLatencyGap + int(time.Since(e.since).Seconds())*latencyGapPerSecond
+}
+
+// LatencyUpdatePayload carries every room member's measured round-trip time
+// in milliseconds, keyed by player ID.
+type LatencyUpdatePayload struct {
+	Latencies map[string]int `json:"latencies"`
+}
+
+// latencyLoop periodically pushes LATENCY_UPDATE into every active room so
+// players see each other's connection quality during the match.
+func (h *Handler) latencyLoop() {
+	for range time.Tick(latencyUpdateInterval) {
+		h.broadcastLatencies()
+	}
+}
+
+// broadcastLatencies sends one LATENCY_UPDATE per room with at least one
+// human player.
+func (h *Handler) broadcastLatencies() {
+	for _, roomID := range h.Hub.RoomIDs() {
+		clients := h.Hub.GetClientsInRoom(roomID)
+		latencies := make(map[string]int, len(clients))
+		for _, c := range clients {
+			if c.IsBot {
+				continue
+			}
+			latencies[c.ID] = c.Latency()
+		}
+		if len(latencies) == 0 {
+			continue
+		}
+		h.Hub.BroadcastToRoom(roomID, "", MsgLatencyUpdate, LatencyUpdatePayload{Latencies: latencies})
+	}
+}
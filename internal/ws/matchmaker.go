@@ -0,0 +1,614 @@
+package ws
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/events"
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Queue modes players can ask for.
+const (
+	ModeDuel   = "duel"
+	ModeRoyale = "royale"
+	ModeTeams  = "teams"
+)
+
+// teamRoomSize is how many players fill a 2v2 team room.
+const teamRoomSize = 4
+
+// Team names used in team mode.
+const (
+	TeamA = "A"
+	TeamB = "B"
+)
+
+// Royale room size bounds; DefaultRoyaleSize is used unless configured.
+const (
+	MinRoyaleSize     = 2
+	MaxRoyaleSize     = 8
+	DefaultRoyaleSize = 4
+)
+
+// Skill-based pairing: two duel players are matched once their rating gap
+// fits inside both players' windows, which widen the longer they wait so
+// nobody queues forever.
+const (
+	baseRatingWindow      = 100
+	ratingWindowPerSecond = 100
+	// matchSweepInterval is how often waiting queues are re-checked for
+	// pairs whose windows have widened enough.
+	matchSweepInterval = time.Second
+)
+
+// queueEntry is one waiting player plus when they started waiting.
+type queueEntry struct {
+	client *Client
+	since  time.Time
+}
+
+// Matchmaker groups queued players into rooms: rating-matched pairs for
+// duels, larger FIFO rooms for battle royale.
+type Matchmaker struct {
+	mu         sync.Mutex
+	hub        *Hub
+	queues     map[string][]queueEntry
+	validator  *game.Validator
+	sessions   *SessionStore
+	loops      *game.LoopManager
+	royaleSize int
+	// pending tracks rooms waiting on the READY handshake before their
+	// simulation starts.
+	pending map[string]*pendingStart
+	// matches tracks per-room format and round wins for multi-round games.
+	matches map[string]*matchMeta
+	// botHost drives fallback bot opponents; set by NewHandler.
+	botHost *Handler
+	// queueStore mirrors queue membership into Redis so it survives
+	// restarts; nil when Redis is not configured.
+	queueStore *QueueStore
+	// recent remembers each player's last opponents so small populations
+	// don't replay the same pairing back to back; opponentStore mirrors it
+	// into Redis, nil when Redis is not configured.
+	recent        map[string]recentOpponents
+	opponentStore *OpponentStore
+	// verifications archives finished matches' seeds for the fairness
+	// endpoint, evicting oldest-first via verifyOrder.
+	verifications map[string]MatchVerification
+	verifyOrder   []string
+}
+
+// SetQueueStore enables Redis-backed queue persistence.
+func (m *Matchmaker) SetQueueStore(qs *QueueStore) {
+	m.mu.Lock()
+	m.queueStore = qs
+	m.mu.Unlock()
+}
+
+// NewMatchmaker returns a matchmaker that registers new matches with the
+// given validator, issues reconnect sessions from the given store, and runs
+// a simulation loop per room through the given manager.
+func NewMatchmaker(hub *Hub, validator *game.Validator, sessions *SessionStore, loops *game.LoopManager) *Matchmaker {
+	m := &Matchmaker{
+		hub:        hub,
+		queues:     make(map[string][]queueEntry),
+		validator:  validator,
+		sessions:   sessions,
+		loops:      loops,
+		royaleSize: DefaultRoyaleSize,
+		pending:    make(map[string]*pendingStart),
+		matches:    make(map[string]*matchMeta),
+		recent:     make(map[string]recentOpponents),
+
+		verifications: make(map[string]MatchVerification),
+	}
+	go m.sweep()
+	return m
+}
+
+// sweep periodically retries duel pairing as waiting players' rating
+// windows widen.
+func (m *Matchmaker) sweep() {
+	for range time.Tick(matchSweepInterval) {
+		m.mu.Lock()
+		for key := range m.queues {
+			if strings.HasPrefix(key, ModeDuel) {
+				m.matchDuels(key)
+			}
+		}
+		m.pruneRecent()
+		m.mu.Unlock()
+	}
+}
+
+// queueKey names the queue for a mode and format combination; players only
+// match with others who asked for the same kind of game.
+func queueKey(mode, format string) string {
+	return mode + "|" + format
+}
+
+// SetRoyaleSize configures how many players fill a royale room, clamped to
+// the supported bounds.
+func (m *Matchmaker) SetRoyaleSize(n int) {
+	if n < MinRoyaleSize {
+		n = MinRoyaleSize
+	}
+	if n > MaxRoyaleSize {
+		n = MaxRoyaleSize
+	}
+	m.mu.Lock()
+	m.royaleSize = n
+	m.mu.Unlock()
+}
+
+// roomSize returns how many players a room of the given mode needs.
+func (m *Matchmaker) roomSize(mode string) int {
+	switch mode {
+	case ModeRoyale:
+		return m.royaleSize
+	case ModeTeams:
+		return teamRoomSize
+	default:
+		return 2
+	}
+}
+
+// Enqueue adds a client to the queue for a mode and format and creates a
+// match as soon as enough compatible players are waiting. Unknown modes fall
+// back to duel, unknown formats to single round. It reports whether the
+// client was accepted; a client already queued or in a room is refused.
+func (m *Matchmaker) Enqueue(c *Client, mode, format string) bool {
+	if mode != ModeRoyale && mode != ModeTeams {
+		mode = ModeDuel
+	}
+	if format != FormatBestOf3 && format != FormatTimed {
+		format = FormatSingle
+	}
+	// Team games are single rounds; the round machinery has no notion of
+	// per-team wins.
+	if mode == ModeTeams {
+		format = FormatSingle
+	}
+	key := queueKey(mode, format)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The handler's InQueue test runs without this lock, so two racing
+	// JOIN_QUEUEs can both get here; only the state transition under the
+	// lock is authoritative.
+	if c.InQueue || c.RoomID != "" {
+		return false
+	}
+	if !m.dedupe(c) {
+		return false
+	}
+	c.InQueue = true
+	since := time.Now()
+	restored := false
+	if pk, psince, ok := m.queueStore.Lookup(c.ID); ok && pk == key {
+		// The player was waiting in this queue before a restart wiped it;
+		// restoring the original enqueue time restores their priority.
+		since = psince
+		restored = true
+	}
+	m.loadRecent(c)
+	m.queues[key] = append(m.queues[key], queueEntry{client: c, since: since})
+	m.queueStore.Save(c.ID, key, since)
+	if restored {
+		slog.Info("queue entry restored after restart", "client_id", c.ID, "mode", mode, "format", format, "waited", time.Since(since))
+		c.SendJSON(MsgQueueRestored, QueueRestoredPayload{Position: len(m.queues[key])})
+	}
+	slog.Info("client joined queue", "client_id", c.ID, "name", c.Name, "rating", c.Rating, "mode", mode, "format", format, "waiting", len(m.queues[key]))
+	if mode == ModeRoyale || mode == ModeTeams {
+		size := m.roomSize(mode)
+		m.pruneQueue(key)
+		for len(m.queues[key]) >= size {
+			entries := m.queues[key][:size:size]
+			m.queues[key] = m.queues[key][size:]
+			players := make([]*Client, len(entries))
+			for i, e := range entries {
+				players[i] = e.client
+			}
+			if mode == ModeTeams {
+				assignTeams(players)
+			}
+			m.createMatch(players, format, nil)
+		}
+		return true
+	}
+	m.matchDuels(key)
+	// If the player is still waiting once the fallback window elapses, a
+	// server-controlled bot steps in as their opponent.
+	if c.InQueue && m.botHost != nil && BotFallbackAfter > 0 {
+		time.AfterFunc(BotFallbackAfter, func() { m.spawnBotMatch(c, key) })
+	}
+	return true
+}
+
+// dedupe evicts a stale queue entry left under the client's ID by a dropped
+// connection, so the returning player isn't locked out of matchmaking. It
+// reports false when a live client is already waiting under that ID. Caller
+// must hold m.mu.
+func (m *Matchmaker) dedupe(c *Client) bool {
+	for key, queue := range m.queues {
+		for i, e := range queue {
+			if e.client.ID != c.ID {
+				continue
+			}
+			if m.liveClient(e.client) {
+				return false
+			}
+			m.queues[key] = append(queue[:i], queue[i+1:]...)
+			e.client.InQueue = false
+			return true
+		}
+	}
+	return true
+}
+
+// liveClient reports whether a queued client is still connected under its
+// own identity and not already placed in a room. Bots have no hub-registered
+// connection of their own to check. Caller must hold m.mu.
+func (m *Matchmaker) liveClient(c *Client) bool {
+	if c.RoomID != "" {
+		return false
+	}
+	if c.IsBot {
+		return true
+	}
+	cur, ok := m.hub.GetClient(c.ID)
+	return ok && cur == c
+}
+
+// pruneQueue drops entries whose client disconnected or already landed in a
+// room, returning the compacted queue. Caller must hold m.mu.
+func (m *Matchmaker) pruneQueue(key string) []queueEntry {
+	queue := m.queues[key]
+	live := queue[:0]
+	for _, e := range queue {
+		if m.liveClient(e.client) {
+			live = append(live, e)
+			continue
+		}
+		e.client.InQueue = false
+		m.queueStore.Remove(e.client.ID)
+		slog.Info("dead queue entry pruned", "client_id", e.client.ID, "queue", key)
+	}
+	m.queues[key] = live
+	return live
+}
+
+// spawnBotMatch pairs a still-queued player with a fresh bot. No-op if the
+// player got a real match or left the queue in the meantime.
+func (m *Matchmaker) spawnBotMatch(c *Client, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	queue := m.queues[key]
+	found := false
+	for i, e := range queue {
+		if e.client == c {
+			m.queues[key] = append(queue[:i], queue[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	c.InQueue = false
+	// The waiting client may have dropped between the timer firing and this
+	// lock; a bot match against an empty socket helps nobody.
+	if !m.liveClient(c) {
+		m.queueStore.Remove(c.ID)
+		return
+	}
+
+	bot := newBotClient(c.Rating, BotMedium)
+	m.hub.Register(bot)
+	go m.botHost.runBot(bot)
+	_, format := splitQueueKey(key)
+	slog.Info("no opponent found, matching with bot", "client_id", c.ID, "bot_id", bot.ID)
+	m.createMatch([]*Client{c, bot}, format, nil)
+}
+
+// CreatePracticeMatch rooms a player with a bot of the chosen difficulty
+// right away, without going through the public queue. The room is marked as
+// practice, so no rating, stats, or leaderboard writes come out of it.
+func (m *Matchmaker) CreatePracticeMatch(c *Client, difficulty string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bot := newBotClient(c.Rating, difficulty)
+	m.hub.Register(bot)
+	go m.botHost.runBot(bot)
+	m.createMatch([]*Client{c, bot}, FormatSingle, nil)
+	if meta, ok := m.matches[c.RoomID]; ok {
+		meta.practice = true
+	}
+	slog.Info("practice match created", "client_id", c.ID, "bot_id", bot.ID, "difficulty", bot.botTier)
+}
+
+// Practice reports whether roomID is an unranked practice room.
+func (m *Matchmaker) Practice(roomID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	return ok && meta.practice
+}
+
+// CreateChallengeMatch rooms a player with a bot on the given challenge
+// day's shared seed, so every attempt that day runs the same obstacle
+// layout. Challenge rooms count as practice — no rating, stats, or ranked
+// leaderboard writes — but the final score ranks on the day's challenge
+// board.
+func (m *Matchmaker) CreateChallengeMatch(c *Client, day string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bot := newBotClient(c.Rating, "")
+	m.hub.Register(bot)
+	go m.botHost.runBot(bot)
+	m.createMatchSeeded([]*Client{c, bot}, FormatSingle, nil, game.DailySeed(day))
+	if meta, ok := m.matches[c.RoomID]; ok {
+		meta.practice = true
+		meta.challengeDay = day
+	}
+	slog.Info("challenge match created", "client_id", c.ID, "bot_id", bot.ID, "day", day)
+}
+
+// ChallengeDay reports the challenge day of a daily-challenge room; ok is
+// false for every other room.
+func (m *Matchmaker) ChallengeDay(roomID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok || meta.challengeDay == "" {
+		return "", false
+	}
+	return meta.challengeDay, true
+}
+
+// assignTeams splits four players into two sides, pairing the strongest
+// with the weakest so the expected combined ratings are close.
+func assignTeams(players []*Client) {
+	sort.Slice(players, func(i, j int) bool { return players[i].Rating > players[j].Rating })
+	for i, c := range players {
+		if i == 0 || i == len(players)-1 {
+			c.Team = TeamA
+		} else {
+			c.Team = TeamB
+		}
+	}
+}
+
+// CreateDirectMatch rooms two players immediately, e.g. for an accepted
+// friend challenge, without going through the public queue. settings carries
+// the challenger's custom match parameters, nil for defaults.
+func (m *Matchmaker) CreateDirectMatch(a, b *Client, format string, settings *MatchSettings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.createMatch([]*Client{a, b}, format, settings)
+}
+
+// ratingWindow returns how far from its own rating an entry is currently
+// willing to match.
+func ratingWindow(e queueEntry) int {
+	return baseRatingWindow + int(time.Since(e.since).Seconds())*ratingWindowPerSecond
+}
+
+// matchDuels pairs players in one duel queue whose rating gap fits both
+// windows, skipping entries whose client has since vanished. Caller must
+// hold m.mu.
+func (m *Matchmaker) matchDuels(key string) {
+	queue := m.pruneQueue(key)
+	for i := 0; i < len(queue); i++ {
+		for j := i + 1; j < len(queue); j++ {
+			a, b := queue[i], queue[j]
+			// Never pair an identity with itself, however the entries got in.
+			if a.client.ID == b.client.ID {
+				continue
+			}
+			// A fresh rematch is dull; hold recent opponents apart for a
+			// while so anyone else who shows up can split them. The hold is
+			// time-bounded like the rating windows, so a lone pair still
+			// gets to play.
+			if m.playedRecently(a.client.ID, b.client.ID) &&
+				(time.Since(a.since) < rematchHoldFor || time.Since(b.since) < rematchHoldFor) {
+				continue
+			}
+			gap := a.client.Rating - b.client.Rating
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap > ratingWindow(a) || gap > ratingWindow(b) {
+				continue
+			}
+			// A 20ms and a 400ms player make a miserable pairing; hold out
+			// for closer alternatives until the waits say otherwise. Zero
+			// readings (no pong yet) are treated as unknown, not perfect.
+			if la, lb := a.client.Latency(), b.client.Latency(); la > 0 && lb > 0 {
+				lgap := la - lb
+				if lgap < 0 {
+					lgap = -lgap
+				}
+				if lgap > latencyWindow(a) || lgap > latencyWindow(b) {
+					continue
+				}
+			}
+			queue = append(queue[:j], queue[j+1:]...)
+			queue = append(queue[:i], queue[i+1:]...)
+			m.queues[key] = queue
+			_, format := splitQueueKey(key)
+			m.createMatch([]*Client{a.client, b.client}, format, nil)
+			i--
+			break
+		}
+	}
+	m.queues[key] = queue
+}
+
+// QueueLength reports how many players are waiting across every queue.
+func (m *Matchmaker) QueueLength() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	waiting := 0
+	for _, entries := range m.queues {
+		waiting += len(entries)
+	}
+	return waiting
+}
+
+// ScoreKey returns the key signing a tracked room's score submissions.
+func (m *Matchmaker) ScoreKey(roomID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok {
+		return "", false
+	}
+	return meta.scoreKey, true
+}
+
+// Room returns the first-class room record of a tracked match.
+func (m *Matchmaker) Room(roomID string) (*game.Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok {
+		return nil, false
+	}
+	return meta.room, true
+}
+
+// Remove takes a client out of whichever queue holds it, e.g. when it
+// cancels matchmaking or disconnects while waiting. It reports whether the
+// client was queued.
+func (m *Matchmaker) Remove(c *Client) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for mode, queue := range m.queues {
+		for i, queued := range queue {
+			if queued.client.ID == c.ID {
+				m.queues[mode] = append(queue[:i], queue[i+1:]...)
+				c.InQueue = false
+				m.queueStore.Remove(c.ID)
+				slog.Info("client left queue", "client_id", c.ID, "mode", mode, "waiting", len(m.queues[mode]))
+				return true
+			}
+		}
+	}
+	c.InQueue = false
+	return false
+}
+
+// createMatch sets up a room for a group of players and notifies them all.
+// settings is nil for standard queue matches. Caller must hold m.mu.
+func (m *Matchmaker) createMatch(players []*Client, format string, settings *MatchSettings) {
+	m.createMatchSeeded(players, format, settings, randomSeed())
+}
+
+// createMatchSeeded is createMatch with the first round's seed chosen by the
+// caller, for rooms that must run a predetermined layout such as the daily
+// challenge. Caller must hold m.mu.
+func (m *Matchmaker) createMatchSeeded(players []*Client, format string, settings *MatchSettings, seed int64) {
+	roomID := "room_" + randomHex(8)
+	m.matches[roomID] = newMatchMeta(roomID, seed, format, players)
+	m.recordOpponents(players)
+
+	ids := make([]string, 0, len(players))
+	infos := make([]PlayerInfo, 0, len(players))
+	for _, c := range players {
+		c.InQueue = false
+		m.queueStore.Remove(c.ID)
+		m.hub.SetRoom(c, roomID)
+		start := 0
+		if settings != nil {
+			// Handicapped players start with their head-start points.
+			start = settings.Handicaps[c.ID]
+		}
+		c.resetRun(start)
+		c.SessionToken = m.sessions.Issue(c)
+		if m.botHost != nil {
+			m.botHost.bus.JoinRoom(roomID, c.ID)
+			m.botHost.bus.SetPlayerState(roomID, c.ID, start, true)
+		}
+		ids = append(ids, c.ID)
+		infos = append(infos, PlayerInfo{ID: c.ID, Name: c.Name, Rating: c.Rating, Team: c.Team, Skin: c.Skin, LatencyMs: c.Latency()})
+	}
+	// The simulation only starts after every player sends READY and the
+	// countdown runs, so nobody begins before their opponent has loaded.
+	// Registering with the validator is deferred too, so its match clock
+	// starts when play actually does.
+	m.initReady(roomID, players, func() {
+		m.markStarted(roomID)
+		m.validator.RegisterMatch(roomID, seed)
+		if settings != nil {
+			m.validator.ApplyMatchSettings(roomID, settings.SpeedMultiplier, settings.Handicaps)
+		}
+		m.loops.Start(roomID, seed, ids, func(s game.Snapshot) {
+			for _, c := range players {
+				c.SendJSON(MsgStateSnapshot, s)
+			}
+		})
+		if m.botHost != nil {
+			go m.botHost.broadcastPowerUps(roomID, seed)
+			go m.botHost.streamObstacleSchedule(roomID, seed)
+		}
+	})
+	slog.Info("match created", "room_id", roomID, "players", len(players), "seed", seed, "format", format)
+	if m.botHost != nil {
+		m.botHost.events.Publish(events.MatchCreated{RoomID: roomID, Players: eventPlayers(players), Format: format, Seed: seed})
+	}
+
+	for _, c := range players {
+		payload := GameStartPayload{
+			RoomID:         roomID,
+			Seed:           seed,
+			Format:         format,
+			Players:        infos,
+			ReconnectToken: c.SessionToken,
+			ServerTime:     time.Now().UnixMilli(),
+			Settings:       settings,
+			ScoreKey:       m.matches[roomID].scoreKey,
+		}
+		// Keep the single-opponent field populated for two-player rooms so
+		// older frontends keep working; v2 clients read the players list.
+		if len(players) == 2 && !c.protoAtLeast(ProtocolV2) {
+			for _, other := range infos {
+				if other.ID != c.ID {
+					payload.Opponent = other
+				}
+			}
+		}
+		c.SendJSON(MsgGameStart, payload)
+	}
+	// The first window of the obstacle schedule follows GAME_START so every
+	// client has it before the countdown ends.
+	if sched, ok := obstacleSchedulePayload(game.NewTimeline(seed), 0); ok {
+		for _, c := range players {
+			c.SendJSON(MsgObstacleSchedule, sched)
+		}
+	}
+}
+
+// randomHex returns n random bytes hex-encoded.
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return hex.EncodeToString(buf)
+}
+
+// randomSeed returns a non-negative random int64 for the match seed.
+func randomSeed() int64 {
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		panic(err)
+	}
+	return n.Int64()
+}
@@ -0,0 +1,451 @@
+package ws
+
+import "encoding/json"
+
+// Message is the envelope for every frame exchanged over the WebSocket.
+type Message struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Seq is an optional client-assigned sequence number, strictly
+	// increasing per connection. Clients that stamp it get dedup and
+	// ordering: stale numbers are rejected and processed ones are ACKed,
+	// which makes resend-after-reconnect safe.
+	Seq int64 `json:"seq,omitempty"`
+}
+
+// Message types sent by clients.
+const (
+	MsgJoinQueue       = "JOIN_QUEUE"
+	MsgCancelQueue     = "CANCEL_QUEUE"
+	MsgUpdateScore     = "UPDATE_SCORE"
+	MsgPlayerDied      = "PLAYER_DIED"
+	MsgPlayerInput     = "PLAYER_INPUT"
+	MsgChat            = "CHAT"
+	MsgEmote           = "EMOTE"
+	MsgMute            = "MUTE"
+	MsgReady           = "READY"
+	MsgPositionUpdate  = "POSITION_UPDATE"
+	MsgChallengeFriend = "CHALLENGE_FRIEND"
+	MsgAcceptInvite    = "ACCEPT_INVITE"
+	// MsgPractice starts a solo match against a bot immediately, outside the
+	// public queue.
+	MsgPractice = "PRACTICE"
+	// MsgJoinChallenge starts a daily-challenge attempt: a bot match on the
+	// day's shared seed whose final score ranks on the challenge board.
+	MsgJoinChallenge = "JOIN_CHALLENGE"
+	// MsgPowerUpCollected claims a pickup inbound; the server relays it to
+	// the rest of the room with the sender and kind filled in.
+	MsgPowerUpCollected = "POWERUP_COLLECTED"
+	// MsgTimeSync is both the request and the reply of the clock handshake.
+	MsgTimeSync = "TIME_SYNC"
+	// MsgSpectate asks to watch a live room; MsgStopSpectate leaves it.
+	MsgSpectate     = "SPECTATE"
+	MsgStopSpectate = "STOP_SPECTATE"
+	// MsgSpectatorChat is the spectator-only chat channel, kept separate so
+	// spectator banter never reaches the players mid-match.
+	MsgSpectatorChat = "SPECTATOR_CHAT"
+	// MsgReportPlayer files a cheating report against another player in the
+	// sender's room, queued for admin review.
+	MsgReportPlayer = "REPORT_PLAYER"
+)
+
+// Message types sent by the server.
+const (
+	MsgQueueCancelled       = "QUEUE_CANCELLED"
+	MsgQueueRestored        = "QUEUE_RESTORED"
+	MsgGameStart            = "GAME_START"
+	MsgOpponentUpdate       = "OPPONENT_UPDATE"
+	MsgTeamUpdate           = "TEAM_UPDATE"
+	MsgOpponentPosition     = "OPPONENT_POSITION"
+	MsgOpponentDied         = "OPPONENT_DIED"
+	MsgOpponentDisconnected = "OPPONENT_DISCONNECTED"
+	MsgOpponentReconnected  = "OPPONENT_RECONNECTED"
+	// MsgSessionResumed confirms a reconnect and carries the replacement
+	// token; reconnect tokens are single-use, so the presented one is dead.
+	MsgSessionResumed = "SESSION_RESUMED"
+	MsgStateSnapshot  = "STATE_SNAPSHOT"
+	MsgRoundOver      = "ROUND_OVER"
+	MsgCountdown      = "COUNTDOWN"
+	MsgMatchCancelled = "MATCH_CANCELLED"
+	MsgGameOver       = "GAME_OVER"
+	MsgInvite         = "INVITE"
+	MsgPowerUpSpawn   = "POWERUP_SPAWN"
+	// MsgObstacleSchedule carries one batch of the authoritative obstacle
+	// schedule, so clients don't each derive it from the seed and drift.
+	MsgObstacleSchedule = "OBSTACLE_SCHEDULE"
+	MsgSpectateOK       = "SPECTATE_OK"
+	// MsgAck confirms one seq-stamped client message was processed.
+	MsgAck = "ACK"
+	// MsgNotice carries operator announcements, e.g. a maintenance banner.
+	MsgNotice = "NOTICE"
+	// MsgLatencyUpdate carries every room member's measured round-trip time.
+	MsgLatencyUpdate = "LATENCY_UPDATE"
+	MsgError         = "ERROR"
+)
+
+// WebSocket close codes for server-initiated disconnects, from the 4000
+// range RFC 6455 reserves for applications. The code tells clients why the
+// connection ended and whether reconnecting makes sense: rate-limited and
+// shutdown closes are worth retrying after a pause, the others are not.
+const (
+	// CloseAuthFailed rejects a reconnect whose session token is invalid or
+	// expired; the client should connect fresh, without the token.
+	CloseAuthFailed = 4001
+	// CloseBanned ends the connection of a player banned mid-session.
+	CloseBanned = 4002
+	// CloseRateLimited ends a connection for sustained message flooding.
+	CloseRateLimited = 4003
+	// CloseServerShutdown marks a graceful restart; the match state is
+	// checkpointed, so reconnecting shortly is expected to work.
+	CloseServerShutdown = 4004
+	// CloseKicked ends a connection removed by a moderator.
+	CloseKicked = 4005
+)
+
+// Match formats players can queue for.
+const (
+	FormatSingle  = "single"
+	FormatBestOf3 = "bo3"
+	// FormatTimed is a single round with a fixed clock: when time runs out
+	// the higher score wins.
+	FormatTimed = "timed"
+)
+
+// JoinQueuePayload is sent by a client that wants to be matched. Mode picks
+// the queue: "duel" (default) or "royale". Format picks the match length:
+// "single" (default), "bo3", or "timed".
+type JoinQueuePayload struct {
+	Name   string `json:"name"`
+	Mode   string `json:"mode,omitempty"`
+	Format string `json:"format,omitempty"`
+	// PlayerID is an optional stable identity (e.g. kept in localStorage)
+	// so ratings and stats survive across connections.
+	PlayerID string `json:"playerId,omitempty"`
+}
+
+// PracticePayload asks for an immediate unranked match against a bot.
+// Difficulty is "easy", "medium", or "hard"; empty plays as medium.
+type PracticePayload struct {
+	Name       string `json:"name"`
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// JoinChallengePayload asks for a daily-challenge attempt. PlayerID is the
+// client's stable identity, like in JOIN_QUEUE: the challenge board keeps one
+// best score per player per day, so attempts need to land under the same ID.
+type JoinChallengePayload struct {
+	Name     string `json:"name"`
+	PlayerID string `json:"playerId,omitempty"`
+}
+
+// ReportPlayerPayload accuses another player in the sender's room of
+// cheating. The report lands in the admin review queue together with the
+// room's score audit trail.
+type ReportPlayerPayload struct {
+	PlayerID string `json:"playerId"`
+	Reason   string `json:"reason"`
+}
+
+// QueueRestoredPayload tells a rejoining client that its pre-restart queue
+// position was recovered. Position is 1-based within the queue.
+type QueueRestoredPayload struct {
+	Position int `json:"position"`
+}
+
+// UpdateScorePayload carries a client's current score. Sig is the hex
+// HMAC-SHA256 over "roomId|score|seq" under the room's score key.
+type UpdateScorePayload struct {
+	Score int    `json:"score"`
+	Sig   string `json:"sig"`
+}
+
+// PlayerDiedPayload carries the final score of a player that hit an
+// obstacle, signed like UpdateScorePayload.
+type PlayerDiedPayload struct {
+	Score int    `json:"score"`
+	Sig   string `json:"sig"`
+}
+
+// PlayerInputPayload carries one jump/duck action into the server-side
+// simulation. Action is one of "jump", "duck", "unduck". T is the client's
+// clock at the moment of the action, in Unix milliseconds; the server logs it
+// with the input so an offline verifier can replay the run against the
+// seed-derived obstacle schedule. Old clients omit it.
+type PlayerInputPayload struct {
+	Action string `json:"action"`
+	T      int64  `json:"t,omitempty"`
+}
+
+// PlayerInfo describes a participant to the other side of a match.
+type PlayerInfo struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Rating int    `json:"rating,omitempty"`
+	// Team is set in team mode: "A" or "B".
+	Team string `json:"team,omitempty"`
+	// Skin is the cosmetic dino skin the player has unlocked through
+	// achievements; empty means the default dino.
+	Skin string `json:"skin,omitempty"`
+	// LatencyMs is the player's measured round-trip time at match start;
+	// zero when no ping/pong cycle has completed yet.
+	LatencyMs int `json:"latencyMs,omitempty"`
+}
+
+// GameStartPayload tells both clients their match is starting.
+type GameStartPayload struct {
+	RoomID string `json:"roomId"`
+	Seed   int64  `json:"seed"`
+	// Format is "single", "bo3", or "timed".
+	Format string `json:"format"`
+	// Players lists every participant in the room, including the recipient.
+	Players []PlayerInfo `json:"players"`
+	// Opponent is only set in two-player rooms, for older frontends.
+	Opponent PlayerInfo `json:"opponent,omitempty"`
+	// ReconnectToken lets the client resume this match if its connection
+	// drops; present it as ?token= on the next /ws connect.
+	ReconnectToken string `json:"reconnectToken"`
+	// ServerTime is the server clock at match creation in unix
+	// milliseconds, an extra reference point next to TIME_SYNC.
+	ServerTime int64 `json:"serverTime"`
+	// Settings carries the custom parameters of a private match; nil for
+	// standard queue matches.
+	Settings *MatchSettings `json:"settings,omitempty"`
+	// ScoreKey signs this room's score submissions: UPDATE_SCORE and
+	// PLAYER_DIED carry an HMAC-SHA256 over "roomId|score|seq" under it.
+	ScoreKey string `json:"scoreKey"`
+}
+
+// SessionResumedPayload is sent to a client that successfully resumed a
+// parked session. ReconnectToken replaces the single-use token the client
+// just presented.
+type SessionResumedPayload struct {
+	ReconnectToken string `json:"reconnectToken"`
+}
+
+// PositionUpdatePayload carries a player's in-world position so opponents
+// can render them as a ghost dino. Inbound PlayerID is ignored; the server
+// stamps the sender's ID before relaying as OPPONENT_POSITION.
+type PositionUpdatePayload struct {
+	PlayerID string `json:"playerId,omitempty"`
+	// X is the player's world x-offset, Y their current jump height.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	// Ducking is set while the player is ducked under a pterodactyl.
+	Ducking bool `json:"ducking"`
+}
+
+// CountdownPayload is one tick of the pre-match countdown. StartAt is the
+// server timestamp (unix milliseconds) when the run begins, so both clients
+// start simulating at the same moment regardless of message latency.
+type CountdownPayload struct {
+	Count   int   `json:"count"`
+	StartAt int64 `json:"startAt"`
+}
+
+// MatchCancelledPayload tells the remaining players their room was torn down
+// before the match started, e.g. because an opponent never readied up.
+type MatchCancelledPayload struct {
+	Reason string `json:"reason"`
+}
+
+// OpponentUpdatePayload relays the opponent's score to a client.
+type OpponentUpdatePayload struct {
+	PlayerID string `json:"playerId"`
+	Score    int    `json:"score"`
+}
+
+// TeamUpdatePayload replaces OPPONENT_UPDATE in team mode: the player's own
+// new score plus the combined running total of each team.
+type TeamUpdatePayload struct {
+	PlayerID string         `json:"playerId"`
+	Score    int            `json:"score"`
+	Teams    map[string]int `json:"teams"`
+}
+
+// Reasons carried by GameOverPayload.
+const (
+	GameOverReasonFinished     = "finished"
+	GameOverReasonOpponentLeft = "opponent_disconnected"
+	GameOverReasonTimeUp       = "time_up"
+	// GameOverReasonStale marks rooms torn down by the stale-room reaper.
+	GameOverReasonStale = "stale"
+	// GameOverReasonIdleForfeit marks a match resolved because one player
+	// went silent mid-run; the active player wins.
+	GameOverReasonIdleForfeit = "idle_forfeit"
+	// GameOverReasonServerRestart marks matches cut short by a restart; the
+	// scores come from the room's last checkpoint.
+	GameOverReasonServerRestart = "server_restart"
+)
+
+// RoundOverPayload announces the result of one round of a multi-round match.
+// NextSeed is the fresh seed for the following round; clients send READY
+// again once they have prepared it.
+type RoundOverPayload struct {
+	Round    int            `json:"round"`
+	WinnerID string         `json:"winnerId"`
+	Scores   map[string]int `json:"scores"`
+	// Wins maps player IDs to round wins so far.
+	Wins     map[string]int `json:"wins"`
+	NextSeed int64          `json:"nextSeed"`
+}
+
+// GameOverPayload announces the result of a finished match. WinnerID is
+// empty when the match ended in a draw.
+type GameOverPayload struct {
+	WinnerID string         `json:"winnerId"`
+	Scores   map[string]int `json:"scores"`
+	Reason   string         `json:"reason"`
+	// WinningTeam names the victorious side in team mode; empty for a
+	// drawn team game and for every other mode.
+	WinningTeam string `json:"winningTeam,omitempty"`
+	// Summary is the recipient's server-computed result block; nil on
+	// teardown paths with nothing meaningful to report (stale rooms,
+	// server restarts).
+	Summary *MatchSummary `json:"summary,omitempty"`
+}
+
+// MatchSummary is computed server-side and attached to GAME_OVER so the
+// frontend result screen needs no extra round trips.
+type MatchSummary struct {
+	// DurationMs is how long the match ran.
+	DurationMs int64 `json:"durationMs"`
+	// MaxScoreRate is the fastest scoring pace in the room, in points per
+	// second.
+	MaxScoreRate float64 `json:"maxScoreRate"`
+	// PersonalBest is set when the recipient beat their stored best score.
+	PersonalBest bool `json:"personalBest"`
+	// RatingDelta is the recipient's Elo change; zero in unrated games.
+	RatingDelta int `json:"ratingDelta"`
+	// Rank is the global leaderboard position the recipient's score lands
+	// at; zero when not computed (bots, practice rooms).
+	Rank int `json:"rank,omitempty"`
+}
+
+// ChatPayload carries a chat message; inbound only Text is set, outbound the
+// sender fields are filled in by the server.
+type ChatPayload struct {
+	PlayerID string `json:"playerId,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Text     string `json:"text"`
+}
+
+// EmotePayload carries one of the predefined emotes.
+type EmotePayload struct {
+	PlayerID string `json:"playerId,omitempty"`
+	Emote    string `json:"emote"`
+}
+
+// MutePayload toggles whether the sender wants to receive opponent chat.
+type MutePayload struct {
+	Muted bool `json:"muted"`
+}
+
+// TimeSyncPayload carries the clock handshake: the client sends its clock
+// in ClientTime, the server echoes it and fills ServerTime, both in unix
+// milliseconds. From the echo the client derives RTT and clock offset.
+type TimeSyncPayload struct {
+	ClientTime int64 `json:"clientTime"`
+	ServerTime int64 `json:"serverTime,omitempty"`
+}
+
+// MatchSettings are the custom parameters a private-match creator may set.
+// Zero values mean "default"; both clients and the server simulation read
+// the same settings from GAME_START so nobody plays a different game.
+type MatchSettings struct {
+	// SpeedMultiplier scales the game speed; 1.0 is the standard ramp.
+	SpeedMultiplier float64 `json:"speedMultiplier,omitempty"`
+	// ObstacleDensity scales how tightly obstacles spawn; 1.0 is standard.
+	ObstacleDensity float64 `json:"obstacleDensity,omitempty"`
+	// Handicaps maps player IDs to head-start points added to their score.
+	Handicaps map[string]int `json:"handicaps,omitempty"`
+}
+
+// ChallengeFriendPayload asks the server to invite an online friend to a
+// direct match, optionally with custom match settings chosen by the
+// challenger.
+type ChallengeFriendPayload struct {
+	FriendID string         `json:"friendId"`
+	Settings *MatchSettings `json:"settings,omitempty"`
+}
+
+// InvitePayload is pushed to the challenged friend.
+type InvitePayload struct {
+	FromID   string `json:"fromId"`
+	FromName string `json:"fromName"`
+}
+
+// ObstacleSpawn is one obstacle of the schedule. At is the spawn time in
+// milliseconds since the run started; Gap is the milliseconds until the next
+// spawn, zero on the last obstacle of the timeline.
+type ObstacleSpawn struct {
+	Kind string `json:"kind"`
+	At   int64  `json:"at"`
+	Gap  int64  `json:"gap,omitempty"`
+}
+
+// ObstacleSchedulePayload is one OBSTACLE_SCHEDULE batch covering the run
+// window [From, Until) in milliseconds. The first batch rides along with
+// GAME_START; long matches get further batches before the previous window
+// runs out.
+type ObstacleSchedulePayload struct {
+	From      int64           `json:"from"`
+	Until     int64           `json:"until"`
+	Obstacles []ObstacleSpawn `json:"obstacles"`
+}
+
+// PowerUpSpawnPayload announces one power-up from the match's deterministic
+// schedule. At is the spawn time in milliseconds since the run started.
+type PowerUpSpawnPayload struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	At   int64  `json:"at"`
+}
+
+// PowerUpCollectedPayload reports a picked-up power-up. Inbound only ID is
+// set; the server stamps the sender and kind before relaying to the room.
+type PowerUpCollectedPayload struct {
+	PlayerID string `json:"playerId,omitempty"`
+	ID       int    `json:"id"`
+	Kind     string `json:"kind,omitempty"`
+}
+
+// SpectatePayload names the room a client wants to watch.
+type SpectatePayload struct {
+	RoomID string `json:"roomId"`
+}
+
+// SpectateOKPayload confirms a SPECTATE and hands the new watcher the
+// current participants so it can render the match immediately.
+type SpectateOKPayload struct {
+	RoomID  string       `json:"roomId"`
+	Players []PlayerInfo `json:"players"`
+	// Scores maps player IDs to their score at join time.
+	Scores map[string]int `json:"scores"`
+}
+
+// AcceptInvitePayload accepts a pending challenge from the named player.
+type AcceptInvitePayload struct {
+	FromID string `json:"fromId"`
+}
+
+// AckPayload echoes the seq of a processed client message.
+type AckPayload struct {
+	Seq int64 `json:"seq"`
+}
+
+// NoticePayload is a free-text operator announcement shown to players.
+type NoticePayload struct {
+	Message string `json:"message"`
+}
+
+// ErrorPayload is sent back when the server rejects a client message. Code
+// is one of the ErrCode constants; Message is for humans. LastSeq is the
+// last client seq the server processed, so seq-stamping clients can resync
+// after a rejection. RoomID rides along on ALREADY_IN_MATCH so a refreshed
+// frontend can find its way back to its running game.
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	LastSeq int64  `json:"lastSeq,omitempty"`
+	RoomID  string `json:"roomId,omitempty"`
+}
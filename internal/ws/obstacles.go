@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Obstacle schedule streaming. The server is the source of truth for the
+// obstacle layout: clients render the batches it pushes instead of deriving
+// the timeline from the seed with their own PRNG, so differing
+// implementations can never drift apart mid-match.
+const (
+	// obstacleWindow is how much of the timeline one batch covers.
+	obstacleWindow = 2 * time.Minute
+	// obstacleLead is how long before the current window runs out the next
+	// batch is pushed.
+	obstacleLead = 10 * time.Second
+)
+
+// obstacleSchedulePayload builds the batch covering [from, from+obstacleWindow)
+// of a seed's timeline. ok is false when the timeline has nothing left there.
+func obstacleSchedulePayload(tl *game.Timeline, from time.Duration) (ObstacleSchedulePayload, bool) {
+	until := from + obstacleWindow
+	window := tl.Window(from, until)
+	if len(window) == 0 {
+		return ObstacleSchedulePayload{}, false
+	}
+	spawns := make([]ObstacleSpawn, len(window))
+	for i, o := range window {
+		spawns[i] = ObstacleSpawn{
+			Kind: o.Type.String(),
+			At:   o.SpawnAt.Milliseconds(),
+			Gap:  o.Gap.Milliseconds(),
+		}
+	}
+	return ObstacleSchedulePayload{
+		From:      from.Milliseconds(),
+		Until:     until.Milliseconds(),
+		Obstacles: spawns,
+	}, true
+}
+
+// streamObstacleSchedule pushes the remaining schedule batches for one round.
+// The first window was already sent with GAME_START (or after ROUND_OVER);
+// this goroutine delivers each following window shortly before the previous
+// one runs out, and exits once the round it was started for is no longer the
+// one registered with the validator.
+func (h *Handler) streamObstacleSchedule(roomID string, seed int64) {
+	tl := game.NewTimeline(seed)
+	start := time.Now()
+	for from := obstacleWindow; ; from += obstacleWindow {
+		time.Sleep(time.Until(start.Add(from - obstacleLead)))
+		if s, ok := h.Validator.MatchSeed(roomID); !ok || s != seed {
+			return
+		}
+		payload, ok := obstacleSchedulePayload(tl, from)
+		if !ok {
+			return
+		}
+		for _, c := range h.Hub.GetClientsInRoom(roomID) {
+			c.SendJSON(MsgObstacleSchedule, payload)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package ws
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Recent-opponent tracking: small player populations run into the same
+// opponent over and over, so the matchmaker remembers who each player just
+// faced and holds those pairs apart while anyone else is waiting.
+const (
+	// recentOpponentCount is how many past opponents are remembered per
+	// player.
+	recentOpponentCount = 5
+	// recentOpponentTTL is how long a pairing counts as recent.
+	recentOpponentTTL = 30 * time.Minute
+	// rematchHoldFor is how long a recent pair is held apart in the queue,
+	// giving other waiters a chance to split them up. After the hold a
+	// rematch beats not playing at all.
+	rematchHoldFor = 15 * time.Second
+)
+
+// recentOpponents is one player's remembered opponents, newest first, with
+// when the list was last touched so stale entries can expire.
+type recentOpponents struct {
+	ids []string
+	at  time.Time
+}
+
+// OpponentStore mirrors recent-opponent lists into Redis so they survive a
+// restart, the same arrangement as QueueStore. A nil store (Redis not
+// configured) is valid and remembers nothing.
+type OpponentStore struct {
+	rdb redis.UniversalClient
+}
+
+// NewOpponentStore wraps a Redis client; rdb may be nil, which keeps the
+// recent-opponent memory process-local.
+func NewOpponentStore(rdb redis.UniversalClient) *OpponentStore {
+	if rdb == nil {
+		return nil
+	}
+	return &OpponentStore{rdb: rdb}
+}
+
+// recentOpponentKey is the per-player Redis key holding their opponent list.
+func recentOpponentKey(playerID string) string {
+	return "mm:recent:" + playerID
+}
+
+// Save replaces a player's persisted opponent list.
+func (o *OpponentStore) Save(playerID string, ids []string) {
+	if o == nil || len(ids) == 0 {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	pipe := o.rdb.TxPipeline()
+	pipe.Del(ctx, recentOpponentKey(playerID))
+	pipe.RPush(ctx, recentOpponentKey(playerID), ids)
+	pipe.Expire(ctx, recentOpponentKey(playerID), recentOpponentTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("persist recent opponents failed", "player_id", playerID, "error", err)
+	}
+}
+
+// Lookup returns a player's persisted opponent list, newest first, or nil.
+func (o *OpponentStore) Lookup(playerID string) []string {
+	if o == nil {
+		return nil
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	ids, err := o.rdb.LRange(ctx, recentOpponentKey(playerID), 0, recentOpponentCount-1).Result()
+	if err != nil {
+		if err != redis.Nil {
+			slog.Error("read recent opponents failed", "player_id", playerID, "error", err)
+		}
+		return nil
+	}
+	return ids
+}
+
+// SetOpponentStore enables Redis-backed persistence of recent opponents.
+func (m *Matchmaker) SetOpponentStore(os *OpponentStore) {
+	m.mu.Lock()
+	m.opponentStore = os
+	m.mu.Unlock()
+}
+
+// recordOpponents remembers who just got roomed with whom. Bots don't count:
+// a rematch against the house is nobody's complaint. Caller must hold m.mu.
+func (m *Matchmaker) recordOpponents(players []*Client) {
+	now := time.Now()
+	for _, c := range players {
+		if c.IsBot {
+			continue
+		}
+		ids := make([]string, 0, recentOpponentCount)
+		for _, o := range players {
+			if o == c || o.IsBot {
+				continue
+			}
+			ids = append(ids, o.ID)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		for _, id := range m.recent[c.ID].ids {
+			if len(ids) >= recentOpponentCount {
+				break
+			}
+			if !containsID(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		m.recent[c.ID] = recentOpponents{ids: ids, at: now}
+		m.opponentStore.Save(c.ID, ids)
+	}
+}
+
+// loadRecent restores a player's opponent list from Redis on their first
+// enqueue after a restart. Caller must hold m.mu.
+func (m *Matchmaker) loadRecent(c *Client) {
+	if _, ok := m.recent[c.ID]; ok {
+		return
+	}
+	if ids := m.opponentStore.Lookup(c.ID); len(ids) > 0 {
+		m.recent[c.ID] = recentOpponents{ids: ids, at: time.Now()}
+	}
+}
+
+// playedRecently reports whether either player remembers the other as a
+// recent opponent. Caller must hold m.mu.
+func (m *Matchmaker) playedRecently(aID, bID string) bool {
+	return containsID(m.recent[aID].ids, bID) || containsID(m.recent[bID].ids, aID)
+}
+
+// pruneRecent forgets opponent lists older than the TTL, matching the expiry
+// of their Redis mirrors. Caller must hold m.mu.
+func (m *Matchmaker) pruneRecent() {
+	cutoff := time.Now().Add(-recentOpponentTTL)
+	for id, r := range m.recent {
+		if r.at.Before(cutoff) {
+			delete(m.recent, id)
+		}
+	}
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
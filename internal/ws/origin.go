@@ -0,0 +1,32 @@
+package ws
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// allowedOrigins is the Origin allowlist enforced by the upgrader. The
+// default "*" is the dev-mode escape hatch: any origin is accepted.
+var allowedOrigins = []string{"*"}
+
+// originAllowed reports whether the request's Origin header may open a
+// websocket. Requests without an Origin header (non-browser clients) are
+// always accepted; browsers are held to the allowlist.
+func originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	// Same-host requests are fine even when the allowlist doesn't mention
+	// the deployment's own URL.
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package ws
+
+import "time"
+
+// positionRelayInterval caps how often one player's position is relayed to
+// the room. Updates arriving faster than this are coalesced: only the newest
+// one is forwarded when the window reopens.
+var positionRelayInterval = 100 * time.Millisecond
+
+// relayPosition forwards a POSITION_UPDATE to the sender's roommates,
+// rate-limited per client. Dropped intermediate positions are fine — ghosts
+// only ever need the latest one.
+func (h *Handler) relayPosition(c *Client, p PositionUpdatePayload) {
+	p.PlayerID = c.ID
+	now := time.Now()
+
+	c.mu.Lock()
+	if c.posPending == nil && now.Sub(c.lastPos) >= positionRelayInterval {
+		c.lastPos = now
+		c.mu.Unlock()
+		h.notifyOpponent(c, MsgOpponentPosition, p)
+		return
+	}
+	// Inside the window: remember only the newest position and schedule one
+	// flush when the window reopens.
+	schedule := c.posPending == nil
+	c.posPending = &p
+	wait := positionRelayInterval - now.Sub(c.lastPos)
+	c.mu.Unlock()
+
+	if schedule {
+		time.AfterFunc(wait, func() { h.flushPosition(c) })
+	}
+}
+
+// flushPosition sends the coalesced position once the relay window reopens.
+func (h *Handler) flushPosition(c *Client) {
+	c.mu.Lock()
+	p := c.posPending
+	c.posPending = nil
+	c.lastPos = time.Now()
+	roomID := c.RoomID
+	c.mu.Unlock()
+	if p == nil || roomID == "" {
+		return
+	}
+	h.notifyOpponent(c, MsgOpponentPosition, *p)
+}
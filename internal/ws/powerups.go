@@ -0,0 +1,58 @@
+package ws
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// broadcastPowerUps replays a round's deterministic power-up schedule as
+// POWERUP_SPAWN messages to everyone in the room. Runs in its own goroutine
+// per round and exits once the round it was started for is no longer the one
+// registered with the validator.
+func (h *Handler) broadcastPowerUps(roomID string, seed int64) {
+	start := time.Now()
+	for _, p := range game.PowerUpSchedule(seed) {
+		time.Sleep(time.Until(start.Add(p.At)))
+		if s, ok := h.Validator.MatchSeed(roomID); !ok || s != seed {
+			return
+		}
+		payload := PowerUpSpawnPayload{ID: p.ID, Kind: p.Kind, At: p.At.Milliseconds()}
+		for _, c := range h.Hub.GetClientsInRoom(roomID) {
+			c.SendJSON(MsgPowerUpSpawn, payload)
+		}
+	}
+}
+
+// handlePowerUpCollected checks a claimed pickup against the room's spawn
+// schedule, applies the double-score effect, and relays the pickup to the
+// rest of the room.
+func (h *Handler) handlePowerUpCollected(c *Client, p PowerUpCollectedPayload) {
+	if c.RoomID == "" {
+		h.sendError(c, ErrCodeNotInRoom, "not in a match")
+		return
+	}
+	if !c.alive() {
+		return
+	}
+	kind, err := h.Validator.CollectPowerUp(c.RoomID, c.ID, p.ID)
+	if err != nil {
+		slog.Warn("power-up claim rejected", "client_id", c.ID, "room_id", c.RoomID, "power_up_id", p.ID, "error", err)
+		h.sendError(c, ErrCodePowerUpRejected, "power-up claim rejected")
+		return
+	}
+	if kind == game.PowerUpDoubleScore {
+		c.startBoost(game.PowerUpEffectDuration)
+	}
+	h.notifyOpponent(c, MsgPowerUpCollected, PowerUpCollectedPayload{PlayerID: c.ID, ID: p.ID, Kind: kind})
+}
+
+// allowedScoreJump is the per-update score delta cap for a client, doubled
+// while a double-score power-up is active.
+func allowedScoreJump(c *Client) int {
+	if c.boosted() {
+		return 2 * maxScoreJump
+	}
+	return maxScoreJump
+}
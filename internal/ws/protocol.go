@@ -0,0 +1,61 @@
+package ws
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Wire protocol versions, negotiated through WebSocket subprotocols: a client
+// offers the versions it speaks ("dino.v1", "dino.v2", ...) on the handshake
+// and the server answers with the highest one it supports. Payload shapes may
+// change between versions; handlers branch on Client.Proto, so a breaking
+// change ships as a new version without stranding old frontends.
+//
+// Connections that offer no dino.* subprotocol — every frontend built before
+// versioning existed — speak v1.
+const (
+	// ProtocolV1 is the original wire format.
+	ProtocolV1 = 1
+	// ProtocolV2 drops the legacy single-opponent field from GAME_START;
+	// clients read the players list instead.
+	ProtocolV2 = 2
+	// ProtocolLatest is the newest version this server speaks.
+	ProtocolLatest = ProtocolV2
+)
+
+// protocolPrefix namespaces our subprotocols, as in "dino.v2".
+const protocolPrefix = "dino.v"
+
+// negotiateProtocol picks the highest mutually supported version among the
+// handshake's subprotocol offers. The returned name is echoed to the client
+// in the upgrade response; it is empty when nothing was negotiated, which
+// means v1 without a subprotocol header.
+func negotiateProtocol(r *http.Request) (version int, name string) {
+	best := 0
+	for _, proto := range websocket.Subprotocols(r) {
+		v, err := strconv.Atoi(strings.TrimPrefix(proto, protocolPrefix))
+		if !strings.HasPrefix(proto, protocolPrefix) || err != nil {
+			continue
+		}
+		if v >= ProtocolV1 && v <= ProtocolLatest && v > best {
+			best = v
+		}
+	}
+	if best == 0 {
+		return ProtocolV1, ""
+	}
+	return best, protocolPrefix + strconv.Itoa(best)
+}
+
+// protoAtLeast reports whether the client negotiated at least version v.
+// Clients created without a handshake — bots, tests — count as v1.
+func (c *Client) protoAtLeast(v int) bool {
+	proto := c.Proto
+	if proto == 0 {
+		proto = ProtocolV1
+	}
+	return proto >= v
+}
@@ -0,0 +1,34 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateProtocol(t *testing.T) {
+	tests := []struct {
+		header   string
+		version  int
+		wantName string
+	}{
+		{"", ProtocolV1, ""},
+		{"dino.v1", ProtocolV1, "dino.v1"},
+		{"dino.v1, dino.v2", ProtocolV2, "dino.v2"},
+		{"dino.v2, dino.v1", ProtocolV2, "dino.v2"},
+		// Unknown or future versions are skipped, not errors.
+		{"dino.v99", ProtocolV1, ""},
+		{"dino.v99, dino.v1", ProtocolV1, "dino.v1"},
+		{"chat.v3", ProtocolV1, ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/ws", nil)
+		if tt.header != "" {
+			r.Header.Set("Sec-WebSocket-Protocol", tt.header)
+		}
+		version, name := negotiateProtocol(r)
+		if version != tt.version || name != tt.wantName {
+			t.Errorf("negotiateProtocol(%q) = (%d, %q), want (%d, %q)",
+				tt.header, version, name, tt.version, tt.wantName)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// queueEntryTTL is how long a persisted queue entry survives in Redis. It
+// covers a deploy plus the clients' reconnect window; players who never come
+// back shouldn't keep their waiting priority forever.
+const queueEntryTTL = 10 * time.Minute
+
+// QueueStore mirrors queue membership into Redis so a restart doesn't
+// silently strand waiting players: a client that rejoins after a deploy gets
+// its original enqueue time — and with it its queue priority — back. A nil
+// store (Redis not configured) is valid and remembers nothing.
+type QueueStore struct {
+	rdb redis.UniversalClient
+}
+
+// NewQueueStore wraps a Redis client; rdb may be nil, which disables queue
+// persistence entirely.
+func NewQueueStore(rdb redis.UniversalClient) *QueueStore {
+	if rdb == nil {
+		return nil
+	}
+	return &QueueStore{rdb: rdb}
+}
+
+// queueEntryKey is the per-player Redis key holding their queue entry.
+func queueEntryKey(playerID string) string {
+	return "mm:queue:" + playerID
+}
+
+// Save records that a player is waiting in the given queue since the given
+// time.
+func (q *QueueStore) Save(playerID, queueKey string, since time.Time) {
+	if q == nil {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	value := fmt.Sprintf("%s|%d", queueKey, since.UnixMilli())
+	if err := q.rdb.Set(ctx, queueEntryKey(playerID), value, queueEntryTTL).Err(); err != nil {
+		slog.Error("persist queue entry failed", "player_id", playerID, "error", err)
+	}
+}
+
+// Remove drops a player's persisted entry once they are matched or leave the
+// queue.
+func (q *QueueStore) Remove(playerID string) {
+	if q == nil {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := q.rdb.Del(ctx, queueEntryKey(playerID)).Err(); err != nil {
+		slog.Error("drop queue entry failed", "player_id", playerID, "error", err)
+	}
+}
+
+// Lookup returns a player's persisted queue key and enqueue time, if any.
+func (q *QueueStore) Lookup(playerID string) (queueKey string, since time.Time, ok bool) {
+	if q == nil {
+		return "", time.Time{}, false
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	raw, err := q.rdb.Get(ctx, queueEntryKey(playerID)).Result()
+	if err != nil {
+		if err != redis.Nil {
+			slog.Error("read queue entry failed", "player_id", playerID, "error", err)
+		}
+		return "", time.Time{}, false
+	}
+	key, ms, found := strings.Cut(raw, "|")
+	if !found {
+		return "", time.Time{}, false
+	}
+	unixMs, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return key, time.UnixMilli(unixMs), true
+}
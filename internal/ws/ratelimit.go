@@ -0,0 +1,45 @@
+package ws
+
+import "time"
+
+// Inbound message rate limiting. The bucket comfortably fits legitimate
+// traffic (a couple of score updates plus inputs per second); sustained
+// abuse beyond it earns strikes and eventually a disconnect.
+var (
+	// InboundRate is how many messages per second refill a client's bucket.
+	InboundRate = 20.0
+	// InboundBurst is the bucket capacity.
+	InboundBurst = 40.0
+	// maxRateStrikes is how many over-limit messages are tolerated before
+	// the client is disconnected.
+	maxRateStrikes = 200
+)
+
+// tokenBucket is a classic token-bucket limiter. It is not safe for
+// concurrent use; each connection's read loop owns its own bucket.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// newTokenBucket returns a full bucket.
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{tokens: burst, last: time.Now(), rate: rate, burst: burst}
+}
+
+// allow consumes one token if available.
+func (b *tokenBucket) allow() bool {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
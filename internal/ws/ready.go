@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Pre-match handshake tuning. After GAME_START each client loads its assets
+// and sends READY; once everyone is ready the server counts down so both
+// runs begin on the same server timestamp.
+var (
+	// ReadyTimeout is how long the room waits for all READY messages before
+	// it is cancelled.
+	ReadyTimeout = 15 * time.Second
+	// countdownFrom is the number the 3-2-1 countdown starts at.
+	countdownFrom = 3
+)
+
+// pendingStart tracks a room between GAME_START and the simulation actually
+// beginning.
+type pendingStart struct {
+	players []*Client
+	// waiting holds the IDs of players who have not sent READY yet.
+	waiting map[string]bool
+	timeout *time.Timer
+	// begin starts the room's simulation loop.
+	begin func()
+}
+
+// initReady registers the ready handshake for a freshly created room.
+// Caller must hold m.mu.
+func (m *Matchmaker) initReady(roomID string, players []*Client, begin func()) {
+	p := &pendingStart{
+		players: players,
+		waiting: make(map[string]bool, len(players)),
+		begin:   begin,
+	}
+	for _, c := range players {
+		p.waiting[c.ID] = true
+	}
+	p.timeout = time.AfterFunc(ReadyTimeout, func() { m.cancelPending(roomID) })
+	m.pending[roomID] = p
+}
+
+// MarkReady records a READY from a client and, once the whole room is ready,
+// runs the countdown and starts the match.
+func (m *Matchmaker) MarkReady(c *Client) {
+	m.mu.Lock()
+	p, ok := m.pending[c.RoomID]
+	if !ok || !p.waiting[c.ID] {
+		m.mu.Unlock()
+		return
+	}
+	delete(p.waiting, c.ID)
+	if len(p.waiting) > 0 {
+		m.mu.Unlock()
+		return
+	}
+	p.timeout.Stop()
+	delete(m.pending, c.RoomID)
+	if meta, ok := m.matches[c.RoomID]; ok {
+		meta.room.Transition(game.RoomCountdown)
+	}
+	m.mu.Unlock()
+
+	go m.runCountdown(c.RoomID, p)
+}
+
+// runCountdown broadcasts the 3-2-1 ticks, waits for the shared start
+// timestamp, then begins the simulation.
+func (m *Matchmaker) runCountdown(roomID string, p *pendingStart) {
+	startAt := time.Now().Add(time.Duration(countdownFrom) * time.Second)
+	slog.Info("countdown started", "room_id", roomID, "start_at", startAt.UnixMilli())
+	for n := countdownFrom; n >= 1; n-- {
+		payload := CountdownPayload{Count: n, StartAt: startAt.UnixMilli()}
+		for _, c := range p.players {
+			c.SendJSON(MsgCountdown, payload)
+		}
+		time.Sleep(time.Second)
+	}
+	p.begin()
+}
+
+// cancelPending tears down a room whose players never all readied up. The
+// simulation loop was never started, so only match bookkeeping is undone.
+func (m *Matchmaker) cancelPending(roomID string) {
+	m.mu.Lock()
+	p, ok := m.pending[roomID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.pending, roomID)
+	m.mu.Unlock()
+
+	slog.Info("match cancelled, not all players readied", "room_id", roomID, "missing", len(p.waiting))
+	m.validator.UnregisterMatch(roomID)
+	for _, c := range p.players {
+		c.SendJSON(MsgMatchCancelled, MatchCancelledPayload{Reason: "opponent never readied"})
+		m.sessions.Drop(c.SessionToken)
+		m.hub.SetRoom(c, "")
+		c.SessionToken = ""
+		c.resetMatchState()
+	}
+	// The match bookkeeping created alongside the room would otherwise
+	// linger forever: no simulation ever started, so neither the death paths
+	// nor the stale-room reaper would get to it.
+	if room, ok := m.ClearMatch(roomID); ok {
+		room.Transition(game.RoomCleaned)
+	}
+}
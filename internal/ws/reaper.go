@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Stale-room reaper tuning.
+var (
+	// staleRoomAfter is how long a room may go without any client message
+	// before it is torn down; 0 disables the reaper.
+	staleRoomAfter = 5 * time.Minute
+	// reapInterval is how often the reaper scans for stale rooms.
+	reapInterval = time.Minute
+)
+
+// reapStaleRooms periodically tears down rooms whose players all vanished
+// without a clean PLAYER_DIED — crashed browsers, dropped networks — so
+// their state doesn't linger forever.
+func (h *Handler) reapStaleRooms() {
+	for range time.Tick(reapInterval) {
+		if staleRoomAfter <= 0 {
+			continue
+		}
+		for _, roomID := range h.Hub.StaleRooms(staleRoomAfter) {
+			h.reapRoom(roomID)
+		}
+	}
+}
+
+// reapRoom force-resolves one stale room: whatever scores the players
+// reached are persisted, remaining clients are notified, and all match
+// state is released.
+func (h *Handler) reapRoom(roomID string) {
+	ctx := context.Background()
+	clients := h.Hub.GetClientsInRoom(roomID)
+	slog.Warn("reaping stale room", "room_id", roomID, "clients", len(clients))
+
+	duration := h.Matchmaker.MatchDuration(roomID)
+	room, _ := h.Matchmaker.ClearMatch(roomID)
+	scores := make(map[string]int, len(clients))
+	for _, c := range clients {
+		scores[c.ID] = c.currentScore()
+	}
+	h.notifyMatchEnd(roomID, clients, scores, "", "", GameOverReasonStale, duration)
+	for _, c := range clients {
+		if !c.IsBot && scores[c.ID] > 0 {
+			h.persistScore(ctx, roomID, c)
+		}
+		// No winner is declared: the room died, nobody finished.
+		c.SendJSON(MsgGameOver, GameOverPayload{Scores: scores, Reason: GameOverReasonStale})
+		c.markDead()
+		h.cleanupClient(c)
+	}
+	h.cleanupRoom(roomID, room)
+}
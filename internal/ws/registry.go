@@ -0,0 +1,154 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/tracing"
+)
+
+// handlerFunc processes one inbound frame after the envelope concerns —
+// codec decode, seq dedup, timeout, tracing — have run. Implementations
+// send their own ERROR frames; dispatch treats every message as handled.
+type handlerFunc func(ctx context.Context, c *Client, msg Message)
+
+// middleware wraps a handlerFunc with a cross-cutting check such as "must
+// be in a room". Middleware listed in a Register call run in order,
+// outermost first, before the payload is decoded.
+type middleware func(handlerFunc) handlerFunc
+
+// Register installs the handler for one message type. New protocol
+// messages plug in here without touching the dispatch loop; registering a
+// built-in type again replaces it. Call it during construction only — the
+// table is read without locking once connections are served.
+func (h *Handler) Register(msgType string, fn handlerFunc, mw ...middleware) {
+	for i := len(mw) - 1; i >= 0; i-- {
+		fn = mw[i](fn)
+	}
+	h.handlers[msgType] = fn
+}
+
+// validatable is the optional self-check hook on payload types. Decoded
+// payloads implementing it are validated before their handler runs, so
+// handlers only ever see well-formed input.
+type validatable interface {
+	Validate() error
+}
+
+// decoded adapts a payload-typed handler into a handlerFunc: the frame's
+// payload is unmarshalled into P, checked via its Validate hook, and
+// rejected with INVALID_PAYLOAD before the handler sees it. seq is the
+// frame's sequence number, zero for unstamped messages.
+func decoded[P any](h *Handler, msgType string, fn func(ctx context.Context, c *Client, p P, seq int64)) handlerFunc {
+	return func(ctx context.Context, c *Client, msg Message) {
+		var p P
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendError(c, ErrCodeInvalidPayload, "malformed "+msgType+" payload")
+			return
+		}
+		if v, ok := any(&p).(validatable); ok {
+			_, span := tracing.Start(ctx, "ws.validate")
+			err := v.Validate()
+			span.End()
+			if err != nil {
+				h.sendError(c, ErrCodeInvalidPayload, err.Error())
+				return
+			}
+		}
+		fn(ctx, c, p, msg.Seq)
+	}
+}
+
+// inRoom rejects messages that only make sense mid-match.
+func (h *Handler) inRoom(next handlerFunc) handlerFunc {
+	return func(ctx context.Context, c *Client, msg Message) {
+		if c.RoomID == "" {
+			h.sendError(c, ErrCodeNotInRoom, "not in a match")
+			return
+		}
+		next(ctx, c, msg)
+	}
+}
+
+// alive silently drops messages from players whose run already ended;
+// clients keep sending through the death animation, and erroring on each
+// frame would flood them.
+func (h *Handler) alive(next handlerFunc) handlerFunc {
+	return func(ctx context.Context, c *Client, msg Message) {
+		if !c.alive() {
+			return
+		}
+		next(ctx, c, msg)
+	}
+}
+
+// chatLimited enforces the shared chat/emote rate limit, dropping the
+// excess silently.
+func (h *Handler) chatLimited(next handlerFunc) handlerFunc {
+	return func(ctx context.Context, c *Client, msg Message) {
+		if !h.allowChat(c) {
+			return
+		}
+		next(ctx, c, msg)
+	}
+}
+
+// registerHandlers installs the built-in protocol. Each line reads as the
+// message's pipeline: middleware in listed order, then payload decoding and
+// validation, then the handler.
+func (h *Handler) registerHandlers() {
+	h.Register(MsgJoinQueue, decoded(h, MsgJoinQueue, h.handleJoinQueue))
+	h.Register(MsgPractice, decoded(h, MsgPractice, h.handlePractice))
+	h.Register(MsgJoinChallenge, decoded(h, MsgJoinChallenge, h.handleJoinChallenge))
+	h.Register(MsgTimeSync, decoded(h, MsgTimeSync, func(_ context.Context, c *Client, p TimeSyncPayload, _ int64) {
+		c.SendJSON(MsgTimeSync, TimeSyncPayload{ClientTime: p.ClientTime, ServerTime: time.Now().UnixMilli()})
+	}))
+	h.Register(MsgReady, func(_ context.Context, c *Client, _ Message) {
+		h.Matchmaker.MarkReady(c)
+	}, h.inRoom)
+	h.Register(MsgCancelQueue, func(_ context.Context, c *Client, _ Message) {
+		if h.Matchmaker.Remove(c) {
+			c.SendJSON(MsgQueueCancelled, nil)
+		}
+	})
+	h.Register(MsgPlayerInput, decoded(h, MsgPlayerInput, h.handlePlayerInput), h.inRoom, h.alive)
+	h.Register(MsgPositionUpdate, decoded(h, MsgPositionUpdate, func(_ context.Context, c *Client, p PositionUpdatePayload, _ int64) {
+		// Position updates are high-frequency; out-of-match ones are dropped
+		// silently instead of generating an error flood.
+		if c.RoomID == "" || !c.alive() {
+			return
+		}
+		h.relayPosition(c, p)
+	}))
+	h.Register(MsgChat, decoded(h, MsgChat, h.handleChat), h.inRoom, h.chatLimited)
+	h.Register(MsgEmote, decoded(h, MsgEmote, func(_ context.Context, c *Client, p EmotePayload, _ int64) {
+		h.notifyChat(c, MsgEmote, EmotePayload{PlayerID: c.ID, Emote: p.Emote})
+	}), h.inRoom, h.chatLimited)
+	h.Register(MsgMute, decoded(h, MsgMute, func(_ context.Context, c *Client, p MutePayload, _ int64) {
+		c.Muted = p.Muted
+	}))
+	h.Register(MsgChallengeFriend, decoded(h, MsgChallengeFriend, func(ctx context.Context, c *Client, p ChallengeFriendPayload, _ int64) {
+		h.challengeFriend(ctx, c, p.FriendID, p.Settings)
+	}))
+	h.Register(MsgAcceptInvite, decoded(h, MsgAcceptInvite, func(_ context.Context, c *Client, p AcceptInvitePayload, _ int64) {
+		h.acceptInvite(c, p.FromID)
+	}))
+	h.Register(MsgSpectate, decoded(h, MsgSpectate, func(_ context.Context, c *Client, p SpectatePayload, _ int64) {
+		h.handleSpectate(c, p)
+	}))
+	h.Register(MsgStopSpectate, func(_ context.Context, c *Client, _ Message) {
+		h.stopSpectating(c)
+	})
+	h.Register(MsgSpectatorChat, decoded(h, MsgSpectatorChat, func(_ context.Context, c *Client, p ChatPayload, _ int64) {
+		h.handleSpectatorChat(c, p)
+	}))
+	h.Register(MsgReportPlayer, decoded(h, MsgReportPlayer, func(ctx context.Context, c *Client, p ReportPlayerPayload, _ int64) {
+		h.handleReport(ctx, c, p)
+	}))
+	h.Register(MsgPowerUpCollected, decoded(h, MsgPowerUpCollected, func(_ context.Context, c *Client, p PowerUpCollectedPayload, _ int64) {
+		h.handlePowerUpCollected(c, p)
+	}))
+	h.Register(MsgUpdateScore, decoded(h, MsgUpdateScore, h.handleUpdateScore), h.inRoom, h.alive)
+	h.Register(MsgPlayerDied, decoded(h, MsgPlayerDied, h.handlePlayerDied), h.inRoom, h.alive)
+}
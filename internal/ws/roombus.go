@@ -0,0 +1,271 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// roomStateTTL bounds how long mirrored room state lives in Redis after the
+// last update, so crashed instances don't leak keys.
+const roomStateTTL = time.Hour
+
+// busOpTimeout bounds each Redis call the bus makes, so a slow or
+// unreachable Redis never wedges a message handler.
+const busOpTimeout = 2 * time.Second
+
+// busCtx returns a timeout-bounded context for one bus operation.
+func busCtx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), busOpTimeout)
+}
+
+// roomChannel names the pub/sub channel carrying one room's traffic.
+func roomChannel(roomID string) string {
+	return "room:msgs:" + roomID
+}
+
+// roomPlayersKey names the hash mapping a room's player IDs to the instance
+// their socket terminates on.
+func roomPlayersKey(roomID string) string {
+	return "room:" + roomID + ":players"
+}
+
+// roomScoresKey names the hash holding a room's per-player state.
+func roomScoresKey(roomID string) string {
+	return "room:" + roomID + ":state"
+}
+
+// busFrame is one relayed in-room message on the wire between instances.
+type busFrame struct {
+	Instance string          `json:"instance"`
+	SenderID string          `json:"senderId"`
+	Type     string          `json:"type"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+}
+
+// RoomBus mirrors room membership and per-player state into Redis and relays
+// in-room messages through pub/sub channels keyed by room ID, so a match
+// keeps working when its two sockets terminate on different instances. Every
+// method is a no-op on a nil receiver, which is how the server runs when
+// Redis is not configured.
+type RoomBus struct {
+	rdb        redis.UniversalClient
+	hub        *Hub
+	instanceID string
+
+	mu   sync.Mutex
+	subs map[string]*redis.PubSub
+}
+
+// NewRoomBus returns a bus identified by a fresh instance ID, or nil when
+// rdb is nil.
+func NewRoomBus(rdb redis.UniversalClient, hub *Hub) *RoomBus {
+	if rdb == nil {
+		return nil
+	}
+	return &RoomBus{
+		rdb:        rdb,
+		hub:        hub,
+		instanceID: "inst_" + randomHex(8),
+		subs:       make(map[string]*redis.PubSub),
+	}
+}
+
+// JoinRoom records which instance owns a player's socket and starts relaying
+// the room's channel to this instance.
+func (b *RoomBus) JoinRoom(roomID, playerID string) {
+	if b == nil {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	pipe := b.rdb.Pipeline()
+	pipe.HSet(ctx, roomPlayersKey(roomID), playerID, b.instanceID)
+	pipe.Expire(ctx, roomPlayersKey(roomID), roomStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("mirror room membership failed", "room_id", roomID, "player_id", playerID, "error", err)
+	}
+	b.subscribe(roomID)
+}
+
+// LeaveRoom undoes JoinRoom; once this instance has no local members left in
+// the room, its channel subscription is dropped too.
+func (b *RoomBus) LeaveRoom(roomID, playerID string) {
+	if b == nil || roomID == "" {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	pipe := b.rdb.Pipeline()
+	pipe.HDel(ctx, roomPlayersKey(roomID), playerID)
+	pipe.HDel(ctx, roomScoresKey(roomID), playerID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("clear room membership failed", "room_id", roomID, "player_id", playerID, "error", err)
+	}
+	if len(b.hub.GetClientsInRoom(roomID)) == 0 && b.hub.SpectatorCount(roomID) == 0 {
+		b.unsubscribe(roomID)
+	}
+}
+
+// SetPlayerState mirrors one player's score and alive flag so other
+// instances (and restart recovery) can read the room's live state.
+func (b *RoomBus) SetPlayerState(roomID, playerID string, score int, alive bool) {
+	if b == nil || roomID == "" {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	pipe := b.rdb.Pipeline()
+	pipe.HSet(ctx, roomScoresKey(roomID), playerID, fmt.Sprintf("%d|%t", score, alive))
+	pipe.Expire(ctx, roomScoresKey(roomID), roomStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("mirror player state failed", "room_id", roomID, "player_id", playerID, "error", err)
+	}
+}
+
+// Publish relays one in-room message to every other instance with members in
+// the room. Local delivery stays in-process; the frame carries the instance
+// ID so the sender skips its own echo.
+func (b *RoomBus) Publish(roomID, senderID, msgType string, payload interface{}) {
+	if b == nil || roomID == "" {
+		return
+	}
+	var raw json.RawMessage
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			slog.Error("marshal bus frame failed", "msg_type", msgType, "error", err)
+			return
+		}
+		raw = data
+	}
+	frame, err := json.Marshal(busFrame{Instance: b.instanceID, SenderID: senderID, Type: msgType, Payload: raw})
+	if err != nil {
+		slog.Error("marshal bus frame failed", "msg_type", msgType, "error", err)
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := b.rdb.Publish(ctx, roomChannel(roomID), frame).Err(); err != nil {
+		slog.Error("publish room message failed", "room_id", roomID, "error", err)
+	}
+}
+
+// subscribe starts relaying a room's channel into this instance, once.
+func (b *RoomBus) subscribe(roomID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[roomID]; ok {
+		return
+	}
+	sub := b.rdb.Subscribe(context.Background(), roomChannel(roomID))
+	b.subs[roomID] = sub
+	go b.relay(roomID, sub)
+}
+
+// unsubscribe stops relaying a room's channel.
+func (b *RoomBus) unsubscribe(roomID string) {
+	b.mu.Lock()
+	sub, ok := b.subs[roomID]
+	delete(b.subs, roomID)
+	b.mu.Unlock()
+	if ok {
+		sub.Close()
+	}
+}
+
+// relay delivers frames published by other instances to this instance's
+// local members of the room. It exits when the subscription is closed.
+func (b *RoomBus) relay(roomID string, sub *redis.PubSub) {
+	for msg := range sub.Channel() {
+		var frame busFrame
+		if err := json.Unmarshal([]byte(msg.Payload), &frame); err != nil {
+			slog.Warn("bad bus frame", "room_id", roomID, "error", err)
+			continue
+		}
+		if frame.Instance == b.instanceID {
+			continue
+		}
+		// Decode once so both wire codecs re-encode the payload correctly.
+		var payload interface{}
+		if len(frame.Payload) > 0 {
+			if err := json.Unmarshal(frame.Payload, &payload); err != nil {
+				slog.Warn("bad bus payload", "room_id", roomID, "error", err)
+				continue
+			}
+		}
+		for _, c := range b.hub.GetClientsInRoom(roomID) {
+			if c.ID != frame.SenderID {
+				c.SendJSON(frame.Type, payload)
+			}
+		}
+		for _, c := range b.hub.SpectatorsInRoom(roomID) {
+			if c.ID != frame.SenderID {
+				c.SendJSON(frame.Type, payload)
+			}
+		}
+	}
+}
+
+// WatchRoom starts relaying a room's channel for a local spectator, without
+// claiming membership in the Redis mirror: spectators fan out from whatever
+// instance they attached to, so their volume never lands on the instance
+// hosting the players.
+func (b *RoomBus) WatchRoom(roomID string) {
+	if b == nil || roomID == "" {
+		return
+	}
+	b.subscribe(roomID)
+}
+
+// Unwatch drops the room's channel subscription once this instance has
+// neither players nor spectators left in the room.
+func (b *RoomBus) Unwatch(roomID string) {
+	if b == nil || roomID == "" {
+		return
+	}
+	if len(b.hub.GetClientsInRoom(roomID)) == 0 && b.hub.SpectatorCount(roomID) == 0 {
+		b.unsubscribe(roomID)
+	}
+}
+
+// RoomState reads a room's mirrored membership and per-player state back
+// from Redis, so an instance hosting none of the players can still seat
+// spectators. It reports ok=false for unknown rooms or without Redis.
+func (b *RoomBus) RoomState(roomID string) (scores map[string]int, ok bool) {
+	if b == nil {
+		return nil, false
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	members, err := b.rdb.HGetAll(ctx, roomPlayersKey(roomID)).Result()
+	if err != nil {
+		slog.Error("read room membership failed", "room_id", roomID, "error", err)
+		return nil, false
+	}
+	if len(members) == 0 {
+		return nil, false
+	}
+	state, err := b.rdb.HGetAll(ctx, roomScoresKey(roomID)).Result()
+	if err != nil {
+		slog.Error("read room state failed", "room_id", roomID, "error", err)
+		return nil, false
+	}
+	scores = make(map[string]int, len(members))
+	for id := range members {
+		scores[id] = 0
+	}
+	for id, raw := range state {
+		var score int
+		var alive bool
+		if _, err := fmt.Sscanf(raw, "%d|%t", &score, &alive); err == nil {
+			scores[id] = score
+		}
+	}
+	return scores, true
+}
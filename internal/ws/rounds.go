@@ -0,0 +1,231 @@
+package ws
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// roundsToWin is how many round wins take a best-of-3 match.
+const roundsToWin = 2
+
+// timedMatchDuration is the clock for FormatTimed rooms.
+var timedMatchDuration = 120 * time.Second
+
+// overtimeFormats lists the formats whose tied matches go to sudden-death
+// overtime instead of ending as draws. Best-of-3 is absent because
+// AdvanceRound already replays drawn rounds, and timed matches keep their
+// clock-expired draws.
+var overtimeFormats = map[string]bool{
+	FormatSingle: true,
+}
+
+// matchMeta is the per-room bookkeeping for match format and round wins.
+type matchMeta struct {
+	format  string
+	round   int
+	wins    map[string]int
+	players []*Client
+	// room is the first-class room record tracking membership and the
+	// waiting/countdown/running/finished lifecycle.
+	room *game.Room
+	// scoreKey signs this room's score submissions; handed out in
+	// GAME_START and stable across rounds.
+	scoreKey string
+	// overtime records that this match already went to sudden death, so a
+	// second tie stands as a draw instead of looping forever.
+	overtime bool
+	// practice marks an unranked solo-vs-bot room: its results stay off the
+	// leaderboard and out of ratings and stats.
+	practice bool
+	// challengeDay, when set, marks a daily-challenge room and names the UTC
+	// day whose shared seed it runs; the final score ranks on that day's
+	// challenge board.
+	challengeDay string
+	// seeds lists the seed of every round played so far, for the fairness
+	// verification archive.
+	seeds []int64
+	// startedAt is when play actually began (after the first countdown).
+	startedAt time.Time
+	// timer ends FormatTimed rooms when their clock runs out.
+	timer *time.Timer
+}
+
+func newMatchMeta(roomID string, seed int64, format string, players []*Client) *matchMeta {
+	room := game.NewRoom(roomID, seed, len(players))
+	for _, c := range players {
+		room.AddPlayer(c.ID)
+	}
+	return &matchMeta{
+		format:   format,
+		round:    1,
+		wins:     make(map[string]int),
+		players:  players,
+		room:     room,
+		scoreKey: randomHex(16),
+		seeds:    []int64{seed},
+	}
+}
+
+// splitQueueKey is the inverse of queueKey.
+func splitQueueKey(key string) (mode, format string) {
+	mode, format, ok := strings.Cut(key, "|")
+	if !ok {
+		return key, FormatSingle
+	}
+	return mode, format
+}
+
+// AdvanceRound records one finished round for a room. For single-round
+// matches and decided best-of-3s it reports done=true along with the round
+// wins; otherwise it schedules the next round (fresh seed, another READY
+// handshake) and reports done=false. A drawn round counts for nobody and is
+// simply replayed.
+func (m *Matchmaker) AdvanceRound(roomID, winnerID string) (done bool, wins map[string]int) {
+	m.mu.Lock()
+	meta, ok := m.matches[roomID]
+	if !ok || meta.format != FormatBestOf3 {
+		m.mu.Unlock()
+		return true, nil
+	}
+	if winnerID != "" {
+		meta.wins[winnerID]++
+		if meta.wins[winnerID] >= roundsToWin {
+			m.mu.Unlock()
+			return true, meta.wins
+		}
+	}
+	meta.round++
+	m.startNextRound(roomID, meta, winnerID)
+	m.mu.Unlock()
+	return false, meta.wins
+}
+
+// StartOvertime begins a sudden-death tiebreaker for a tied match: the
+// players get one extra round with a fresh seed, and whoever wins it takes
+// the match. Only formats in overtimeFormats qualify, and only once per
+// match. It reports whether overtime was started.
+func (m *Matchmaker) StartOvertime(roomID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok || !overtimeFormats[meta.format] || meta.overtime {
+		return false
+	}
+	meta.overtime = true
+	meta.round++
+	m.startNextRound(roomID, meta, "")
+	slog.Info("match tied, starting sudden-death overtime", "room_id", roomID)
+	return true
+}
+
+// markStarted records the moment play begins for a room and, for timed
+// rooms, arms the match clock.
+func (m *Matchmaker) markStarted(roomID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok {
+		return
+	}
+	meta.startedAt = time.Now()
+	// Every player counts as active at the starting gun, so the idle
+	// watchdog measures silence from the moment play begins.
+	for _, c := range meta.players {
+		c.markActive()
+	}
+	meta.room.Transition(game.RoomRunning)
+	if meta.format == FormatTimed && m.botHost != nil {
+		meta.timer = time.AfterFunc(timedMatchDuration, func() {
+			m.botHost.finishTimedMatch(roomID)
+		})
+	}
+}
+
+// MatchDuration reports how long a room has been playing, or zero if the
+// room is unknown or still in its READY handshake.
+func (m *Matchmaker) MatchDuration(roomID string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok || meta.startedAt.IsZero() {
+		return 0
+	}
+	return time.Since(meta.startedAt)
+}
+
+// ClearMatch drops a room's round bookkeeping once the match is resolved,
+// stopping any pending match clock and moving the room to finished. It
+// reports whether the room was still tracked, so racing finish paths resolve
+// the match exactly once, and hands the room back so the winning path can
+// close out its lifecycle once cleanup is done.
+func (m *Matchmaker) ClearMatch(roomID string) (*game.Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, ok := m.matches[roomID]
+	if !ok {
+		return nil, false
+	}
+	if meta.timer != nil {
+		meta.timer.Stop()
+	}
+	meta.room.Transition(game.RoomFinished)
+	m.archiveVerification(roomID, meta)
+	delete(m.matches, roomID)
+	return meta.room, true
+}
+
+// startNextRound resets the room for another round: stop the old simulation,
+// issue a fresh seed, and wait for every player to READY up again before the
+// next countdown. Caller must hold m.mu.
+func (m *Matchmaker) startNextRound(roomID string, meta *matchMeta, lastWinnerID string) {
+	m.loops.Stop(roomID)
+	m.validator.UnregisterMatch(roomID)
+
+	seed := randomSeed()
+	meta.seeds = append(meta.seeds, seed)
+	meta.room.Transition(game.RoomWaiting)
+	meta.room.Reseed(seed)
+	scores := make(map[string]int, len(meta.players))
+	ids := make([]string, 0, len(meta.players))
+	for _, c := range meta.players {
+		scores[c.ID] = c.currentScore()
+		ids = append(ids, c.ID)
+	}
+	players := meta.players
+	m.initReady(roomID, players, func() {
+		meta.room.Transition(game.RoomRunning)
+		m.validator.RegisterMatch(roomID, seed)
+		m.loops.Start(roomID, seed, ids, func(s game.Snapshot) {
+			for _, c := range players {
+				c.SendJSON(MsgStateSnapshot, s)
+			}
+		})
+		if m.botHost != nil {
+			go m.botHost.broadcastPowerUps(roomID, seed)
+			go m.botHost.streamObstacleSchedule(roomID, seed)
+		}
+	})
+
+	payload := RoundOverPayload{
+		Round:    meta.round - 1,
+		WinnerID: lastWinnerID,
+		Scores:   scores,
+		Wins:     meta.wins,
+		NextSeed: seed,
+	}
+	for _, c := range meta.players {
+		c.resetRun(0)
+		c.SendJSON(MsgRoundOver, payload)
+	}
+	// Clients drop the old round's obstacle schedule on ROUND_OVER; hand them
+	// the first window of the new seed's right away.
+	if sched, ok := obstacleSchedulePayload(game.NewTimeline(seed), 0); ok {
+		for _, c := range meta.players {
+			c.SendJSON(MsgObstacleSchedule, sched)
+		}
+	}
+	slog.Info("next round starting", "room_id", roomID, "round", meta.round, "seed", seed)
+}
@@ -0,0 +1,32 @@
+package ws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Score submission signing: every room gets a random key, handed to its
+// players in GAME_START. UPDATE_SCORE and PLAYER_DIED must carry an HMAC
+// over (roomId, score, seq) under that key, so a leaderboard write can only
+// come from a client that was actually dealt into the match.
+
+// SignScore computes the hex HMAC-SHA256 a client attaches to a score
+// submission. Exported as the reference implementation for Go clients.
+func SignScore(key, roomID string, score int, seq int64) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	fmt.Fprintf(mac, "%s|%d|%d", roomID, score, seq)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyScoreSig checks a score submission's signature against the sender's
+// room key. Unknown rooms fail closed.
+func (h *Handler) verifyScoreSig(c *Client, score int, seq int64, sig string) bool {
+	key, ok := h.Matchmaker.ScoreKey(c.RoomID)
+	if !ok {
+		return false
+	}
+	expected := SignScore(key, c.RoomID, score, seq)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
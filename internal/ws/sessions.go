@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"sync"
+	"time"
+)
+
+// reconnectGrace is how long a disconnected player's match state is kept
+// alive waiting for them to come back.
+var reconnectGrace = 30 * time.Second
+
+// SessionStore maps reconnect tokens to in-match client state so a dropped
+// connection can resume instead of forfeiting the match. Tokens are
+// single-use: Resume consumes the presented token, and the caller issues a
+// fresh one for the continued session, so a leaked token cannot be replayed.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	// tokens mirrors token validity into Redis for cross-instance replay
+	// protection and TTL cleanup; nil without Redis.
+	tokens *TokenStore
+}
+
+type session struct {
+	client *Client
+	expiry *time.Timer
+}
+
+// NewSessionStore returns an empty store.
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]*session)}
+}
+
+// SetTokenStore attaches the optional Redis token mirror. Call it at
+// startup, before connections arrive.
+func (s *SessionStore) SetTokenStore(tokens *TokenStore) {
+	s.tokens = tokens
+}
+
+// Issue creates a reconnect token for a client entering a match. The token
+// is handed to the client in GAME_START (or SESSION_RESUMED after a
+// reconnect) and presented on the next reconnect.
+func (s *SessionStore) Issue(c *Client) string {
+	token := randomHex(16)
+	s.mu.Lock()
+	s.sessions[token] = &session{client: c}
+	s.mu.Unlock()
+	s.tokens.Save(token, c.ID)
+	return token
+}
+
+// Park starts the grace window for a disconnected client. If the client does
+// not resume before it elapses, onExpire runs and the session is dropped.
+func (s *SessionStore) Park(token string, onExpire func(*Client)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[token]
+	if !ok {
+		return
+	}
+	sess.expiry = time.AfterFunc(reconnectGrace, func() {
+		s.mu.Lock()
+		delete(s.sessions, token)
+		s.mu.Unlock()
+		s.tokens.Drop(token)
+		onExpire(sess.client)
+	})
+}
+
+// Resume consumes a token and returns its parked client, stopping the
+// expiry timer. It fails if the token is unknown, already used, or the
+// grace window elapsed; on success the token is dead and the caller must
+// issue a replacement. Of several connections racing with the same token,
+// exactly one wins.
+func (s *SessionStore) Resume(token string) (*Client, bool) {
+	// The Redis GETDEL runs first: it is the atomic single-use gate across
+	// instances, and burning the token of a session that turns out to be
+	// gone locally costs nothing.
+	if !s.tokens.Consume(token) {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[token]
+	if !ok {
+		return nil, false
+	}
+	if sess.expiry != nil {
+		if !sess.expiry.Stop() {
+			// Timer already fired; the session is being torn down.
+			return nil, false
+		}
+		sess.expiry = nil
+	}
+	delete(s.sessions, token)
+	return sess.client, true
+}
+
+// Drop removes a token once its match is over.
+func (s *SessionStore) Drop(token string) {
+	s.mu.Lock()
+	if sess, ok := s.sessions[token]; ok {
+		if sess.expiry != nil {
+			sess.expiry.Stop()
+		}
+		delete(s.sessions, token)
+	}
+	s.mu.Unlock()
+	s.tokens.Drop(token)
+}
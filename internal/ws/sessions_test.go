@@ -0,0 +1,62 @@
+package ws
+
+import (
+	"sync"
+	"testing"
+)
+
+// Tokens are single-use: once resumed, the same token must never work
+// again, or a stolen token could hijack a session the legitimate player
+// already reclaimed.
+func TestResumeConsumesToken(t *testing.T) {
+	store := NewSessionStore()
+	c := newTestClient("p1")
+	token := store.Issue(c)
+
+	got, ok := store.Resume(token)
+	if !ok || got != c {
+		t.Fatalf("first Resume = (%v, %v), want the issued client", got, ok)
+	}
+	if _, ok := store.Resume(token); ok {
+		t.Fatal("replayed token resumed a second time")
+	}
+}
+
+// Concurrent reconnects with the same token — a client retrying on a flaky
+// link, or an attacker racing the real player — must produce exactly one
+// winner.
+func TestConcurrentResumeSingleWinner(t *testing.T) {
+	store := NewSessionStore()
+	token := store.Issue(newTestClient("p1"))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := store.Resume(token); ok {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Fatalf("%d concurrent resumes succeeded, want exactly 1", wins)
+	}
+}
+
+// A dropped token is gone for good, and dropping an unknown token is a
+// harmless no-op.
+func TestDroppedTokenCannotResume(t *testing.T) {
+	store := NewSessionStore()
+	token := store.Issue(newTestClient("p1"))
+	store.Drop(token)
+	if _, ok := store.Resume(token); ok {
+		t.Fatal("dropped token still resumed")
+	}
+	store.Drop("never-issued")
+}
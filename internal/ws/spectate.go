@@ -0,0 +1,87 @@
+package ws
+
+import "log/slog"
+
+// Spectating: any connected client that is neither queued nor playing may
+// watch a live room with SPECTATE. Spectators sit outside the room index, so
+// match resolution, score fan-out, and the reaper never see them; they get a
+// chat channel of their own that players are never subscribed to.
+
+// handleSpectate attaches a client to a live room as a watcher.
+func (h *Handler) handleSpectate(c *Client, p SpectatePayload) {
+	if c.RoomID != "" || c.InQueue {
+		h.sendError(c, ErrCodeInvalidPayload, "finish your current game first")
+		return
+	}
+	players := h.Hub.GetClientsInRoom(p.RoomID)
+	if len(players) == 0 {
+		// No local players: the match may live on another instance, in
+		// which case the bus's Redis mirror knows it and its channel can be
+		// relayed here. Spectator traffic then fans out from this instance,
+		// not the one hosting the players.
+		scores, ok := h.bus.RoomState(p.RoomID)
+		if !ok {
+			h.sendError(c, ErrCodeNotInRoom, "no such room")
+			return
+		}
+		h.Hub.AddSpectator(p.RoomID, c)
+		h.bus.WatchRoom(p.RoomID)
+		infos := make([]PlayerInfo, 0, len(scores))
+		for id := range scores {
+			infos = append(infos, PlayerInfo{ID: id})
+		}
+		c.SendJSON(MsgSpectateOK, SpectateOKPayload{RoomID: p.RoomID, Players: infos, Scores: scores})
+		slog.Info("client spectating remote room", "client_id", c.ID, "room_id", p.RoomID)
+		return
+	}
+	h.Hub.AddSpectator(p.RoomID, c)
+	if room, ok := h.Matchmaker.Room(p.RoomID); ok {
+		room.AddSpectator(c.ID)
+	}
+
+	infos := make([]PlayerInfo, 0, len(players))
+	scores := make(map[string]int, len(players))
+	for _, pl := range players {
+		infos = append(infos, PlayerInfo{ID: pl.ID, Name: pl.Name, Rating: pl.Rating, Team: pl.Team, Skin: pl.Skin})
+		scores[pl.ID] = pl.currentScore()
+	}
+	c.SendJSON(MsgSpectateOK, SpectateOKPayload{RoomID: p.RoomID, Players: infos, Scores: scores})
+	slog.Info("client spectating room", "client_id", c.ID, "room_id", p.RoomID)
+}
+
+// stopSpectating removes a watcher from both the hub's spectator index and
+// the room record, if the match is still tracked. The bus subscription is
+// dropped once nobody on this instance cares about the room anymore.
+func (h *Handler) stopSpectating(c *Client) {
+	if c.Spectating == "" {
+		return
+	}
+	roomID := c.Spectating
+	if room, ok := h.Matchmaker.Room(roomID); ok {
+		room.RemoveSpectator(c.ID)
+	}
+	h.Hub.RemoveSpectator(c)
+	h.bus.Unwatch(roomID)
+}
+
+// handleSpectatorChat relays a chat message to the other spectators of the
+// same room. Players never receive this channel.
+func (h *Handler) handleSpectatorChat(c *Client, p ChatPayload) {
+	if c.Spectating == "" {
+		h.sendError(c, ErrCodeNotInRoom, "not spectating a room")
+		return
+	}
+	if !h.allowChat(c) {
+		return
+	}
+	text := sanitizeChat(p.Text)
+	if text == "" {
+		return
+	}
+	payload := ChatPayload{PlayerID: c.ID, Name: c.Name, Text: text}
+	for _, other := range h.Hub.SpectatorsInRoom(c.Spectating) {
+		if other.ID != c.ID && !other.Muted {
+			other.SendJSON(MsgSpectatorChat, payload)
+		}
+	}
+}
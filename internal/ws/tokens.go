@@ -0,0 +1,83 @@
+package ws
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sessionTokenTTL is how long an issued reconnect token stays valid in
+// Redis. It is a generous bound on match length plus the reconnect grace
+// window; the in-memory session store is the real gate, the TTL only
+// guarantees Redis cleans up tokens whose match outlived every instance.
+const sessionTokenTTL = time.Hour
+
+// TokenStore mirrors reconnect token validity into Redis so tokens are
+// single-use even across instances: consuming one is an atomic GETDEL, so
+// two connections racing with the same stolen token can never both win. A
+// nil store (Redis not configured) is valid and leaves single-use
+// enforcement to the in-memory session store alone.
+type TokenStore struct {
+	rdb redis.UniversalClient
+}
+
+// NewTokenStore wraps a Redis client; rdb may be nil, which disables the
+// cross-instance replay protection.
+func NewTokenStore(rdb redis.UniversalClient) *TokenStore {
+	if rdb == nil {
+		return nil
+	}
+	return &TokenStore{rdb: rdb}
+}
+
+// tokenKey is the Redis key marking one issued token as live.
+func tokenKey(token string) string {
+	return "session:token:" + token
+}
+
+// Save registers a freshly issued token, tagged with its player for
+// debugging, and starts its TTL.
+func (t *TokenStore) Save(token, playerID string) {
+	if t == nil {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := t.rdb.Set(ctx, tokenKey(token), playerID, sessionTokenTTL).Err(); err != nil {
+		slog.Error("persist session token failed", "player_id", playerID, "error", err)
+	}
+}
+
+// Consume atomically invalidates a token and reports whether it was still
+// live. A token that was never saved, already consumed, or expired comes
+// back false. Redis errors fail open: a blip must not lock players out of
+// resuming, and the in-memory store still enforces single use per instance.
+func (t *TokenStore) Consume(token string) bool {
+	if t == nil {
+		return true
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := t.rdb.GetDel(ctx, tokenKey(token)).Err(); err != nil {
+		if err == redis.Nil {
+			return false
+		}
+		slog.Error("consume session token failed", "error", err)
+		return true
+	}
+	return true
+}
+
+// Drop invalidates a token early, once its match is over or the grace
+// window elapsed.
+func (t *TokenStore) Drop(token string) {
+	if t == nil {
+		return
+	}
+	ctx, cancel := busCtx()
+	defer cancel()
+	if err := t.rdb.Del(ctx, tokenKey(token)).Err(); err != nil {
+		slog.Error("drop session token failed", "error", err)
+	}
+}
@@ -0,0 +1,283 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ghazlabs/challenge-entry-level-1/internal/game"
+)
+
+// Machine-readable codes carried by ERROR payloads so clients can react
+// without parsing human-oriented text.
+const (
+	ErrCodeInvalidPayload = "INVALID_PAYLOAD"
+	ErrCodeNotInRoom      = "NOT_IN_ROOM"
+	ErrCodeRateLimited    = "RATE_LIMITED"
+	ErrCodeScoreRejected  = "SCORE_REJECTED"
+	ErrCodeBanned         = "BANNED"
+	ErrCodeUnknownType    = "UNKNOWN_TYPE"
+	// ErrCodeChallengeFailed covers rejected friend challenges: offline or
+	// busy friend, missing friendship, or no pending invite.
+	ErrCodeChallengeFailed = "CHALLENGE_FAILED"
+	// ErrCodeConnectionLimit marks a second connection for a player
+	// identity that is already online.
+	ErrCodeConnectionLimit = "CONNECTION_LIMIT"
+	// ErrCodePowerUpRejected marks a POWERUP_COLLECTED claim that doesn't
+	// match the room's spawn schedule.
+	ErrCodePowerUpRejected = "POWERUP_REJECTED"
+	// ErrCodeNameTaken marks a JOIN_QUEUE whose name is already used by a
+	// different registered player.
+	ErrCodeNameTaken = "NAME_TAKEN"
+	// ErrCodeStaleSeq marks a seq-stamped message whose number was already
+	// processed; the ERROR's lastSeq tells the client where to resume.
+	ErrCodeStaleSeq = "STALE_SEQ"
+	// ErrCodeBadSignature marks a score submission whose HMAC doesn't match
+	// the room's score key.
+	ErrCodeBadSignature = "BAD_SIGNATURE"
+	// ErrCodeMaintenance marks a request refused because the feature is
+	// switched off for a maintenance window.
+	ErrCodeMaintenance = "MAINTENANCE"
+	// ErrCodeAlreadyInQueue marks a JOIN_QUEUE from a client that is
+	// already waiting for a match.
+	ErrCodeAlreadyInQueue = "ALREADY_IN_QUEUE"
+	// ErrCodeAlreadyInMatch marks a JOIN_QUEUE from a client that is
+	// already in a room; the ERROR's roomId points back to it.
+	ErrCodeAlreadyInMatch = "ALREADY_IN_MATCH"
+)
+
+// errScoreJump marks a score update that moved backwards or jumped further
+// than maxScoreJump allows between two updates.
+var errScoreJump = errors.New("score jump exceeds allowed delta")
+
+// Limits enforced on client-supplied fields.
+const (
+	maxNameLength     = 20
+	maxPlayerIDLength = 64
+	// maxReportedScore is a sanity ceiling far above any legitimate run.
+	maxReportedScore = 1_000_000
+	// maxReportReason bounds the free-text reason of a REPORT_PLAYER.
+	maxReportReason = 500
+)
+
+// validName reports whether a display name uses only letters, digits,
+// spaces, underscores, and hyphens.
+func validName(name string) bool {
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+		case r == ' ', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// nameContainsProfanity reports whether a display name contains a word from
+// the chat denylist. Unlike chat, where offending words are masked, a
+// profane name is rejected outright.
+func nameContainsProfanity(name string) bool {
+	lower := strings.ToLower(name)
+	for _, bad := range profanity {
+		if strings.Contains(lower, bad) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDisplayName trims and checks a client-supplied display name,
+// returning the form that is stored and shown. It is exported so the REST
+// score ingestion endpoint enforces the same rules as JOIN_QUEUE.
+func ValidateDisplayName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	if len(name) > maxNameLength {
+		return "", fmt.Errorf("name exceeds %d characters", maxNameLength)
+	}
+	if !validName(name) {
+		return "", errors.New("name contains invalid characters")
+	}
+	if nameContainsProfanity(name) {
+		return "", errors.New("name is not allowed")
+	}
+	return name, nil
+}
+
+// Validate checks a JOIN_QUEUE payload. The name is trimmed in place, so the
+// validated form is also the one stored and displayed.
+func (p *JoinQueuePayload) Validate() error {
+	name, err := ValidateDisplayName(p.Name)
+	if err != nil {
+		return err
+	}
+	p.Name = name
+	if p.Mode != "" && p.Mode != ModeDuel && p.Mode != ModeRoyale && p.Mode != ModeTeams {
+		return fmt.Errorf("unknown mode %q", p.Mode)
+	}
+	if p.Format != "" && p.Format != FormatSingle && p.Format != FormatBestOf3 && p.Format != FormatTimed {
+		return fmt.Errorf("unknown format %q", p.Format)
+	}
+	if len(p.PlayerID) > maxPlayerIDLength {
+		return fmt.Errorf("playerId exceeds %d characters", maxPlayerIDLength)
+	}
+	return nil
+}
+
+// Validate checks a PRACTICE payload; the name rules match JOIN_QUEUE.
+func (p *PracticePayload) Validate() error {
+	name, err := ValidateDisplayName(p.Name)
+	if err != nil {
+		return err
+	}
+	p.Name = name
+	if p.Difficulty != "" {
+		if _, ok := botTiers[p.Difficulty]; !ok {
+			return fmt.Errorf("unknown difficulty %q", p.Difficulty)
+		}
+	}
+	return nil
+}
+
+// Validate checks a JOIN_CHALLENGE payload; the name rules match JOIN_QUEUE.
+func (p *JoinChallengePayload) Validate() error {
+	name, err := ValidateDisplayName(p.Name)
+	if err != nil {
+		return err
+	}
+	p.Name = name
+	if len(p.PlayerID) > maxPlayerIDLength {
+		return fmt.Errorf("playerId exceeds %d characters", maxPlayerIDLength)
+	}
+	return nil
+}
+
+// Validate checks a REPORT_PLAYER payload. The reason is trimmed in place.
+func (p *ReportPlayerPayload) Validate() error {
+	if p.PlayerID == "" {
+		return errors.New("playerId is required")
+	}
+	if len(p.PlayerID) > maxPlayerIDLength {
+		return fmt.Errorf("playerId exceeds %d characters", maxPlayerIDLength)
+	}
+	p.Reason = strings.TrimSpace(p.Reason)
+	if p.Reason == "" {
+		return errors.New("reason is required")
+	}
+	if len(p.Reason) > maxReportReason {
+		return fmt.Errorf("reason exceeds %d characters", maxReportReason)
+	}
+	return nil
+}
+
+// Bounds on private-match settings; generous enough for fun variants while
+// keeping the anti-cheat ceiling meaningful.
+const (
+	minSettingScale = 0.5
+	maxSettingScale = 2.0
+	maxHandicap     = 1000
+)
+
+// Validate checks custom match settings from a CHALLENGE_FRIEND payload.
+func (s *MatchSettings) Validate() error {
+	if s.SpeedMultiplier != 0 && (s.SpeedMultiplier < minSettingScale || s.SpeedMultiplier > maxSettingScale) {
+		return fmt.Errorf("speedMultiplier must be in %g..%g", minSettingScale, maxSettingScale)
+	}
+	if s.ObstacleDensity != 0 && (s.ObstacleDensity < minSettingScale || s.ObstacleDensity > maxSettingScale) {
+		return fmt.Errorf("obstacleDensity must be in %g..%g", minSettingScale, maxSettingScale)
+	}
+	for id, h := range s.Handicaps {
+		if h < 0 || h > maxHandicap {
+			return fmt.Errorf("handicap for %s must be in 0..%d", id, maxHandicap)
+		}
+	}
+	return nil
+}
+
+// Validate checks a CHALLENGE_FRIEND payload; custom settings, when
+// present, check themselves.
+func (p *ChallengeFriendPayload) Validate() error {
+	if p.FriendID == "" {
+		return errors.New("friendId is required")
+	}
+	if p.Settings != nil {
+		return p.Settings.Validate()
+	}
+	return nil
+}
+
+// Validate checks an ACCEPT_INVITE payload.
+func (p *AcceptInvitePayload) Validate() error {
+	if p.FromID == "" {
+		return errors.New("fromId is required")
+	}
+	return nil
+}
+
+// Validate checks a SPECTATE payload.
+func (p *SpectatePayload) Validate() error {
+	if p.RoomID == "" {
+		return errors.New("roomId is required")
+	}
+	return nil
+}
+
+// Validate checks an UPDATE_SCORE payload.
+func (p *UpdateScorePayload) Validate() error {
+	if p.Score < 0 || p.Score > maxReportedScore {
+		return fmt.Errorf("score %d out of range", p.Score)
+	}
+	return nil
+}
+
+// Validate checks a PLAYER_DIED payload.
+func (p *PlayerDiedPayload) Validate() error {
+	if p.Score < 0 || p.Score > maxReportedScore {
+		return fmt.Errorf("score %d out of range", p.Score)
+	}
+	return nil
+}
+
+// Validate checks a PLAYER_INPUT payload.
+func (p *PlayerInputPayload) Validate() error {
+	if p.T < 0 {
+		return fmt.Errorf("timestamp %d out of range", p.T)
+	}
+	switch game.InputKind(p.Action) {
+	case game.InputJump, game.InputDuck, game.InputUnduck:
+		return nil
+	}
+	return fmt.Errorf("unknown action %q", p.Action)
+}
+
+// Validate checks a POSITION_UPDATE payload; the bounds are generous sanity
+// limits, not gameplay rules.
+func (p *PositionUpdatePayload) Validate() error {
+	if p.X < 0 || p.X > 1e7 {
+		return fmt.Errorf("x %g out of range", p.X)
+	}
+	if p.Y < 0 || p.Y > 10_000 {
+		return fmt.Errorf("y %g out of range", p.Y)
+	}
+	return nil
+}
+
+// Validate checks a CHAT payload.
+func (p *ChatPayload) Validate() error {
+	if p.Text == "" {
+		return errors.New("text is required")
+	}
+	return nil
+}
+
+// Validate checks an EMOTE payload.
+func (p *EmotePayload) Validate() error {
+	if !allowedEmotes[p.Emote] {
+		return fmt.Errorf("unknown emote %q", p.Emote)
+	}
+	return nil
+}
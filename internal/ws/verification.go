@@ -0,0 +1,52 @@
+package ws
+
+import "time"
+
+// verificationArchiveSize caps the in-memory archive of finished matches
+// kept for fairness verification; the oldest record is evicted first.
+const verificationArchiveSize = 512
+
+// MatchVerification is the fairness record of one finished match: every seed
+// that was played, in round order, plus how long the match ran. The obstacle
+// layout every participant received derives deterministically from the seed,
+// so publishing the seeds lets anyone — tournament referees, suspicious
+// players — re-derive the schedule and confirm both sides saw the same game.
+type MatchVerification struct {
+	RoomID string `json:"roomId"`
+	Format string `json:"format"`
+	// Seeds lists the seed of every round played, in order.
+	Seeds []int64 `json:"seeds"`
+	// DurationMs is total play time across all rounds.
+	DurationMs int64     `json:"durationMs"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// archiveVerification records a resolved match's seeds for later
+// verification. Matches that never left the READY handshake have nothing to
+// verify and are skipped. Caller must hold m.mu.
+func (m *Matchmaker) archiveVerification(roomID string, meta *matchMeta) {
+	if meta.startedAt.IsZero() {
+		return
+	}
+	m.verifications[roomID] = MatchVerification{
+		RoomID:     roomID,
+		Format:     meta.format,
+		Seeds:      meta.seeds,
+		DurationMs: time.Since(meta.startedAt).Milliseconds(),
+		FinishedAt: time.Now(),
+	}
+	m.verifyOrder = append(m.verifyOrder, roomID)
+	if len(m.verifyOrder) > verificationArchiveSize {
+		delete(m.verifications, m.verifyOrder[0])
+		m.verifyOrder = m.verifyOrder[1:]
+	}
+}
+
+// Verification returns the archived fairness record of a finished match; ok
+// is false for unknown rooms and records already evicted from the archive.
+func (m *Matchmaker) Verification(roomID string) (MatchVerification, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.verifications[roomID]
+	return v, ok
+}
@@ -0,0 +1,99 @@
+package ws
+
+// Tie-breaker policies for matches whose top score is shared. The default
+// declares a draw — which single-round formats turn into sudden-death
+// overtime — while the alternatives split the leaders by how the tied score
+// was earned.
+const (
+	// TieBreakNone leaves a shared top score as a draw.
+	TieBreakNone = "none"
+	// TieBreakSurvival gives a tied match to whoever stayed alive longer.
+	TieBreakSurvival = "survival"
+	// TieBreakFirstToScore gives a tied match to whoever reached the shared
+	// top score first.
+	TieBreakFirstToScore = "first_to_score"
+)
+
+// tieBreaker is the active policy, set from config at startup.
+var tieBreaker = TieBreakNone
+
+// decideWinner resolves a finished room's winner: the unique max-score
+// holder wins outright, a shared top score goes through the configured
+// tie-breaker, and only a tie the policy cannot split either stands as a
+// draw. It collects every max-score holder first, so a tie between early
+// players is forgotten the moment a later strictly-higher score appears.
+func decideWinner(clients []*Client) (winnerID string, draw bool) {
+	var leaders []*Client
+	best := 0
+	for _, c := range clients {
+		score := c.currentScore()
+		switch {
+		case len(leaders) == 0 || score > best:
+			leaders = []*Client{c}
+			best = score
+		case score == best:
+			leaders = append(leaders, c)
+		}
+	}
+	switch tieBreaker {
+	case TieBreakSurvival:
+		leaders = filterLeaders(leaders, outlasts)
+	case TieBreakFirstToScore:
+		leaders = filterLeaders(leaders, scoredEarlier)
+	}
+	switch len(leaders) {
+	case 0:
+		return "", false
+	case 1:
+		return leaders[0].ID, false
+	default:
+		return "", true
+	}
+}
+
+// filterLeaders keeps the leaders that no other leader beats under better,
+// so an undecidable comparison keeps everyone and the tie stands.
+func filterLeaders(leaders []*Client, better func(a, b *Client) bool) []*Client {
+	if len(leaders) < 2 {
+		return leaders
+	}
+	out := []*Client{leaders[0]}
+	for _, c := range leaders[1:] {
+		switch {
+		case better(c, out[0]):
+			out = append(out[:0], c)
+		case !better(out[0], c):
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// outlasts reports whether a stayed alive strictly longer than b. A zero
+// diedAt means the player was still running when the match ended and
+// outlasts any recorded death.
+func outlasts(a, b *Client) bool {
+	_, aDied := a.matchTimes()
+	_, bDied := b.matchTimes()
+	if aDied.IsZero() {
+		return !bDied.IsZero()
+	}
+	if bDied.IsZero() {
+		return false
+	}
+	return aDied.After(bDied)
+}
+
+// scoredEarlier reports whether a reached its final score strictly before
+// b. A zero scoredAt means the score never moved and is never earlier.
+func scoredEarlier(a, b *Client) bool {
+	aScored, _ := a.matchTimes()
+	bScored, _ := b.matchTimes()
+	if aScored.IsZero() {
+		return false
+	}
+	if bScored.IsZero() {
+		return true
+	}
+	return aScored.Before(bScored)
+}
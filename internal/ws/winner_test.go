@@ -0,0 +1,68 @@
+package ws
+
+import (
+	"testing"
+	"time"
+)
+
+// tieClient builds a client for winner tests. diedSec and scoredSec are
+// offsets in seconds from a fixed base; zero leaves the timestamp unset.
+func tieClient(id string, score, diedSec, scoredSec int) *Client {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(id)
+	c.Score = score
+	if diedSec != 0 {
+		c.diedAt = base.Add(time.Duration(diedSec) * time.Second)
+	}
+	if scoredSec != 0 {
+		c.scoredAt = base.Add(time.Duration(scoredSec) * time.Second)
+	}
+	return c
+}
+
+func TestDecideWinner(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  string
+		clients []*Client
+		winner  string
+		draw    bool
+	}{
+		{"two players, higher wins", TieBreakNone,
+			[]*Client{tieClient("p1", 4, 0, 0), tieClient("p2", 9, 0, 0)}, "p2", false},
+		{"order does not matter", TieBreakNone,
+			[]*Client{tieClient("p1", 9, 0, 0), tieClient("p2", 4, 0, 0)}, "p1", false},
+		{"two tied is a draw", TieBreakNone,
+			[]*Client{tieClient("p1", 7, 0, 0), tieClient("p2", 7, 0, 0)}, "", true},
+		{"early tie forgotten by later higher score", TieBreakNone,
+			[]*Client{tieClient("p1", 4, 0, 0), tieClient("p2", 4, 0, 0), tieClient("p3", 9, 0, 0)}, "p3", false},
+		{"tie at the max among three", TieBreakNone,
+			[]*Client{tieClient("p1", 9, 0, 0), tieClient("p2", 4, 0, 0), tieClient("p3", 9, 0, 0)}, "", true},
+		{"tie below the max is no draw", TieBreakNone,
+			[]*Client{tieClient("p1", 9, 0, 0), tieClient("p2", 4, 0, 0), tieClient("p3", 4, 0, 0)}, "p1", false},
+		{"survival: later death wins the tie", TieBreakSurvival,
+			[]*Client{tieClient("p1", 7, 10, 0), tieClient("p2", 7, 30, 0)}, "p2", false},
+		{"survival: still running outlasts any death", TieBreakSurvival,
+			[]*Client{tieClient("p1", 7, 30, 0), tieClient("p2", 7, 0, 0)}, "p2", false},
+		{"survival: same death instant stays a draw", TieBreakSurvival,
+			[]*Client{tieClient("p1", 7, 10, 0), tieClient("p2", 7, 10, 0)}, "", true},
+		{"survival ignores non-leaders", TieBreakSurvival,
+			[]*Client{tieClient("p1", 7, 10, 0), tieClient("p2", 3, 99, 0), tieClient("p3", 7, 20, 0), tieClient("p4", 5, 99, 0)}, "p3", false},
+		{"first to score: earlier wins the tie", TieBreakFirstToScore,
+			[]*Client{tieClient("p1", 7, 0, 20), tieClient("p2", 7, 0, 5)}, "p2", false},
+		{"first to score: unset timestamp never wins", TieBreakFirstToScore,
+			[]*Client{tieClient("p1", 0, 0, 0), tieClient("p2", 0, 0, 0)}, "", true},
+		{"four-way tie split by first to score", TieBreakFirstToScore,
+			[]*Client{tieClient("p1", 7, 0, 8), tieClient("p2", 7, 0, 3), tieClient("p3", 7, 0, 12), tieClient("p4", 7, 0, 5)}, "p2", false},
+		{"no clients", TieBreakNone, nil, "", false},
+	}
+	for _, tc := range cases {
+		prev := tieBreaker
+		tieBreaker = tc.policy
+		winner, draw := decideWinner(tc.clients)
+		tieBreaker = prev
+		if winner != tc.winner || draw != tc.draw {
+			t.Errorf("%s: decideWinner = (%q, %v), want (%q, %v)", tc.name, winner, draw, tc.winner, tc.draw)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+// Dino Duel frontend: a Chrome Dino clone where both players face the same
+// obstacle schedule, streamed by the server in OBSTACLE_SCHEDULE batches. The
+// server is the source of truth for matchmaking, obstacles, opponent state
+// and the leaderboard.
+
+const $ = (id) => document.getElementById(id);
+const canvas = $("canvas");
+const ctx = canvas.getContext("2d");
+
+const GROUND_Y = 170;
+const BASE_POINTS_PER_SEC = 10;
+const SPEED_RAMP_PER_SEC = 0.012;
+const MAX_SPEED_MULT = 2.5;
+
+let socket = null;
+let state = null;
+let pendingMatch = null;
+// myId is our own player ID, derived from GAME_START so GAME_OVER's winnerId
+// can be told apart from the opponent's.
+let myId = null;
+let pendingStart = null;
+// serverOffset estimates (server clock - local clock) in ms, from TIME_SYNC.
+let serverOffset = 0;
+// scoreSeq numbers score submissions; the server dedups resends and the
+// room's score key signs (roomId, score, seq) so scores can't be forged.
+let scoreSeq = 0;
+let scoreKey = null;
+// obstacleSchedule accumulates the server's OBSTACLE_SCHEDULE batches for the
+// current round: entries of {kind, at} with `at` in ms since the run started.
+let obstacleSchedule = [];
+
+const OBSTACLE_KINDS = { cactus_small: 0, cactus_large: 1, pterodactyl: 2 };
+
+// connect opens the socket and sends joinMessage (JOIN_QUEUE or PRACTICE)
+// once the clock handshake is on its way.
+function connect(joinMessage) {
+  const proto = location.protocol === "https:" ? "wss:" : "ws:";
+  // This frontend speaks wire protocol v1 (it still reads the legacy
+  // `opponent` field from GAME_START); the server may also offer newer ones.
+  socket = new WebSocket(`${proto}//${location.host}/ws`, ["dino.v1"]);
+  socket.onopen = () => {
+    socket.send(JSON.stringify({
+      type: "TIME_SYNC",
+      payload: { clientTime: Date.now() },
+    }));
+    socket.send(JSON.stringify(joinMessage));
+    $("status").textContent = joinMessage.type === "PRACTICE"
+      ? "Starting practice…"
+      : "Waiting for an opponent…";
+  };
+  socket.onmessage = (ev) => handleMessage(JSON.parse(ev.data));
+  socket.onclose = () => { $("status").textContent = "Disconnected."; };
+}
+
+function handleMessage(msg) {
+  switch (msg.type) {
+    case "GAME_START":
+      prepareGame(msg.payload);
+      break;
+    case "TIME_SYNC": {
+      // Offset assumes a symmetric path: half the RTT each way.
+      const now = Date.now();
+      const rtt = now - msg.payload.clientTime;
+      serverOffset = msg.payload.serverTime + rtt / 2 - now;
+      break;
+    }
+    case "COUNTDOWN":
+      $("status").textContent = msg.payload.count;
+      // All clients begin at the same server timestamp.
+      if (pendingStart === null) {
+        const delay = msg.payload.startAt - serverOffset - Date.now();
+        pendingStart = setTimeout(beginRun, Math.max(0, delay));
+      }
+      break;
+    case "MATCH_CANCELLED":
+      clearTimeout(pendingStart);
+      pendingStart = null;
+      state = null;
+      $("menu").hidden = false;
+      $("game").hidden = true;
+      $("status").textContent = "Match cancelled: " + msg.payload.reason;
+      break;
+    case "OPPONENT_UPDATE":
+      $("opp-score").textContent = msg.payload.score;
+      break;
+    case "OPPONENT_POSITION":
+      if (state) state.ghost = msg.payload;
+      break;
+    case "OPPONENT_DIED":
+      $("opp-score").textContent = msg.payload.score;
+      break;
+    case "OBSTACLE_SCHEDULE":
+      obstacleSchedule.push(...msg.payload.obstacles);
+      break;
+    case "ROUND_OVER":
+      // Next round of a best-of-3: adopt the fresh seed and ready up again.
+      // The schedule for the new seed arrives right after this message.
+      pendingMatch.seed = msg.payload.nextSeed;
+      obstacleSchedule = [];
+      pendingStart = null;
+      $("result").hidden = false;
+      $("result").textContent = `Round ${msg.payload.round} over — next round soon…`;
+      send("READY", null);
+      break;
+    case "GAME_OVER":
+      finishGame(msg.payload);
+      break;
+    case "NOTICE":
+      $("status").textContent = msg.payload.message;
+      break;
+    case "ERROR":
+      console.warn("server:", msg.payload.message);
+      break;
+  }
+}
+
+// prepareGame acknowledges the match and waits for the server countdown; the
+// run itself begins in beginRun at the shared start timestamp.
+function prepareGame(p) {
+  pendingMatch = p;
+  pendingStart = null;
+  obstacleSchedule = [];
+  // players includes us; the opponent field names the other side.
+  myId = (p.players.find((pl) => pl.id !== p.opponent.id) || {}).id;
+  scoreKey = crypto.subtle.importKey(
+    "raw", new TextEncoder().encode(p.scoreKey),
+    { name: "HMAC", hash: "SHA-256" }, false, ["sign"],
+  );
+  $("menu").hidden = true;
+  $("game").hidden = false;
+  $("result").hidden = true;
+  $("status").textContent = "Get ready…";
+  send("READY", null);
+}
+
+function beginRun() {
+  const p = pendingMatch;
+  pendingStart = null;
+  $("result").hidden = true;
+  $("status").textContent = "";
+  $("opponent-label").innerHTML = `${p.opponent.name}: <b id="opp-score">0</b>`;
+
+  state = {
+    roomId: p.roomId,
+    startedAt: performance.now(),
+    lastFrame: performance.now(),
+    lastSent: 0,
+    score: 0,
+    dinoY: 0,
+    dinoVel: 0,
+    obstacles: [],
+    nextObstacle: 0, // index of the next schedule entry to spawn
+    alive: true,
+    ghost: null,
+    lastPosSent: 0,
+  };
+  requestAnimationFrame(frame);
+}
+
+function jump() {
+  if (state && state.alive && state.dinoY === 0) state.dinoVel = 520;
+}
+
+document.addEventListener("keydown", (e) => {
+  if (e.code === "Space" || e.code === "ArrowUp") { e.preventDefault(); jump(); }
+});
+canvas.addEventListener("pointerdown", jump);
+
+function frame(now) {
+  if (!state) return;
+  const dt = Math.min((now - state.lastFrame) / 1000, 0.05);
+  state.lastFrame = now;
+  const elapsed = (now - state.startedAt) / 1000;
+  const speed = Math.min(MAX_SPEED_MULT, 1 + SPEED_RAMP_PER_SEC * elapsed);
+
+  if (state.alive) {
+    state.score += BASE_POINTS_PER_SEC * speed * dt;
+
+    // physics
+    state.dinoVel -= 1600 * dt;
+    state.dinoY = Math.max(0, state.dinoY + state.dinoVel * dt);
+    if (state.dinoY === 0) state.dinoVel = 0;
+
+    // obstacles scroll left; spawns come off the server's schedule
+    const scroll = 240 * speed * dt;
+    state.obstacles.forEach((o) => { o.x -= scroll; });
+    state.obstacles = state.obstacles.filter((o) => o.x > -40);
+    const elapsedMs = now - state.startedAt;
+    while (state.nextObstacle < obstacleSchedule.length &&
+           obstacleSchedule[state.nextObstacle].at <= elapsedMs) {
+      const spawn = obstacleSchedule[state.nextObstacle++];
+      state.obstacles.push({ x: canvas.width, kind: OBSTACLE_KINDS[spawn.kind] ?? 0 });
+    }
+
+    // collision
+    const dinoBox = { x: 60, y: GROUND_Y - 40 - state.dinoY, w: 30, h: 40 };
+    for (const o of state.obstacles) {
+      const box = obstacleBox(o);
+      if (dinoBox.x < box.x + box.w && dinoBox.x + dinoBox.w > box.x &&
+          dinoBox.y < box.y + box.h && dinoBox.y + dinoBox.h > box.y) {
+        die();
+        break;
+      }
+    }
+
+    // report score at most twice a second
+    if (now - state.lastSent > 500 && state.alive) {
+      state.lastSent = now;
+      sendScore("UPDATE_SCORE", Math.floor(state.score));
+    }
+
+    // share our position ~10 times a second for the opponent's ghost
+    if (now - state.lastPosSent > 100) {
+      state.lastPosSent = now;
+      send("POSITION_UPDATE", { x: 0, y: state.dinoY, ducking: false });
+    }
+  }
+
+  draw();
+  $("my-score").textContent = Math.floor(state.score);
+  requestAnimationFrame(frame);
+}
+
+function obstacleBox(o) {
+  if (o.kind === 2) return { x: o.x, y: GROUND_Y - 95, w: 40, h: 28 }; // pterodactyl
+  if (o.kind === 1) return { x: o.x, y: GROUND_Y - 55, w: 28, h: 55 }; // large cactus
+  return { x: o.x, y: GROUND_Y - 38, w: 20, h: 38 }; // small cactus
+}
+
+function draw() {
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+  ctx.strokeStyle = "#535353";
+  ctx.beginPath();
+  ctx.moveTo(0, GROUND_Y);
+  ctx.lineTo(canvas.width, GROUND_Y);
+  ctx.stroke();
+
+  // opponent's ghost dino, drawn behind our own
+  if (state.ghost) {
+    ctx.globalAlpha = 0.3;
+    ctx.fillStyle = "#607d8b";
+    ctx.fillRect(60, GROUND_Y - 40 - state.ghost.y, 30, 40);
+    ctx.globalAlpha = 1;
+  }
+
+  ctx.fillStyle = state.alive ? "#535353" : "#c0392b";
+  ctx.fillRect(60, GROUND_Y - 40 - state.dinoY, 30, 40);
+
+  ctx.fillStyle = "#2e7d32";
+  for (const o of state.obstacles) {
+    const b = obstacleBox(o);
+    ctx.fillRect(b.x, b.y, b.w, b.h);
+  }
+}
+
+// sendScore signs and sends one score submission with the room's key.
+async function sendScore(type, score) {
+  const st = state;
+  const seq = ++scoreSeq;
+  const key = await scoreKey;
+  const data = new TextEncoder().encode(`${st.roomId}|${score}|${seq}`);
+  const mac = new Uint8Array(await crypto.subtle.sign("HMAC", key, data));
+  const sig = [...mac].map((b) => b.toString(16).padStart(2, "0")).join("");
+  socket.send(JSON.stringify({ type, seq, payload: { score, sig } }));
+}
+
+function die() {
+  state.alive = false;
+  sendScore("PLAYER_DIED", Math.floor(state.score));
+  $("result").hidden = false;
+  $("result").textContent = "You crashed! Waiting for opponent…";
+}
+
+function finishGame(p) {
+  const r = $("result");
+  r.hidden = false;
+  const over = p.reason === "time_up" ? "Time's up!" : "Game over!";
+  if (!p.winnerId) r.textContent = `${over} It's a draw!`;
+  else if (p.winnerId === myId) r.textContent = `${over} You win!`;
+  else r.textContent = `${over} You lose.`;
+  state.alive = false;
+  pendingMatch = null;
+  loadLeaderboard();
+  setTimeout(() => {
+    $("menu").hidden = false;
+    $("game").hidden = true;
+    $("status").textContent = "";
+    state = null;
+  }, 4000);
+}
+
+function send(type, payload) {
+  if (socket && socket.readyState === WebSocket.OPEN) {
+    socket.send(JSON.stringify({ type, payload }));
+  }
+}
+
+function renderBoard(entries) {
+  $("board").innerHTML = entries
+    .map((e) => {
+      const name = escapeHtml(e.playerName);
+      // Guests are marked so they can't pass for a registered player.
+      return `<li>${e.verified ? name : `guest: ${name}`} — ${e.score}</li>`;
+    })
+    .join("");
+}
+
+async function loadLeaderboard() {
+  try {
+    const res = await fetch("/api/leaderboard?limit=10");
+    const data = await res.json();
+    renderBoard(data.entries);
+  } catch (err) {
+    console.warn("leaderboard:", err);
+  }
+}
+
+// Live updates: the server pushes the new top 10 whenever a score cracks it.
+function watchLeaderboard() {
+  const events = new EventSource("/api/leaderboard/stream");
+  events.onmessage = (ev) => renderBoard(JSON.parse(ev.data));
+  events.onerror = () => {
+    // EventSource reconnects on its own; fall back to one fetch meanwhile.
+    loadLeaderboard();
+  };
+}
+
+function escapeHtml(s) {
+  const div = document.createElement("div");
+  div.textContent = s;
+  return div.innerHTML;
+}
+
+$("play").addEventListener("click", () => connect({
+  type: "JOIN_QUEUE",
+  payload: {
+    name: $("name").value.trim() || "anonymous",
+    format: $("format").value,
+  },
+}));
+// Practice matches are unranked: the bot starts right away and nothing is
+// written to the leaderboard.
+$("practice").addEventListener("click", () => connect({
+  type: "PRACTICE",
+  payload: {
+    name: $("name").value.trim() || "anonymous",
+    difficulty: $("difficulty").value,
+  },
+}));
+loadLeaderboard();
+watchLeaderboard();